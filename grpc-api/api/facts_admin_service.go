@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	factsv1 "github.com/cafo13/animal-facts/grpc-api/gen/facts/v1"
+	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/validation"
+)
+
+// FactsAdminRequiredScopes are the scopes of the methods of the FactsAdminService, they are the same as the scopes
+// of the matching endpoints of the internal API.
+var FactsAdminRequiredScopes = map[string]string{
+	factsv1.FactsAdminService_CreateFact_FullMethodName:    "create:fact",
+	factsv1.FactsAdminService_UpdateFact_FullMethodName:    "update:fact",
+	factsv1.FactsAdminService_DeleteFact_FullMethodName:    "delete:fact",
+	factsv1.FactsAdminService_ApproveFact_FullMethodName:   "approve:fact",
+	factsv1.FactsAdminService_UnapproveFact_FullMethodName: "unapprove:fact",
+	factsv1.FactsAdminService_ListAllFacts_FullMethodName:  "get:fact",
+}
+
+// FactsAdminService manages all facts with the handler of the internal API.
+type FactsAdminService struct {
+	factsv1.UnimplementedFactsAdminServiceServer
+	factsHandler *handler.FactsHandler
+}
+
+func NewFactsAdminService(factsHandler *handler.FactsHandler) *FactsAdminService {
+	return &FactsAdminService{factsHandler: factsHandler}
+}
+
+func (f *FactsAdminService) CreateFact(ctx context.Context, request *factsv1.CreateFactRequest) (*factsv1.CreateFactResponse, error) {
+	input := request.GetFact()
+	if input == nil {
+		return nil, status.Error(codes.InvalidArgument, "fact must be set")
+	}
+
+	id := primitive.NewObjectID()
+	err := f.factsHandler.WithContext(ctx).Create(&handler.Fact{
+		ID:       id,
+		Fact:     input.GetFact(),
+		Source:   input.GetSource(),
+		Animal:   input.GetAnimal(),
+		Tags:     input.GetTags(),
+		Language: input.GetLanguage(),
+		Approved: false,
+	})
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		return nil, invalidFact(validationErrs)
+	} else if err != nil {
+		return nil, internalError(err)
+	}
+
+	return &factsv1.CreateFactResponse{Id: id.Hex()}, nil
+}
+
+func (f *FactsAdminService) UpdateFact(ctx context.Context, request *factsv1.UpdateFactRequest) (*emptypb.Empty, error) {
+	id, err := parseID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	input := request.GetFact()
+	if input == nil {
+		return nil, status.Error(codes.InvalidArgument, "fact must be set")
+	}
+
+	err = f.factsHandler.WithContext(ctx).Update(&handler.Fact{
+		ID:       id,
+		Fact:     input.GetFact(),
+		Source:   input.GetSource(),
+		Animal:   input.GetAnimal(),
+		Tags:     input.GetTags(),
+		Language: input.GetLanguage(),
+	})
+	return empty(request.GetId(), err)
+}
+
+func (f *FactsAdminService) DeleteFact(ctx context.Context, request *factsv1.DeleteFactRequest) (*emptypb.Empty, error) {
+	id, err := parseID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return empty(request.GetId(), f.factsHandler.WithContext(ctx).Delete(id))
+}
+
+func (f *FactsAdminService) ApproveFact(ctx context.Context, request *factsv1.ApproveFactRequest) (*emptypb.Empty, error) {
+	id, err := parseID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return empty(request.GetId(), f.factsHandler.WithContext(ctx).Approve(id))
+}
+
+func (f *FactsAdminService) UnapproveFact(ctx context.Context, request *factsv1.UnapproveFactRequest) (*emptypb.Empty, error) {
+	id, err := parseID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return empty(request.GetId(), f.factsHandler.WithContext(ctx).Unapprove(id))
+}
+
+func (f *FactsAdminService) ListAllFacts(ctx context.Context, request *factsv1.ListAllFactsRequest) (*factsv1.ListAllFactsResponse, error) {
+	var facts []*repository.Fact
+	var err error
+	if request.GetReviewQueue() {
+		facts, err = f.factsHandler.WithContext(ctx).GetReviewQueue()
+	} else {
+		facts, err = f.factsHandler.WithContext(ctx).GetAll()
+	}
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	response := &factsv1.ListAllFactsResponse{}
+	for _, fact := range facts {
+		response.Facts = append(response.Facts, mapAdminFact(fact))
+	}
+
+	return response, nil
+}
+
+// empty maps the result of a handler call that changes the fact with the ID to the response.
+func empty(id string, err error) (*emptypb.Empty, error) {
+	var validationErrs validation.Errors
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "fact with ID '%s' not found", id)
+	} else if errors.As(err, &validationErrs) {
+		return nil, invalidFact(validationErrs)
+	} else if err != nil {
+		return nil, internalError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// invalidFact is the InvalidArgument status of a fact that failed the validation, with the field errors as field
+// violations of the fact of the request.
+func invalidFact(validationErrs validation.Errors) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldError := range validationErrs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       "fact." + fieldError.Field,
+			Description: fieldError.Message,
+		})
+	}
+
+	invalid := status.New(codes.InvalidArgument, validationErrs.Error())
+	if withDetails, err := invalid.WithDetails(badRequest); err == nil {
+		invalid = withDetails
+	}
+
+	return invalid.Err()
+}
+
+func mapAdminFact(fact *repository.Fact) *factsv1.AdminFact {
+	return &factsv1.AdminFact{
+		Id:         fact.ID.Hex(),
+		Fact:       fact.Fact,
+		Source:     fact.Source,
+		Animal:     fact.Animal,
+		Tags:       fact.Tags,
+		Language:   fact.Language,
+		Approved:   fact.Approved,
+		ApprovedAt: timestamp(fact.ApprovedAt),
+		Flagged:    fact.Flagged,
+		Revision:   int32(fact.Revision),
+		CreatedAt:  timestamp(fact.CreatedAt),
+		CreatedBy:  fact.CreatedBy,
+		UpdatedAt:  timestamp(fact.UpdatedAt),
+		UpdatedBy:  fact.UpdatedBy,
+	}
+}
+
+// timestamp leaves zero times unset, like the approval time of facts that are not approved.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	factsv1 "github.com/cafo13/animal-facts/grpc-api/gen/facts/v1"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// FactsService serves the approved facts with the handler of the public API.
+type FactsService struct {
+	factsv1.UnimplementedFactsServiceServer
+	factsHandler *handler.FactsHandler
+}
+
+func NewFactsService(factsHandler *handler.FactsHandler) *FactsService {
+	return &FactsService{factsHandler: factsHandler}
+}
+
+func (f *FactsService) GetFact(ctx context.Context, request *factsv1.GetFactRequest) (*factsv1.Fact, error) {
+	id, err := parseID(request.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	fact, err := f.factsHandler.WithContext(ctx).Get(id)
+	if errors.Is(err, handler.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "fact with ID '%s' not found", request.GetId())
+	} else if err != nil {
+		return nil, internalError(err)
+	}
+
+	return mapFact(fact), nil
+}
+
+func (f *FactsService) GetRandomFact(ctx context.Context, request *factsv1.GetRandomFactRequest) (*factsv1.Fact, error) {
+	fact, err := f.factsHandler.WithContext(ctx).GetRandomApprovedMatching(mapFilter(request.GetFilter()))
+	if errors.Is(err, handler.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "no approved fact matches the filter")
+	} else if err != nil {
+		return nil, internalError(err)
+	}
+
+	return mapFact(fact), nil
+}
+
+func (f *FactsService) ListFacts(ctx context.Context, request *factsv1.ListFactsRequest) (*factsv1.ListFactsResponse, error) {
+	pageSize := int(request.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	} else if pageSize < 1 || pageSize > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageSize)
+	}
+
+	filter := mapFilter(request.GetFilter())
+	page, err := f.factsHandler.WithContext(ctx).ListApproved(handler.ListQuery{
+		Animal:         filter.Animal,
+		Tag:            filter.Tag,
+		Language:       filter.Language,
+		SortDescending: request.GetNewestFirst(),
+		Cursor:         request.GetPageToken(),
+		Limit:          pageSize,
+	})
+	if errors.Is(err, handler.ErrInvalidCursor) {
+		return nil, status.Error(codes.InvalidArgument, "page_token is not valid for this request")
+	} else if err != nil {
+		return nil, internalError(err)
+	}
+
+	response := &factsv1.ListFactsResponse{NextPageToken: page.NextCursor}
+	for _, fact := range page.Facts {
+		response.Facts = append(response.Facts, mapFact(fact))
+	}
+
+	return response, nil
+}
+
+func (f *FactsService) CountFacts(ctx context.Context, request *factsv1.CountFactsRequest) (*factsv1.CountFactsResponse, error) {
+	count, err := f.factsHandler.WithContext(ctx).GetFactsCountMatching(mapFilter(request.GetFilter()))
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return &factsv1.CountFactsResponse{Count: int32(count)}, nil
+}
+
+func mapFact(fact *handler.Fact) *factsv1.Fact {
+	return &factsv1.Fact{
+		Id:     fact.ID,
+		Fact:   fact.Fact,
+		Source: fact.Source,
+	}
+}
+
+func mapFilter(filter *factsv1.FactFilter) handler.FactFilter {
+	return handler.FactFilter{
+		Animal:   normalize(filter.GetAnimal()),
+		Tag:      normalize(filter.GetTag()),
+		Language: normalize(filter.GetLanguage()),
+	}
+}
+
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func parseID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, status.Error(codes.InvalidArgument, "id is not a valid object id in hex string format")
+	}
+
+	return objID, nil
+}
+
+// internalError logs the error and only returns a generic message, as internal errors should not be displayed to users.
+func internalError(err error) error {
+	log.Logger().WithError(err).Error("failed to handle grpc request")
+	return status.Error(codes.Internal, "internal error")
+}
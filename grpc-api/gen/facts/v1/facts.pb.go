@@ -0,0 +1,1581 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.0
+// source: facts/v1/facts.proto
+
+package factsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Fact struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Fact   string `protobuf:"bytes,2,opt,name=fact,proto3" json:"fact,omitempty"`
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+}
+
+func (x *Fact) Reset() {
+	*x = Fact{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Fact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fact) ProtoMessage() {}
+
+func (x *Fact) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fact.ProtoReflect.Descriptor instead.
+func (*Fact) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Fact) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Fact) GetFact() string {
+	if x != nil {
+		return x.Fact
+	}
+	return ""
+}
+
+func (x *Fact) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// FactFilter selects facts, filters with empty values are ignored.
+type FactFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Animal   string `protobuf:"bytes,1,opt,name=animal,proto3" json:"animal,omitempty"`
+	Tag      string `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	Language string `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+}
+
+func (x *FactFilter) Reset() {
+	*x = FactFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FactFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FactFilter) ProtoMessage() {}
+
+func (x *FactFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FactFilter.ProtoReflect.Descriptor instead.
+func (*FactFilter) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FactFilter) GetAnimal() string {
+	if x != nil {
+		return x.Animal
+	}
+	return ""
+}
+
+func (x *FactFilter) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *FactFilter) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type GetFactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetFactRequest) Reset() {
+	*x = GetFactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFactRequest) ProtoMessage() {}
+
+func (x *GetFactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFactRequest.ProtoReflect.Descriptor instead.
+func (*GetFactRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetFactRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetRandomFactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *FactFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *GetRandomFactRequest) Reset() {
+	*x = GetRandomFactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRandomFactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRandomFactRequest) ProtoMessage() {}
+
+func (x *GetRandomFactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRandomFactRequest.ProtoReflect.Descriptor instead.
+func (*GetRandomFactRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetRandomFactRequest) GetFilter() *FactFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListFactsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *FactFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// between 1 and 100, 20 if not set
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous response, empty for the first page
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// orders the facts by creation time with the newest first instead of the oldest first
+	NewestFirst bool `protobuf:"varint,4,opt,name=newest_first,json=newestFirst,proto3" json:"newest_first,omitempty"`
+}
+
+func (x *ListFactsRequest) Reset() {
+	*x = ListFactsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFactsRequest) ProtoMessage() {}
+
+func (x *ListFactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFactsRequest.ProtoReflect.Descriptor instead.
+func (*ListFactsRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListFactsRequest) GetFilter() *FactFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListFactsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListFactsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListFactsRequest) GetNewestFirst() bool {
+	if x != nil {
+		return x.NewestFirst
+	}
+	return false
+}
+
+type ListFactsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Facts []*Fact `protobuf:"bytes,1,rep,name=facts,proto3" json:"facts,omitempty"`
+	// empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *ListFactsResponse) Reset() {
+	*x = ListFactsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFactsResponse) ProtoMessage() {}
+
+func (x *ListFactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFactsResponse.ProtoReflect.Descriptor instead.
+func (*ListFactsResponse) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListFactsResponse) GetFacts() []*Fact {
+	if x != nil {
+		return x.Facts
+	}
+	return nil
+}
+
+func (x *ListFactsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type CountFactsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *FactFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *CountFactsRequest) Reset() {
+	*x = CountFactsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountFactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountFactsRequest) ProtoMessage() {}
+
+func (x *CountFactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountFactsRequest.ProtoReflect.Descriptor instead.
+func (*CountFactsRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CountFactsRequest) GetFilter() *FactFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type CountFactsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *CountFactsResponse) Reset() {
+	*x = CountFactsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountFactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountFactsResponse) ProtoMessage() {}
+
+func (x *CountFactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountFactsResponse.ProtoReflect.Descriptor instead.
+func (*CountFactsResponse) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CountFactsResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AdminFact struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Fact       string                 `protobuf:"bytes,2,opt,name=fact,proto3" json:"fact,omitempty"`
+	Source     string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Animal     string                 `protobuf:"bytes,4,opt,name=animal,proto3" json:"animal,omitempty"`
+	Tags       []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Language   string                 `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
+	Approved   bool                   `protobuf:"varint,7,opt,name=approved,proto3" json:"approved,omitempty"`
+	ApprovedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=approved_at,json=approvedAt,proto3" json:"approved_at,omitempty"`
+	Flagged    bool                   `protobuf:"varint,9,opt,name=flagged,proto3" json:"flagged,omitempty"`
+	Revision   int32                  `protobuf:"varint,10,opt,name=revision,proto3" json:"revision,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CreatedBy  string                 `protobuf:"bytes,12,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	UpdatedBy  string                 `protobuf:"bytes,14,opt,name=updated_by,json=updatedBy,proto3" json:"updated_by,omitempty"`
+}
+
+func (x *AdminFact) Reset() {
+	*x = AdminFact{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminFact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminFact) ProtoMessage() {}
+
+func (x *AdminFact) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminFact.ProtoReflect.Descriptor instead.
+func (*AdminFact) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AdminFact) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AdminFact) GetFact() string {
+	if x != nil {
+		return x.Fact
+	}
+	return ""
+}
+
+func (x *AdminFact) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *AdminFact) GetAnimal() string {
+	if x != nil {
+		return x.Animal
+	}
+	return ""
+}
+
+func (x *AdminFact) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AdminFact) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *AdminFact) GetApproved() bool {
+	if x != nil {
+		return x.Approved
+	}
+	return false
+}
+
+func (x *AdminFact) GetApprovedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ApprovedAt
+	}
+	return nil
+}
+
+func (x *AdminFact) GetFlagged() bool {
+	if x != nil {
+		return x.Flagged
+	}
+	return false
+}
+
+func (x *AdminFact) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *AdminFact) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AdminFact) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *AdminFact) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *AdminFact) GetUpdatedBy() string {
+	if x != nil {
+		return x.UpdatedBy
+	}
+	return ""
+}
+
+type FactInput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fact     string   `protobuf:"bytes,1,opt,name=fact,proto3" json:"fact,omitempty"`
+	Source   string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Animal   string   `protobuf:"bytes,3,opt,name=animal,proto3" json:"animal,omitempty"`
+	Tags     []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Language string   `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`
+}
+
+func (x *FactInput) Reset() {
+	*x = FactInput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FactInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FactInput) ProtoMessage() {}
+
+func (x *FactInput) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FactInput.ProtoReflect.Descriptor instead.
+func (*FactInput) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FactInput) GetFact() string {
+	if x != nil {
+		return x.Fact
+	}
+	return ""
+}
+
+func (x *FactInput) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FactInput) GetAnimal() string {
+	if x != nil {
+		return x.Animal
+	}
+	return ""
+}
+
+func (x *FactInput) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *FactInput) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type CreateFactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fact *FactInput `protobuf:"bytes,1,opt,name=fact,proto3" json:"fact,omitempty"`
+}
+
+func (x *CreateFactRequest) Reset() {
+	*x = CreateFactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFactRequest) ProtoMessage() {}
+
+func (x *CreateFactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFactRequest.ProtoReflect.Descriptor instead.
+func (*CreateFactRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateFactRequest) GetFact() *FactInput {
+	if x != nil {
+		return x.Fact
+	}
+	return nil
+}
+
+type CreateFactResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *CreateFactResponse) Reset() {
+	*x = CreateFactResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFactResponse) ProtoMessage() {}
+
+func (x *CreateFactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFactResponse.ProtoReflect.Descriptor instead.
+func (*CreateFactResponse) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateFactResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateFactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Fact *FactInput `protobuf:"bytes,2,opt,name=fact,proto3" json:"fact,omitempty"`
+}
+
+func (x *UpdateFactRequest) Reset() {
+	*x = UpdateFactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFactRequest) ProtoMessage() {}
+
+func (x *UpdateFactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFactRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFactRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateFactRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateFactRequest) GetFact() *FactInput {
+	if x != nil {
+		return x.Fact
+	}
+	return nil
+}
+
+type DeleteFactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteFactRequest) Reset() {
+	*x = DeleteFactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteFactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFactRequest) ProtoMessage() {}
+
+func (x *DeleteFactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFactRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFactRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteFactRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ApproveFactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ApproveFactRequest) Reset() {
+	*x = ApproveFactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApproveFactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveFactRequest) ProtoMessage() {}
+
+func (x *ApproveFactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveFactRequest.ProtoReflect.Descriptor instead.
+func (*ApproveFactRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ApproveFactRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UnapproveFactRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *UnapproveFactRequest) Reset() {
+	*x = UnapproveFactRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnapproveFactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnapproveFactRequest) ProtoMessage() {}
+
+func (x *UnapproveFactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnapproveFactRequest.ProtoReflect.Descriptor instead.
+func (*UnapproveFactRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UnapproveFactRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListAllFactsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// only returns the facts that are not approved yet or flagged because of open reports
+	ReviewQueue bool `protobuf:"varint,1,opt,name=review_queue,json=reviewQueue,proto3" json:"review_queue,omitempty"`
+}
+
+func (x *ListAllFactsRequest) Reset() {
+	*x = ListAllFactsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAllFactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAllFactsRequest) ProtoMessage() {}
+
+func (x *ListAllFactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAllFactsRequest.ProtoReflect.Descriptor instead.
+func (*ListAllFactsRequest) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListAllFactsRequest) GetReviewQueue() bool {
+	if x != nil {
+		return x.ReviewQueue
+	}
+	return false
+}
+
+type ListAllFactsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Facts []*AdminFact `protobuf:"bytes,1,rep,name=facts,proto3" json:"facts,omitempty"`
+}
+
+func (x *ListAllFactsResponse) Reset() {
+	*x = ListAllFactsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_facts_v1_facts_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAllFactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAllFactsResponse) ProtoMessage() {}
+
+func (x *ListAllFactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_facts_v1_facts_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAllFactsResponse.ProtoReflect.Descriptor instead.
+func (*ListAllFactsResponse) Descriptor() ([]byte, []int) {
+	return file_facts_v1_facts_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListAllFactsResponse) GetFacts() []*AdminFact {
+	if x != nil {
+		return x.Facts
+	}
+	return nil
+}
+
+var File_facts_v1_facts_proto protoreflect.FileDescriptor
+
+var file_facts_v1_facts_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x66, 0x61, 0x63, 0x74, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x14, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61,
+	0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d,
+	0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x42, 0x0a, 0x04, 0x46, 0x61,
+	0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x61, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x66, 0x61, 0x63, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x52,
+	0x0a, 0x0a, 0x46, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e,
+	0x69, 0x6d, 0x61, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61,
+	0x67, 0x65, 0x22, 0x20, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x50, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x52, 0x61, 0x6e, 0x64, 0x6f,
+	0x6d, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x06,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61,
+	0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0xab, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x46,
+	0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61, 0x6e,
+	0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x65, 0x77, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6e, 0x65, 0x77, 0x65, 0x73, 0x74, 0x46,
+	0x69, 0x72, 0x73, 0x74, 0x22, 0x6d, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x61, 0x63, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61,
+	0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x61, 0x63, 0x74, 0x52, 0x05, 0x66, 0x61, 0x63, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x22, 0x4d, 0x0a, 0x11, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x46, 0x61, 0x63, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61,
+	0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x46, 0x61, 0x63, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x22, 0x2a, 0x0a, 0x12, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x46, 0x61, 0x63, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xd2,
+	0x03, 0x0a, 0x09, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x46, 0x61, 0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x66, 0x61, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x61, 0x63, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6e, 0x69, 0x6d,
+	0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x3b, 0x0a, 0x0b,
+	0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x61,
+	0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x64, 0x41, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x6c, 0x61,
+	0x67, 0x67, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x66, 0x6c, 0x61, 0x67,
+	0x67, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x62, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x42, 0x79, 0x22, 0x7f, 0x0a, 0x09, 0x46, 0x61, 0x63, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x66, 0x61, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x66, 0x61, 0x63, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e,
+	0x69, 0x6d, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67,
+	0x75, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67,
+	0x75, 0x61, 0x67, 0x65, 0x22, 0x48, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x61,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x04, 0x66, 0x61, 0x63,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c,
+	0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x61, 0x63, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x04, 0x66, 0x61, 0x63, 0x74, 0x22, 0x24,
+	0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x58, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x61,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x33, 0x0a, 0x04, 0x66, 0x61, 0x63,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c,
+	0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46,
+	0x61, 0x63, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x04, 0x66, 0x61, 0x63, 0x74, 0x22, 0x23,
+	0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x22, 0x24, 0x0a, 0x12, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x46, 0x61,
+	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x26, 0x0a, 0x14, 0x55, 0x6e, 0x61,
+	0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x38, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x6c, 0x6c, 0x46, 0x61, 0x63, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x5f, 0x71, 0x75, 0x65, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x51, 0x75, 0x65, 0x75, 0x65, 0x22, 0x4d, 0x0a, 0x14, 0x4c,
+	0x69, 0x73, 0x74, 0x41, 0x6c, 0x6c, 0x46, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x05, 0x66, 0x61, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73,
+	0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x46,
+	0x61, 0x63, 0x74, 0x52, 0x05, 0x66, 0x61, 0x63, 0x74, 0x73, 0x32, 0xf3, 0x02, 0x0a, 0x0c, 0x46,
+	0x61, 0x63, 0x74, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4b, 0x0a, 0x07, 0x47,
+	0x65, 0x74, 0x46, 0x61, 0x63, 0x74, 0x12, 0x24, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61,
+	0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x12, 0x57, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x52,
+	0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x46, 0x61, 0x63, 0x74, 0x12, 0x2a, 0x2e, 0x61, 0x6e, 0x69, 0x6d,
+	0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61,
+	0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x63,
+	0x74, 0x12, 0x5c, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x61, 0x63, 0x74, 0x73, 0x12, 0x26,
+	0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x61, 0x63, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x46, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x5f, 0x0a, 0x0a, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x46, 0x61, 0x63, 0x74, 0x73, 0x12, 0x27, 0x2e,
+	0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x46, 0x61, 0x63, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x46, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x32, 0x9f, 0x04, 0x0a, 0x11, 0x46, 0x61, 0x63, 0x74, 0x73, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5f, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x46, 0x61, 0x63, 0x74, 0x12, 0x27, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e,
+	0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74,
+	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x46, 0x61, 0x63, 0x74, 0x12, 0x27, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61,
+	0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4d, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x46, 0x61, 0x63, 0x74, 0x12, 0x27, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4f, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65,
+	0x46, 0x61, 0x63, 0x74, 0x12, 0x28, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x53, 0x0a, 0x0d, 0x55, 0x6e, 0x61, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x46, 0x61, 0x63, 0x74, 0x12, 0x2a, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c,
+	0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x6e, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x46, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x65, 0x0a, 0x0c, 0x4c,
+	0x69, 0x73, 0x74, 0x41, 0x6c, 0x6c, 0x46, 0x61, 0x63, 0x74, 0x73, 0x12, 0x29, 0x2e, 0x61, 0x6e,
+	0x69, 0x6d, 0x61, 0x6c, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x6c, 0x6c, 0x46, 0x61, 0x63, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x2e, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x6c, 0x6c, 0x46, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x3e, 0x5a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x63, 0x61, 0x66, 0x6f, 0x31, 0x33, 0x2f, 0x61, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x2d, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x65,
+	0x6e, 0x2f, 0x66, 0x61, 0x63, 0x74, 0x73, 0x2f, 0x76, 0x31, 0x3b, 0x66, 0x61, 0x63, 0x74, 0x73,
+	0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_facts_v1_facts_proto_rawDescOnce sync.Once
+	file_facts_v1_facts_proto_rawDescData = file_facts_v1_facts_proto_rawDesc
+)
+
+func file_facts_v1_facts_proto_rawDescGZIP() []byte {
+	file_facts_v1_facts_proto_rawDescOnce.Do(func() {
+		file_facts_v1_facts_proto_rawDescData = protoimpl.X.CompressGZIP(file_facts_v1_facts_proto_rawDescData)
+	})
+	return file_facts_v1_facts_proto_rawDescData
+}
+
+var file_facts_v1_facts_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_facts_v1_facts_proto_goTypes = []any{
+	(*Fact)(nil),                  // 0: animalfacts.facts.v1.Fact
+	(*FactFilter)(nil),            // 1: animalfacts.facts.v1.FactFilter
+	(*GetFactRequest)(nil),        // 2: animalfacts.facts.v1.GetFactRequest
+	(*GetRandomFactRequest)(nil),  // 3: animalfacts.facts.v1.GetRandomFactRequest
+	(*ListFactsRequest)(nil),      // 4: animalfacts.facts.v1.ListFactsRequest
+	(*ListFactsResponse)(nil),     // 5: animalfacts.facts.v1.ListFactsResponse
+	(*CountFactsRequest)(nil),     // 6: animalfacts.facts.v1.CountFactsRequest
+	(*CountFactsResponse)(nil),    // 7: animalfacts.facts.v1.CountFactsResponse
+	(*AdminFact)(nil),             // 8: animalfacts.facts.v1.AdminFact
+	(*FactInput)(nil),             // 9: animalfacts.facts.v1.FactInput
+	(*CreateFactRequest)(nil),     // 10: animalfacts.facts.v1.CreateFactRequest
+	(*CreateFactResponse)(nil),    // 11: animalfacts.facts.v1.CreateFactResponse
+	(*UpdateFactRequest)(nil),     // 12: animalfacts.facts.v1.UpdateFactRequest
+	(*DeleteFactRequest)(nil),     // 13: animalfacts.facts.v1.DeleteFactRequest
+	(*ApproveFactRequest)(nil),    // 14: animalfacts.facts.v1.ApproveFactRequest
+	(*UnapproveFactRequest)(nil),  // 15: animalfacts.facts.v1.UnapproveFactRequest
+	(*ListAllFactsRequest)(nil),   // 16: animalfacts.facts.v1.ListAllFactsRequest
+	(*ListAllFactsResponse)(nil),  // 17: animalfacts.facts.v1.ListAllFactsResponse
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 19: google.protobuf.Empty
+}
+var file_facts_v1_facts_proto_depIdxs = []int32{
+	1,  // 0: animalfacts.facts.v1.GetRandomFactRequest.filter:type_name -> animalfacts.facts.v1.FactFilter
+	1,  // 1: animalfacts.facts.v1.ListFactsRequest.filter:type_name -> animalfacts.facts.v1.FactFilter
+	0,  // 2: animalfacts.facts.v1.ListFactsResponse.facts:type_name -> animalfacts.facts.v1.Fact
+	1,  // 3: animalfacts.facts.v1.CountFactsRequest.filter:type_name -> animalfacts.facts.v1.FactFilter
+	18, // 4: animalfacts.facts.v1.AdminFact.approved_at:type_name -> google.protobuf.Timestamp
+	18, // 5: animalfacts.facts.v1.AdminFact.created_at:type_name -> google.protobuf.Timestamp
+	18, // 6: animalfacts.facts.v1.AdminFact.updated_at:type_name -> google.protobuf.Timestamp
+	9,  // 7: animalfacts.facts.v1.CreateFactRequest.fact:type_name -> animalfacts.facts.v1.FactInput
+	9,  // 8: animalfacts.facts.v1.UpdateFactRequest.fact:type_name -> animalfacts.facts.v1.FactInput
+	8,  // 9: animalfacts.facts.v1.ListAllFactsResponse.facts:type_name -> animalfacts.facts.v1.AdminFact
+	2,  // 10: animalfacts.facts.v1.FactsService.GetFact:input_type -> animalfacts.facts.v1.GetFactRequest
+	3,  // 11: animalfacts.facts.v1.FactsService.GetRandomFact:input_type -> animalfacts.facts.v1.GetRandomFactRequest
+	4,  // 12: animalfacts.facts.v1.FactsService.ListFacts:input_type -> animalfacts.facts.v1.ListFactsRequest
+	6,  // 13: animalfacts.facts.v1.FactsService.CountFacts:input_type -> animalfacts.facts.v1.CountFactsRequest
+	10, // 14: animalfacts.facts.v1.FactsAdminService.CreateFact:input_type -> animalfacts.facts.v1.CreateFactRequest
+	12, // 15: animalfacts.facts.v1.FactsAdminService.UpdateFact:input_type -> animalfacts.facts.v1.UpdateFactRequest
+	13, // 16: animalfacts.facts.v1.FactsAdminService.DeleteFact:input_type -> animalfacts.facts.v1.DeleteFactRequest
+	14, // 17: animalfacts.facts.v1.FactsAdminService.ApproveFact:input_type -> animalfacts.facts.v1.ApproveFactRequest
+	15, // 18: animalfacts.facts.v1.FactsAdminService.UnapproveFact:input_type -> animalfacts.facts.v1.UnapproveFactRequest
+	16, // 19: animalfacts.facts.v1.FactsAdminService.ListAllFacts:input_type -> animalfacts.facts.v1.ListAllFactsRequest
+	0,  // 20: animalfacts.facts.v1.FactsService.GetFact:output_type -> animalfacts.facts.v1.Fact
+	0,  // 21: animalfacts.facts.v1.FactsService.GetRandomFact:output_type -> animalfacts.facts.v1.Fact
+	5,  // 22: animalfacts.facts.v1.FactsService.ListFacts:output_type -> animalfacts.facts.v1.ListFactsResponse
+	7,  // 23: animalfacts.facts.v1.FactsService.CountFacts:output_type -> animalfacts.facts.v1.CountFactsResponse
+	11, // 24: animalfacts.facts.v1.FactsAdminService.CreateFact:output_type -> animalfacts.facts.v1.CreateFactResponse
+	19, // 25: animalfacts.facts.v1.FactsAdminService.UpdateFact:output_type -> google.protobuf.Empty
+	19, // 26: animalfacts.facts.v1.FactsAdminService.DeleteFact:output_type -> google.protobuf.Empty
+	19, // 27: animalfacts.facts.v1.FactsAdminService.ApproveFact:output_type -> google.protobuf.Empty
+	19, // 28: animalfacts.facts.v1.FactsAdminService.UnapproveFact:output_type -> google.protobuf.Empty
+	17, // 29: animalfacts.facts.v1.FactsAdminService.ListAllFacts:output_type -> animalfacts.facts.v1.ListAllFactsResponse
+	20, // [20:30] is the sub-list for method output_type
+	10, // [10:20] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_facts_v1_facts_proto_init() }
+func file_facts_v1_facts_proto_init() {
+	if File_facts_v1_facts_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_facts_v1_facts_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Fact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*FactFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetFactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetRandomFactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ListFactsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ListFactsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*CountFactsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*CountFactsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*AdminFact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*FactInput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateFactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateFactResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateFactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteFactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*ApproveFactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*UnapproveFactRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAllFactsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_facts_v1_facts_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*ListAllFactsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_facts_v1_facts_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_facts_v1_facts_proto_goTypes,
+		DependencyIndexes: file_facts_v1_facts_proto_depIdxs,
+		MessageInfos:      file_facts_v1_facts_proto_msgTypes,
+	}.Build()
+	File_facts_v1_facts_proto = out.File
+	file_facts_v1_facts_proto_rawDesc = nil
+	file_facts_v1_facts_proto_goTypes = nil
+	file_facts_v1_facts_proto_depIdxs = nil
+}
@@ -0,0 +1,528 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v5.27.0
+// source: facts/v1/facts.proto
+
+package factsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	FactsService_GetFact_FullMethodName       = "/animalfacts.facts.v1.FactsService/GetFact"
+	FactsService_GetRandomFact_FullMethodName = "/animalfacts.facts.v1.FactsService/GetRandomFact"
+	FactsService_ListFacts_FullMethodName     = "/animalfacts.facts.v1.FactsService/ListFacts"
+	FactsService_CountFacts_FullMethodName    = "/animalfacts.facts.v1.FactsService/CountFacts"
+)
+
+// FactsServiceClient is the client API for FactsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FactsService provides the approved facts without authentication, like the public REST API.
+type FactsServiceClient interface {
+	GetFact(ctx context.Context, in *GetFactRequest, opts ...grpc.CallOption) (*Fact, error)
+	GetRandomFact(ctx context.Context, in *GetRandomFactRequest, opts ...grpc.CallOption) (*Fact, error)
+	ListFacts(ctx context.Context, in *ListFactsRequest, opts ...grpc.CallOption) (*ListFactsResponse, error)
+	CountFacts(ctx context.Context, in *CountFactsRequest, opts ...grpc.CallOption) (*CountFactsResponse, error)
+}
+
+type factsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFactsServiceClient(cc grpc.ClientConnInterface) FactsServiceClient {
+	return &factsServiceClient{cc}
+}
+
+func (c *factsServiceClient) GetFact(ctx context.Context, in *GetFactRequest, opts ...grpc.CallOption) (*Fact, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Fact)
+	err := c.cc.Invoke(ctx, FactsService_GetFact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsServiceClient) GetRandomFact(ctx context.Context, in *GetRandomFactRequest, opts ...grpc.CallOption) (*Fact, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Fact)
+	err := c.cc.Invoke(ctx, FactsService_GetRandomFact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsServiceClient) ListFacts(ctx context.Context, in *ListFactsRequest, opts ...grpc.CallOption) (*ListFactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFactsResponse)
+	err := c.cc.Invoke(ctx, FactsService_ListFacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsServiceClient) CountFacts(ctx context.Context, in *CountFactsRequest, opts ...grpc.CallOption) (*CountFactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountFactsResponse)
+	err := c.cc.Invoke(ctx, FactsService_CountFacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FactsServiceServer is the server API for FactsService service.
+// All implementations must embed UnimplementedFactsServiceServer
+// for forward compatibility
+//
+// FactsService provides the approved facts without authentication, like the public REST API.
+type FactsServiceServer interface {
+	GetFact(context.Context, *GetFactRequest) (*Fact, error)
+	GetRandomFact(context.Context, *GetRandomFactRequest) (*Fact, error)
+	ListFacts(context.Context, *ListFactsRequest) (*ListFactsResponse, error)
+	CountFacts(context.Context, *CountFactsRequest) (*CountFactsResponse, error)
+	mustEmbedUnimplementedFactsServiceServer()
+}
+
+// UnimplementedFactsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFactsServiceServer struct {
+}
+
+func (UnimplementedFactsServiceServer) GetFact(context.Context, *GetFactRequest) (*Fact, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFact not implemented")
+}
+func (UnimplementedFactsServiceServer) GetRandomFact(context.Context, *GetRandomFactRequest) (*Fact, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRandomFact not implemented")
+}
+func (UnimplementedFactsServiceServer) ListFacts(context.Context, *ListFactsRequest) (*ListFactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFacts not implemented")
+}
+func (UnimplementedFactsServiceServer) CountFacts(context.Context, *CountFactsRequest) (*CountFactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountFacts not implemented")
+}
+func (UnimplementedFactsServiceServer) mustEmbedUnimplementedFactsServiceServer() {}
+
+// UnsafeFactsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FactsServiceServer will
+// result in compilation errors.
+type UnsafeFactsServiceServer interface {
+	mustEmbedUnimplementedFactsServiceServer()
+}
+
+func RegisterFactsServiceServer(s grpc.ServiceRegistrar, srv FactsServiceServer) {
+	s.RegisterService(&FactsService_ServiceDesc, srv)
+}
+
+func _FactsService_GetFact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsServiceServer).GetFact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsService_GetFact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsServiceServer).GetFact(ctx, req.(*GetFactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsService_GetRandomFact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRandomFactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsServiceServer).GetRandomFact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsService_GetRandomFact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsServiceServer).GetRandomFact(ctx, req.(*GetRandomFactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsService_ListFacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsServiceServer).ListFacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsService_ListFacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsServiceServer).ListFacts(ctx, req.(*ListFactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsService_CountFacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountFactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsServiceServer).CountFacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsService_CountFacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsServiceServer).CountFacts(ctx, req.(*CountFactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FactsService_ServiceDesc is the grpc.ServiceDesc for FactsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FactsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "animalfacts.facts.v1.FactsService",
+	HandlerType: (*FactsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFact",
+			Handler:    _FactsService_GetFact_Handler,
+		},
+		{
+			MethodName: "GetRandomFact",
+			Handler:    _FactsService_GetRandomFact_Handler,
+		},
+		{
+			MethodName: "ListFacts",
+			Handler:    _FactsService_ListFacts_Handler,
+		},
+		{
+			MethodName: "CountFacts",
+			Handler:    _FactsService_CountFacts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "facts/v1/facts.proto",
+}
+
+const (
+	FactsAdminService_CreateFact_FullMethodName    = "/animalfacts.facts.v1.FactsAdminService/CreateFact"
+	FactsAdminService_UpdateFact_FullMethodName    = "/animalfacts.facts.v1.FactsAdminService/UpdateFact"
+	FactsAdminService_DeleteFact_FullMethodName    = "/animalfacts.facts.v1.FactsAdminService/DeleteFact"
+	FactsAdminService_ApproveFact_FullMethodName   = "/animalfacts.facts.v1.FactsAdminService/ApproveFact"
+	FactsAdminService_UnapproveFact_FullMethodName = "/animalfacts.facts.v1.FactsAdminService/UnapproveFact"
+	FactsAdminService_ListAllFacts_FullMethodName  = "/animalfacts.facts.v1.FactsAdminService/ListAllFacts"
+)
+
+// FactsAdminServiceClient is the client API for FactsAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FactsAdminService manages all facts like the internal REST API, every method needs a JWT in the authorization
+// metadata ("Bearer <token>") with the same scope as the matching REST endpoint.
+type FactsAdminServiceClient interface {
+	// requires the scope create:fact
+	CreateFact(ctx context.Context, in *CreateFactRequest, opts ...grpc.CallOption) (*CreateFactResponse, error)
+	// requires the scope update:fact
+	UpdateFact(ctx context.Context, in *UpdateFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// requires the scope delete:fact
+	DeleteFact(ctx context.Context, in *DeleteFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// requires the scope approve:fact
+	ApproveFact(ctx context.Context, in *ApproveFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// requires the scope unapprove:fact
+	UnapproveFact(ctx context.Context, in *UnapproveFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// requires the scope get:fact
+	ListAllFacts(ctx context.Context, in *ListAllFactsRequest, opts ...grpc.CallOption) (*ListAllFactsResponse, error)
+}
+
+type factsAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFactsAdminServiceClient(cc grpc.ClientConnInterface) FactsAdminServiceClient {
+	return &factsAdminServiceClient{cc}
+}
+
+func (c *factsAdminServiceClient) CreateFact(ctx context.Context, in *CreateFactRequest, opts ...grpc.CallOption) (*CreateFactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateFactResponse)
+	err := c.cc.Invoke(ctx, FactsAdminService_CreateFact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsAdminServiceClient) UpdateFact(ctx context.Context, in *UpdateFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FactsAdminService_UpdateFact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsAdminServiceClient) DeleteFact(ctx context.Context, in *DeleteFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FactsAdminService_DeleteFact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsAdminServiceClient) ApproveFact(ctx context.Context, in *ApproveFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FactsAdminService_ApproveFact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsAdminServiceClient) UnapproveFact(ctx context.Context, in *UnapproveFactRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FactsAdminService_UnapproveFact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *factsAdminServiceClient) ListAllFacts(ctx context.Context, in *ListAllFactsRequest, opts ...grpc.CallOption) (*ListAllFactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAllFactsResponse)
+	err := c.cc.Invoke(ctx, FactsAdminService_ListAllFacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FactsAdminServiceServer is the server API for FactsAdminService service.
+// All implementations must embed UnimplementedFactsAdminServiceServer
+// for forward compatibility
+//
+// FactsAdminService manages all facts like the internal REST API, every method needs a JWT in the authorization
+// metadata ("Bearer <token>") with the same scope as the matching REST endpoint.
+type FactsAdminServiceServer interface {
+	// requires the scope create:fact
+	CreateFact(context.Context, *CreateFactRequest) (*CreateFactResponse, error)
+	// requires the scope update:fact
+	UpdateFact(context.Context, *UpdateFactRequest) (*emptypb.Empty, error)
+	// requires the scope delete:fact
+	DeleteFact(context.Context, *DeleteFactRequest) (*emptypb.Empty, error)
+	// requires the scope approve:fact
+	ApproveFact(context.Context, *ApproveFactRequest) (*emptypb.Empty, error)
+	// requires the scope unapprove:fact
+	UnapproveFact(context.Context, *UnapproveFactRequest) (*emptypb.Empty, error)
+	// requires the scope get:fact
+	ListAllFacts(context.Context, *ListAllFactsRequest) (*ListAllFactsResponse, error)
+	mustEmbedUnimplementedFactsAdminServiceServer()
+}
+
+// UnimplementedFactsAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFactsAdminServiceServer struct {
+}
+
+func (UnimplementedFactsAdminServiceServer) CreateFact(context.Context, *CreateFactRequest) (*CreateFactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFact not implemented")
+}
+func (UnimplementedFactsAdminServiceServer) UpdateFact(context.Context, *UpdateFactRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFact not implemented")
+}
+func (UnimplementedFactsAdminServiceServer) DeleteFact(context.Context, *DeleteFactRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFact not implemented")
+}
+func (UnimplementedFactsAdminServiceServer) ApproveFact(context.Context, *ApproveFactRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveFact not implemented")
+}
+func (UnimplementedFactsAdminServiceServer) UnapproveFact(context.Context, *UnapproveFactRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnapproveFact not implemented")
+}
+func (UnimplementedFactsAdminServiceServer) ListAllFacts(context.Context, *ListAllFactsRequest) (*ListAllFactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAllFacts not implemented")
+}
+func (UnimplementedFactsAdminServiceServer) mustEmbedUnimplementedFactsAdminServiceServer() {}
+
+// UnsafeFactsAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FactsAdminServiceServer will
+// result in compilation errors.
+type UnsafeFactsAdminServiceServer interface {
+	mustEmbedUnimplementedFactsAdminServiceServer()
+}
+
+func RegisterFactsAdminServiceServer(s grpc.ServiceRegistrar, srv FactsAdminServiceServer) {
+	s.RegisterService(&FactsAdminService_ServiceDesc, srv)
+}
+
+func _FactsAdminService_CreateFact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsAdminServiceServer).CreateFact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsAdminService_CreateFact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsAdminServiceServer).CreateFact(ctx, req.(*CreateFactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsAdminService_UpdateFact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsAdminServiceServer).UpdateFact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsAdminService_UpdateFact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsAdminServiceServer).UpdateFact(ctx, req.(*UpdateFactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsAdminService_DeleteFact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsAdminServiceServer).DeleteFact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsAdminService_DeleteFact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsAdminServiceServer).DeleteFact(ctx, req.(*DeleteFactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsAdminService_ApproveFact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveFactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsAdminServiceServer).ApproveFact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsAdminService_ApproveFact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsAdminServiceServer).ApproveFact(ctx, req.(*ApproveFactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsAdminService_UnapproveFact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnapproveFactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsAdminServiceServer).UnapproveFact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsAdminService_UnapproveFact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsAdminServiceServer).UnapproveFact(ctx, req.(*UnapproveFactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FactsAdminService_ListAllFacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAllFactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FactsAdminServiceServer).ListAllFacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FactsAdminService_ListAllFacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FactsAdminServiceServer).ListAllFacts(ctx, req.(*ListAllFactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FactsAdminService_ServiceDesc is the grpc.ServiceDesc for FactsAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FactsAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "animalfacts.facts.v1.FactsAdminService",
+	HandlerType: (*FactsAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFact",
+			Handler:    _FactsAdminService_CreateFact_Handler,
+		},
+		{
+			MethodName: "UpdateFact",
+			Handler:    _FactsAdminService_UpdateFact_Handler,
+		},
+		{
+			MethodName: "DeleteFact",
+			Handler:    _FactsAdminService_DeleteFact_Handler,
+		},
+		{
+			MethodName: "ApproveFact",
+			Handler:    _FactsAdminService_ApproveFact_Handler,
+		},
+		{
+			MethodName: "UnapproveFact",
+			Handler:    _FactsAdminService_UnapproveFact_Handler,
+		},
+		{
+			MethodName: "ListAllFacts",
+			Handler:    _FactsAdminService_ListAllFacts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "facts/v1/facts.proto",
+}
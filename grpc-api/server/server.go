@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/cafo13/animal-facts/grpc-api/api"
+	factsv1 "github.com/cafo13/animal-facts/grpc-api/gen/facts/v1"
+	"github.com/cafo13/animal-facts/pkg/middleware"
+)
+
+// Server runs the gRPC services as service.Worker next to the HTTP API of the process.
+type Server struct {
+	port         int
+	grpcServer   *grpc.Server
+	healthServer *health.Server
+}
+
+func NewServer(port int, factsService *api.FactsService, factsAdminService *api.FactsAdminService, validateToken middleware.TokenValidator) *Server {
+	grpcServer := grpc.NewServer(
+		// all methods of the admin service need a token, methods without a scope are denied
+		grpc.ChainUnaryInterceptor(middleware.EnsureValidTokenGRPC(
+			validateToken,
+			[]string{factsv1.FactsAdminService_ServiceDesc.ServiceName},
+			api.FactsAdminRequiredScopes,
+		)),
+	)
+	factsv1.RegisterFactsServiceServer(grpcServer, factsService)
+	factsv1.RegisterFactsAdminServiceServer(grpcServer, factsAdminService)
+
+	healthServer := health.NewServer()
+	healthv1.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	return &Server{port: port, grpcServer: grpcServer, healthServer: healthServer}
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on grpc port %d", s.port)
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves the services on the listener until the context is done, then the services are reported as not serving
+// and the server stops after the running calls are finished.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	for service := range s.grpcServer.GetServiceInfo() {
+		s.healthServer.SetServingStatus(service, healthv1.HealthCheckResponse_SERVING)
+	}
+	s.healthServer.SetServingStatus("", healthv1.HealthCheckResponse_SERVING)
+
+	go func() {
+		<-ctx.Done()
+		s.healthServer.Shutdown()
+		s.grpcServer.GracefulStop()
+	}()
+
+	log.Logger().Infof("serving grpc on %s", listener.Addr())
+	err := s.grpcServer.Serve(listener)
+	if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return errors.Wrap(err, "failed to serve grpc")
+	}
+
+	return nil
+}
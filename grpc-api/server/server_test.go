@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/cafo13/animal-facts/grpc-api/api"
+	factsv1 "github.com/cafo13/animal-facts/grpc-api/gen/facts/v1"
+	internalhandler "github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/middleware"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	publichandler "github.com/cafo13/animal-facts/public-api/handler"
+)
+
+// validateTestToken accepts tokens that are the scopes of the user, "invalid" is rejected.
+func validateTestToken(ctx context.Context, token string) (interface{}, error) {
+	if token == "invalid" {
+		return nil, errors.New("token is invalid")
+	}
+
+	return &validator.ValidatedClaims{CustomClaims: &middleware.CustomClaims{Scope: token}}, nil
+}
+
+func newTestClient(t *testing.T, facts map[primitive.ObjectID]*repository.Fact) (*grpc.ClientConn, context.CancelFunc) {
+	t.Helper()
+
+	factsRepository := repository.NewMockFactsRepository(facts, false)
+	server := NewServer(
+		0,
+		api.NewFactsService(publichandler.NewFactsHandler(factsRepository)),
+		api.NewFactsAdminService(internalhandler.NewFactsHandler(factsRepository)),
+		validateTestToken,
+	)
+
+	listener := bufconn.Listen(1024 * 1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- server.Serve(ctx, listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error = %v", err)
+	}
+
+	return conn, func() {
+		conn.Close()
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() unexpected error = %v", err)
+		}
+	}
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_FactsService(t *testing.T) {
+	approvedID := primitive.NewObjectID()
+	unapprovedID := primitive.NewObjectID()
+	facts := map[primitive.ObjectID]*repository.Fact{
+		approvedID:   {ID: approvedID, Fact: "Whales sing.", Source: "https://factanimal.com/", Animal: "whale", Language: "en", Approved: true, CreatedAt: time.Now()},
+		unapprovedID: {ID: unapprovedID, Fact: "Cats sleep a lot.", Animal: "cat", Language: "en", CreatedAt: time.Now()},
+	}
+	conn, stop := newTestClient(t, facts)
+	defer stop()
+	client := factsv1.NewFactsServiceClient(conn)
+
+	fact, err := client.GetFact(context.Background(), &factsv1.GetFactRequest{Id: approvedID.Hex()})
+	if err != nil || fact.GetFact() != "Whales sing." {
+		t.Errorf("GetFact() = %v, %v, want the whale fact", fact, err)
+	}
+
+	_, err = client.GetFact(context.Background(), &factsv1.GetFactRequest{Id: "no-id"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetFact() error = %v, want InvalidArgument", err)
+	}
+
+	fact, err = client.GetRandomFact(context.Background(), &factsv1.GetRandomFactRequest{Filter: &factsv1.FactFilter{Animal: " Whale "}})
+	if err != nil || fact.GetId() != approvedID.Hex() {
+		t.Errorf("GetRandomFact() = %v, %v, want the whale fact", fact, err)
+	}
+
+	_, err = client.GetRandomFact(context.Background(), &factsv1.GetRandomFactRequest{Filter: &factsv1.FactFilter{Animal: "cat"}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetRandomFact() error = %v, want NotFound as the cat fact is not approved", err)
+	}
+
+	list, err := client.ListFacts(context.Background(), &factsv1.ListFactsRequest{})
+	if err != nil || len(list.GetFacts()) != 1 || list.GetNextPageToken() != "" {
+		t.Errorf("ListFacts() = %v, %v, want only the approved fact", list, err)
+	}
+
+	_, err = client.ListFacts(context.Background(), &factsv1.ListFactsRequest{PageSize: 101})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListFacts() error = %v, want InvalidArgument", err)
+	}
+
+	count, err := client.CountFacts(context.Background(), &factsv1.CountFactsRequest{})
+	if err != nil || count.GetCount() != 1 {
+		t.Errorf("CountFacts() = %v, %v, want 1", count, err)
+	}
+}
+
+func TestServer_FactsAdminService(t *testing.T) {
+	id := primitive.NewObjectID()
+	facts := map[primitive.ObjectID]*repository.Fact{
+		id: {ID: id, Fact: "Octopuses have three hearts.", Animal: "octopus", Language: "en", CreatedAt: time.Now()},
+	}
+	conn, stop := newTestClient(t, facts)
+	defer stop()
+	client := factsv1.NewFactsAdminServiceClient(conn)
+
+	input := &factsv1.FactInput{Fact: "Octopuses have three hearts.", Source: "https://factanimal.com/octopus/", Animal: "Octopus", Tags: []string{"Ocean"}}
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{name: "without token", ctx: context.Background(), wantCode: codes.Unauthenticated},
+		{name: "invalid token", ctx: withToken("invalid"), wantCode: codes.Unauthenticated},
+		{name: "missing scope", ctx: withToken("get:fact create:fact"), wantCode: codes.PermissionDenied},
+		{name: "with scope", ctx: withToken("get:fact update:fact"), wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.UpdateFact(tt.ctx, &factsv1.UpdateFactRequest{Id: id.Hex(), Fact: input})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("UpdateFact() error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+
+	created, err := client.CreateFact(withToken("create:fact"), &factsv1.CreateFactRequest{Fact: input})
+	if err != nil || created.GetId() == "" {
+		t.Errorf("CreateFact() = %v, %v, want the ID of the new fact", created, err)
+	}
+	if _, err := client.CreateFact(withToken("create:fact"), &factsv1.CreateFactRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateFact() error = %v, want InvalidArgument without fact", err)
+	}
+	invalidInput := &factsv1.FactInput{Fact: "Octopuses have three hearts.", Source: "factanimal.com"}
+	if _, err := client.CreateFact(withToken("create:fact"), &factsv1.CreateFactRequest{Fact: invalidInput}); status.Code(err) != codes.InvalidArgument ||
+		len(status.Convert(err).Details()) != 1 {
+		t.Errorf("CreateFact() error = %v, want InvalidArgument with the field violations of an invalid source", err)
+	}
+
+	if _, err := client.ApproveFact(withToken("approve:fact"), &factsv1.ApproveFactRequest{Id: id.Hex()}); err != nil {
+		t.Errorf("ApproveFact() unexpected error = %v", err)
+	}
+
+	list, err := client.ListAllFacts(withToken("get:fact"), &factsv1.ListAllFactsRequest{})
+	if err != nil || len(list.GetFacts()) != 1 {
+		t.Fatalf("ListAllFacts() = %v, %v, want the fact", list, err)
+	}
+	fact := list.GetFacts()[0]
+	if !fact.GetApproved() || fact.GetApprovedAt() == nil || fact.GetAnimal() != "octopus" || len(fact.GetTags()) != 1 || fact.GetTags()[0] != "ocean" || fact.GetRevision() != 2 {
+		t.Errorf("ListAllFacts() = %v, want the updated, approved and normalized fact", fact)
+	}
+
+	reviewQueue, err := client.ListAllFacts(withToken("get:fact"), &factsv1.ListAllFactsRequest{ReviewQueue: true})
+	if err != nil || len(reviewQueue.GetFacts()) != 0 {
+		t.Errorf("ListAllFacts() = %v, %v, want an empty review queue", reviewQueue, err)
+	}
+
+	if _, err := client.UnapproveFact(withToken("unapprove:fact"), &factsv1.UnapproveFactRequest{Id: id.Hex()}); err != nil {
+		t.Errorf("UnapproveFact() unexpected error = %v", err)
+	}
+	if _, err := client.DeleteFact(withToken("delete:fact"), &factsv1.DeleteFactRequest{Id: "no-id"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("DeleteFact() error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestServer_Health(t *testing.T) {
+	conn, stop := newTestClient(t, map[primitive.ObjectID]*repository.Fact{})
+	defer stop()
+	client := healthv1.NewHealthClient(conn)
+
+	for _, service := range []string{"", factsv1.FactsService_ServiceDesc.ServiceName, factsv1.FactsAdminService_ServiceDesc.ServiceName} {
+		response, err := client.Check(context.Background(), &healthv1.HealthCheckRequest{Service: service})
+		if err != nil || response.GetStatus() != healthv1.HealthCheckResponse_SERVING {
+			t.Errorf("Check(%q) = %v, %v, want SERVING", service, response, err)
+		}
+	}
+}
+
+func TestFactsAdminRequiredScopes(t *testing.T) {
+	for _, method := range factsv1.FactsAdminService_ServiceDesc.Methods {
+		fullMethod := "/" + factsv1.FactsAdminService_ServiceDesc.ServiceName + "/" + method.MethodName
+		if _, ok := api.FactsAdminRequiredScopes[fullMethod]; !ok {
+			t.Errorf("FactsAdminRequiredScopes has no scope for %s, it would not be authenticated", fullMethod)
+		}
+	}
+}
+
+func TestEnsureValidTokenGRPC_deniesAdminMethodsWithoutScope(t *testing.T) {
+	interceptor := middleware.EnsureValidTokenGRPC(validateTestToken, []string{factsv1.FactsAdminService_ServiceDesc.ServiceName}, api.FactsAdminRequiredScopes)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "handled", nil
+	}
+	incoming := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	allScopes := "create:fact update:fact delete:fact approve:fact unapprove:fact get:fact"
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		fullMethod string
+		wantCode   codes.Code
+	}{
+		{name: "unlisted admin method without token", ctx: context.Background(), fullMethod: "/" + factsv1.FactsAdminService_ServiceDesc.ServiceName + "/PurgeFacts", wantCode: codes.Unauthenticated},
+		{name: "unlisted admin method with all scopes", ctx: incoming(allScopes), fullMethod: "/" + factsv1.FactsAdminService_ServiceDesc.ServiceName + "/PurgeFacts", wantCode: codes.PermissionDenied},
+		{name: "listed admin method", ctx: incoming(allScopes), fullMethod: factsv1.FactsAdminService_ListAllFacts_FullMethodName, wantCode: codes.OK},
+		{name: "public method", ctx: context.Background(), fullMethod: "/" + factsv1.FactsService_ServiceDesc.ServiceName + "/GetFact", wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.fullMethod}, handler)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("interceptor error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+const (
+	maxServeTimeSeriesPoints = 1000
+)
+
+type AnalyticsApi struct {
+	analyticsApiRoutes []router.Route
+	analyticsHandler   *handler.AnalyticsHandler
+}
+
+func NewAnalyticsApi(analyticsHandler *handler.AnalyticsHandler) *AnalyticsApi {
+	return &AnalyticsApi{analyticsHandler: analyticsHandler}
+}
+
+func (a *AnalyticsApi) SetupRoutes() {
+	a.analyticsApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/analytics/serves",
+			HandlerFunc: a.getServeCounts,
+			Scope:       "get:analytics",
+		},
+		{
+			Method:      "GET",
+			Path:        "/analytics/serves/:id",
+			HandlerFunc: a.getServeTimeSeries,
+			Scope:       "get:analytics",
+		},
+	}
+}
+
+func (a *AnalyticsApi) GetRoutes() []router.Route {
+	return a.analyticsApiRoutes
+}
+
+// getServeCounts
+//
+//	@Summary      gets serve counts
+//	@Description  gets the number of times each fact was served by the public API in the time range, ordered by count
+//	@Produce      json
+//	@Param        from  query  string  false  "start of the time range in RFC 3339 format (default 30 days ago)"
+//	@Param        to    query  string  false  "end of the time range in RFC 3339 format (default now)"
+//	@Success      200  {array}   handler.FactServeCount
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /analytics/serves [get]
+func (a *AnalyticsApi) getServeCounts(c echo.Context) error {
+	from, to, err := parseTimeRange(c, 30*24*time.Hour)
+	if err != nil {
+		return problem.BadRequest(err.Error())
+	}
+
+	serveCounts, err := a.analyticsHandler.GetServeCounts(from, to)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &serveCounts)
+}
+
+// getServeTimeSeries
+//
+//	@Summary      gets serve time series of fact
+//	@Description  gets the number of times a fact was served by the public API per hour or day in the time range
+//	@Produce      json
+//	@Param        granularity  query  string  false  "hour or day (default hour)"
+//	@Param        from         query  string  false  "start of the time range in RFC 3339 format (default 24 hours ago)"
+//	@Param        to           query  string  false  "end of the time range in RFC 3339 format (default now)"
+//	@Success      200  {array}   handler.ServeCountPoint
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /analytics/serves/:id [get]
+func (a *AnalyticsApi) getServeTimeSeries(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	granularity := repository.GranularityHour
+	defaultRange := 24 * time.Hour
+	switch c.QueryParam("granularity") {
+	case "", string(repository.GranularityHour):
+	case string(repository.GranularityDay):
+		granularity = repository.GranularityDay
+		defaultRange = 30 * 24 * time.Hour
+	default:
+		return problem.BadRequest("granularity must be either hour or day")
+	}
+
+	from, to, err := parseTimeRange(c, defaultRange)
+	if err != nil {
+		return problem.BadRequest(err.Error())
+	}
+	if to.Sub(from)/granularity.Duration() > maxServeTimeSeriesPoints {
+		return problem.BadRequest(fmt.Sprintf("time range must not contain more than %d periods", maxServeTimeSeriesPoints))
+	}
+
+	timeSeries, err := a.analyticsHandler.GetServeTimeSeries(objID, granularity, from, to)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &timeSeries)
+}
+
+// parseTimeRange reads the from and to query parameters, to defaults to now and from to defaultRange before to.
+func parseTimeRange(c echo.Context, defaultRange time.Duration) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if toParam := c.QueryParam("to"); toParam != "" {
+		var err error
+		to, err = time.Parse(time.RFC3339, toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to query parameter '%s' is not a valid RFC 3339 timestamp", toParam)
+		}
+	}
+
+	from := to.Add(-defaultRange)
+	if fromParam := c.QueryParam("from"); fromParam != "" {
+		var err error
+		from, err = time.Parse(time.RFC3339, fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from query parameter '%s' is not a valid RFC 3339 timestamp", fromParam)
+		}
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+
+	return from, to, nil
+}
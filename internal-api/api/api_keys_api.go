@@ -0,0 +1,286 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/apikey"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+const (
+	// defaultAPIKeyUsageDays is the number of days the usage is returned for if no days are requested
+	defaultAPIKeyUsageDays = 30
+)
+
+type CreateAPIKey struct {
+	Owner       string   `json:"owner"`
+	Description string   `json:"description"`
+	Scopes      []string `json:"scopes"`
+	// DailyQuota is the number of requests per UTC day, 0 for no quota.
+	DailyQuota int `json:"dailyQuota"`
+}
+
+// IssueAPIKeyResult contains the key, it is only returned when the key is issued or rotated.
+type IssueAPIKeyResult struct {
+	Id     string `json:"id"`
+	Key    string `json:"key"`
+	Prefix string `json:"prefix"`
+}
+
+type APIKeysApi struct {
+	apiKeysApiRoutes []router.Route
+	apiKeysHandler   *handler.APIKeysHandler
+}
+
+func NewAPIKeysApi(apiKeysHandler *handler.APIKeysHandler) *APIKeysApi {
+	return &APIKeysApi{apiKeysHandler: apiKeysHandler}
+}
+
+func (a *APIKeysApi) SetupRoutes() {
+	a.apiKeysApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/api-keys",
+			HandlerFunc: a.getAPIKeys,
+			Scope:       "get:api-key",
+		},
+		{
+			Method:      "POST",
+			Path:        "/api-keys",
+			HandlerFunc: a.issueAPIKey,
+			Scope:       "create:api-key",
+		},
+		{
+			Method:      "GET",
+			Path:        "/api-keys/:id",
+			HandlerFunc: a.getAPIKey,
+			Scope:       "get:api-key",
+		},
+		{
+			Method:      "POST",
+			Path:        "/api-keys/:id/rotate",
+			HandlerFunc: a.rotateAPIKey,
+			Scope:       "update:api-key",
+		},
+		{
+			Method:      "DELETE",
+			Path:        "/api-keys/:id",
+			HandlerFunc: a.revokeAPIKey,
+			Scope:       "delete:api-key",
+		},
+		{
+			Method:      "GET",
+			Path:        "/api-keys/:id/usage",
+			HandlerFunc: a.getAPIKeyUsage,
+			Scope:       "get:api-key",
+		},
+	}
+}
+
+func (a *APIKeysApi) GetRoutes() []router.Route {
+	return a.apiKeysApiRoutes
+}
+
+// getAPIKeys
+//
+//	@Summary      gets api keys
+//	@Description  gets all api keys of the public api including the revoked ones, the keys themselves are not included
+//	@Produce      json
+//	@Success      200  {array}   []repository.APIKey
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /api-keys [get]
+func (a *APIKeysApi) getAPIKeys(c echo.Context) error {
+	keys, err := a.apiKeysHandler.GetKeys()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &keys)
+}
+
+// issueAPIKey
+//
+//	@Summary      issue api key
+//	@Description  issues an api key for the public api (scopes: read:facts, create:report), the key is only returned once and only its hash is stored
+//	@Produce      json
+//	@Param        request body CreateAPIKey true "api key"
+//	@Success      201  {object}  IssueAPIKeyResult
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /api-keys [post]
+func (a *APIKeysApi) issueAPIKey(c echo.Context) error {
+	apiKey := &CreateAPIKey{}
+	if err := c.Bind(apiKey); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+
+	issuedKey, key, err := a.apiKeysHandler.IssueKey(&handler.APIKeySettings{
+		Owner:       apiKey.Owner,
+		Description: apiKey.Description,
+		Scopes:      apiKey.Scopes,
+		DailyQuota:  apiKey.DailyQuota,
+	})
+	if isInvalidAPIKey(err) {
+		return problem.BadRequest(invalidAPIKeyMessage(err))
+	} else if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, IssueAPIKeyResult{Id: issuedKey.ID.Hex(), Key: key, Prefix: issuedKey.Prefix})
+}
+
+// getAPIKey
+//
+//	@Summary      gets api key
+//	@Description  gets an api key by ID, the key itself is not included
+//	@Produce      json
+//	@Success      200  {object}  repository.APIKey
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /api-keys/:id [get]
+func (a *APIKeysApi) getAPIKey(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	key, err := a.apiKeysHandler.GetKey(objID)
+	if errors.Is(err, handler.ErrAPIKeyNotFound) {
+		return problem.NotFound(fmt.Sprintf("api key with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, key)
+}
+
+// rotateAPIKey
+//
+//	@Summary      rotate api key
+//	@Description  replaces the key of an api key with a new one, the old key is not valid anymore, settings and usage are kept
+//	@Produce      json
+//	@Success      200  {object}  IssueAPIKeyResult
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      409  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /api-keys/:id/rotate [post]
+func (a *APIKeysApi) rotateAPIKey(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	rotatedKey, key, err := a.apiKeysHandler.RotateKey(objID)
+	if errors.Is(err, handler.ErrAPIKeyNotFound) {
+		return problem.NotFound(fmt.Sprintf("api key with ID '%s' not found", id))
+	} else if errors.Is(err, handler.ErrAPIKeyRevoked) {
+		return problem.Conflict(fmt.Sprintf("api key with ID '%s' is revoked", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, IssueAPIKeyResult{Id: rotatedKey.ID.Hex(), Key: key, Prefix: rotatedKey.Prefix})
+}
+
+// revokeAPIKey
+//
+//	@Summary      revoke api key
+//	@Description  revokes an api key, requests with it are rejected, the api key is kept with its usage
+//	@Produce      json
+//	@Success      200  {string}  "api key revoked"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /api-keys/:id [delete]
+func (a *APIKeysApi) revokeAPIKey(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	err = a.apiKeysHandler.RevokeKey(objID)
+	if errors.Is(err, handler.ErrAPIKeyNotFound) {
+		return problem.NotFound(fmt.Sprintf("api key with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.String(http.StatusOK, "api key revoked")
+}
+
+// getAPIKeyUsage
+//
+//	@Summary      gets api key usage
+//	@Description  gets the requests of an api key per UTC day, from and to are days like 2024-01-31 (both included, the last 30 days by default, at most 366 days), days without requests are missing
+//	@Produce      json
+//	@Param        from  query  string  false  "first day"
+//	@Param        to    query  string  false  "last day"
+//	@Success      200  {array}   []repository.APIKeyUsage
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /api-keys/:id/usage [get]
+func (a *APIKeysApi) getAPIKeyUsage(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	lastDay := time.Now().UTC()
+	if to := strings.TrimSpace(c.QueryParam("to")); to != "" {
+		lastDay, err = time.Parse(apikey.DayFormat, to)
+		if err != nil {
+			return problem.BadRequest("to must be a day in the format 2006-01-02")
+		}
+	}
+	firstDay := lastDay.AddDate(0, 0, -(defaultAPIKeyUsageDays - 1))
+	if from := strings.TrimSpace(c.QueryParam("from")); from != "" {
+		firstDay, err = time.Parse(apikey.DayFormat, from)
+		if err != nil {
+			return problem.BadRequest("from must be a day in the format 2006-01-02")
+		}
+	}
+
+	usage, err := a.apiKeysHandler.GetUsage(objID, firstDay, lastDay)
+	if errors.Is(err, handler.ErrInvalidAPIKeyUsageDays) {
+		return problem.BadRequest("from must not be after to and the period must not be longer than 366 days")
+	} else if errors.Is(err, handler.ErrAPIKeyNotFound) {
+		return problem.NotFound(fmt.Sprintf("api key with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &usage)
+}
+
+func isInvalidAPIKey(err error) bool {
+	return errors.Is(err, handler.ErrInvalidAPIKeyOwner) ||
+		errors.Is(err, handler.ErrInvalidAPIKeyScopes) ||
+		errors.Is(err, handler.ErrInvalidAPIKeyQuota)
+}
+
+func invalidAPIKeyMessage(err error) string {
+	switch {
+	case errors.Is(err, handler.ErrInvalidAPIKeyOwner):
+		return "owner must not be empty"
+	case errors.Is(err, handler.ErrInvalidAPIKeyScopes):
+		return fmt.Sprintf("scopes must contain at least one of %s", strings.Join(apikey.Scopes, ", "))
+	default:
+		return "dailyQuota must not be negative"
+	}
+}
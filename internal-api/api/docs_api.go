@@ -0,0 +1,31 @@
+package api
+
+import (
+	echoSwagger "github.com/swaggo/echo-swagger"
+
+	_ "github.com/cafo13/animal-facts/internal-api/docs"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+// DocsApi serves the swagger documentation of the api, it is mounted without the prefix of a version.
+type DocsApi struct {
+	docsApiRoutes []router.Route
+}
+
+func NewDocsApi() *DocsApi {
+	return &DocsApi{}
+}
+
+func (d *DocsApi) SetupRoutes() {
+	d.docsApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/swagger/*",
+			HandlerFunc: echoSwagger.WrapHandler,
+		},
+	}
+}
+
+func (d *DocsApi) GetRoutes() []router.Route {
+	return d.docsApiRoutes
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+type EventsApi struct {
+	eventsApiRoutes []router.Route
+	eventBus        *events.Bus
+}
+
+func NewEventsApi(eventBus *events.Bus) *EventsApi {
+	return &EventsApi{eventBus: eventBus}
+}
+
+func (e *EventsApi) SetupRoutes() {
+	e.eventsApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/events",
+			HandlerFunc: e.streamEvents,
+			Scope:       "get:fact",
+		},
+		{
+			Method:      "GET",
+			Path:        "/events/ws",
+			HandlerFunc: e.streamEventsWebSocket,
+			Scope:       "get:fact",
+		},
+	}
+}
+
+func (e *EventsApi) GetRoutes() []router.Route {
+	return e.eventsApiRoutes
+}
+
+// streamEvents
+//
+//	@Summary      streams fact events
+//	@Description  streams the created, updated, approved, unapproved and deleted facts as server-sent events, clients resume after the last received event with the Last-Event-ID header
+//	@Produce      text/event-stream
+//	@Param        types          query   string  false  "comma separated event types (fact.created, fact.updated, fact.approved, fact.unapproved, fact.deleted), all types if empty"
+//	@Param        lastEventId    query   int     false  "ID of the last received event, the Last-Event-ID header takes precedence"
+//	@Param        Last-Event-ID  header  int     false  "ID of the last received event"
+//	@Success      200  {object}  events.Event
+//	@Failure      400  {object}  problem.Problem
+//	@Router       /events [get]
+func (e *EventsApi) streamEvents(c echo.Context) error {
+	lastEventID, err := events.LastEventID(c)
+	if err != nil {
+		return problem.BadRequest("last event ID must be a positive integer")
+	}
+
+	return events.ServeSSE(c, e.eventBus, lastEventID, events.TypeFilter(c.QueryParam("types")))
+}
+
+// streamEventsWebSocket
+//
+//	@Summary      streams fact events via websocket
+//	@Description  streams the created, updated, approved, unapproved and deleted facts as JSON messages of a websocket, clients resume after the last received event with the lastEventId query parameter
+//	@Param        types        query  string  false  "comma separated event types (fact.created, fact.updated, fact.approved, fact.unapproved, fact.deleted), all types if empty"
+//	@Param        lastEventId  query  int     false  "ID of the last received event"
+//	@Success      101  {object}  events.Event
+//	@Failure      400  {object}  problem.Problem
+//	@Router       /events/ws [get]
+func (e *EventsApi) streamEventsWebSocket(c echo.Context) error {
+	lastEventID, err := events.LastEventID(c)
+	if err != nil {
+		return problem.BadRequest("last event ID must be a positive integer")
+	}
+
+	return events.ServeWebSocket(c, e.eventBus, lastEventID, events.TypeFilter(c.QueryParam("types")))
+}
@@ -2,21 +2,21 @@ package api
 
 import (
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/labstack/echo/v4"
-	echoSwagger "github.com/swaggo/echo-swagger"
 
-	_ "github.com/cafo13/animal-facts/internal-api/docs"
 	"github.com/cafo13/animal-facts/internal-api/handler"
-	"github.com/cafo13/animal-facts/pkg/middleware"
+	"github.com/cafo13/animal-facts/pkg/patch"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
 	"github.com/cafo13/animal-facts/pkg/router"
-)
-
-var (
-	basePathV1 = "api/v1"
+	"github.com/cafo13/animal-facts/pkg/validation"
 )
 
 type CreateFactResult struct {
@@ -24,12 +24,11 @@ type CreateFactResult struct {
 }
 
 type CreateUpdateFact struct {
-	Fact   string `json:"fact"`
-	Source string `json:"source"`
-}
-
-type ErrorResult struct {
-	Error string `json:"error"`
+	Fact     string   `json:"fact"`
+	Source   string   `json:"source"`
+	Animal   string   `json:"animal"`
+	Tags     []string `json:"tags"`
+	Language string   `json:"language"`
 }
 
 type CountResult struct {
@@ -49,67 +48,51 @@ func (f *FactsApi) SetupRoutes() {
 	f.factsApiRoutes = []router.Route{
 		{
 			Method:      "GET",
-			Path:        "/swagger/*",
-			HandlerFunc: echoSwagger.WrapHandler,
-		},
-		{
-			Method:      "GET",
-			Path:        "/health-internal",
-			HandlerFunc: f.getHealth,
+			Path:        "/facts/all",
+			HandlerFunc: f.getAllFacts,
+			Scope:       "get:fact",
 		},
 		{
 			Method:      "GET",
-			Path:        fmt.Sprintf("/%s/facts/all", basePathV1),
-			HandlerFunc: f.getAllFacts,
-			Middlewares: []echo.MiddlewareFunc{
-				middleware.EnsureValidToken(),
-				middleware.VerifyScope("get:fact"),
-			},
+			Path:        "/facts/review",
+			HandlerFunc: f.getReviewQueue,
+			Scope:       "get:fact",
 		},
 		{
 			Method:      "POST",
-			Path:        fmt.Sprintf("/%s/facts", basePathV1),
+			Path:        "/facts",
 			HandlerFunc: f.createFact,
-			Middlewares: []echo.MiddlewareFunc{
-				middleware.EnsureValidToken(),
-				middleware.VerifyScope("create:fact"),
-			},
+			Scope:       "create:fact",
 		},
 		{
 			Method:      "PUT",
-			Path:        fmt.Sprintf("%s/facts/:id", basePathV1),
+			Path:        "/facts/:id",
 			HandlerFunc: f.updateFact,
-			Middlewares: []echo.MiddlewareFunc{
-				middleware.EnsureValidToken(),
-				middleware.VerifyScope("update:fact"),
-			},
+			Scope:       "update:fact",
+		},
+		{
+			Method:      "PATCH",
+			Path:        "/facts/:id",
+			HandlerFunc: f.patchFact,
+			Scope:       "update:fact",
 		},
 		{
 			Method:      "DELETE",
-			Path:        fmt.Sprintf("%s/facts/:id", basePathV1),
+			Path:        "/facts/:id",
 			HandlerFunc: f.deleteFact,
-			Middlewares: []echo.MiddlewareFunc{
-				middleware.EnsureValidToken(),
-				middleware.VerifyScope("delete:fact"),
-			},
+			Scope:       "delete:fact",
 		},
 		{
 			Method:      "POST",
-			Path:        fmt.Sprintf("/%s/facts/:id/approve", basePathV1),
+			Path:        "/facts/:id/approve",
 			HandlerFunc: f.approveFact,
-			Middlewares: []echo.MiddlewareFunc{
-				middleware.EnsureValidToken(),
-				middleware.VerifyScope("approve:fact"),
-			},
+			Scope:       "approve:fact",
 		},
 		{
 			Method:      "POST",
-			Path:        fmt.Sprintf("/%s/facts/:id/unapprove", basePathV1),
+			Path:        "/facts/:id/unapprove",
 			HandlerFunc: f.unapproveFact,
-			Middlewares: []echo.MiddlewareFunc{
-				middleware.EnsureValidToken(),
-				middleware.VerifyScope("unapprove:fact"),
-			},
+			Scope:       "unapprove:fact",
 		},
 	}
 }
@@ -118,11 +101,6 @@ func (f *FactsApi) GetRoutes() []router.Route {
 	return f.factsApiRoutes
 }
 
-func (f *FactsApi) getHealth(c echo.Context) error {
-	// TODO check database connection for health check and maybe other connections?
-	return c.String(http.StatusOK, "Healthy")
-}
-
 // createFact
 //
 //	@Summary      create fact
@@ -130,25 +108,28 @@ func (f *FactsApi) getHealth(c echo.Context) error {
 //	@Produce      json
 //	@Param        request body CreateUpdateFact true "fact"
 //	@Success      201  {object}  CreateFactResult
-//	@Failure      400  {object}  ErrorResult
-//	@Failure      500  {object}  ErrorResult
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      422  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts [post]
 func (f *FactsApi) createFact(c echo.Context) error {
 	fact := &CreateUpdateFact{}
-	if err := c.Bind(fact); err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResult{Error: err.Error()})
+	if err := decodeFact(c, fact); err != nil {
+		return err
 	}
 
 	id := primitive.NewObjectID()
-	err := f.factsHandler.Create(&handler.Fact{
+	err := f.factsHandler.WithContext(c.Request().Context()).Create(&handler.Fact{
 		ID:       id,
 		Fact:     fact.Fact,
 		Source:   fact.Source,
+		Animal:   fact.Animal,
+		Tags:     fact.Tags,
+		Language: fact.Language,
 		Approved: false,
 	})
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return err
 	}
 
 	t := id.Hex()
@@ -162,53 +143,120 @@ func (f *FactsApi) createFact(c echo.Context) error {
 //	@Produce      json
 //	@Param        request body CreateUpdateFact true "fact"
 //	@Success      200  {string}  "fact updated"
-//	@Failure      400  {object}  ErrorResult
-//	@Failure      500  {object}  ErrorResult
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      422  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts/:id [put]
 func (f *FactsApi) updateFact(c echo.Context) error {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResult{Error: "id from request path is not a valid object id in hex string format"})
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
 	}
 
 	fact := &CreateUpdateFact{}
-	if err := c.Bind(fact); err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResult{Error: err.Error()})
+	if err := decodeFact(c, fact); err != nil {
+		return err
 	}
 
-	err = f.factsHandler.Update(&handler.Fact{
-		ID:     objID,
-		Fact:   fact.Fact,
-		Source: fact.Source,
+	err = f.factsHandler.WithContext(c.Request().Context()).Update(&handler.Fact{
+		ID:       objID,
+		Fact:     fact.Fact,
+		Source:   fact.Source,
+		Animal:   fact.Animal,
+		Tags:     fact.Tags,
+		Language: fact.Language,
 	})
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return err
 	}
 
 	return c.String(http.StatusOK, "fact updated")
 }
 
+// patchFact
+//
+//	@Summary      patch fact
+//	@Description  changes the content of an existing fact (fact, source, animal, tags and language) with a JSON Merge Patch or a JSON Patch, the patch is applied to the current content and the patched content is validated like updated facts
+//	@Accept       application/merge-patch+json,application/json-patch+json
+//	@Produce      json
+//	@Param        request body []patch.Operation true "JSON Patch, or JSON Merge Patch of the content like handler.FactContent"
+//	@Success      200  {string}  "fact patched"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      409  {object}  problem.Problem
+//	@Failure      415  {object}  problem.Problem
+//	@Failure      422  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/:id [patch]
+func (f *FactsApi) patchFact(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return problem.BadRequest("failed to read request body")
+	}
+	factPatch, err := patch.Parse(mediaType, body)
+	if errors.Is(err, patch.ErrUnsupportedMediaType) {
+		return problem.UnsupportedMediaType(err.Error())
+	} else if err != nil {
+		return problem.BadRequest(err.Error())
+	}
+
+	err = f.factsHandler.WithContext(c.Request().Context()).Patch(objID, factPatch)
+	if err != nil {
+		return patchProblem(id, err)
+	}
+
+	return c.String(http.StatusOK, "fact patched")
+}
+
+// patchProblem maps the errors of patches that are caused by the patch, a failed test conflicts with the current fact
+// and a path that doesn't exist in the fact can't be processed.
+func patchProblem(id string, err error) error {
+	detail := err.Error()
+	var operationErr *patch.OperationError
+	if errors.As(err, &operationErr) {
+		detail = operationErr.Error()
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return problem.NotFound(fmt.Sprintf("fact with ID '%s' not found", id))
+	case errors.Is(err, patch.ErrTestFailed):
+		return problem.Conflict(detail)
+	case errors.Is(err, patch.ErrPathNotFound):
+		return problem.Validation(detail)
+	case errors.Is(err, patch.ErrInvalidPatch):
+		return problem.BadRequest(detail)
+	}
+
+	return err
+}
+
 // deleteFact
 //
 //	@Summary      delete fact
 //	@Description  delete an existing fact
 //	@Produce      json
 //	@Success      200  {string}  "fact deleted"
-//	@Failure      500  {object}  ErrorResult
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts/:id [delete]
 func (f *FactsApi) deleteFact(c echo.Context) error {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResult{Error: "id from request path is not a valid object id in hex string format"})
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
 	}
 
-	err = f.factsHandler.Delete(objID)
+	err = f.factsHandler.WithContext(c.Request().Context()).Delete(objID)
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return err
 	}
 
 	return c.String(http.StatusOK, "fact deleted")
@@ -220,19 +268,18 @@ func (f *FactsApi) deleteFact(c echo.Context) error {
 //	@Description  approve an existing fact, so that it gets available in the public API
 //	@Produce      json
 //	@Success      200  {string}  "fact approved"
-//	@Failure      500  {object}  ErrorResult
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts/:id/approve [post]
 func (f *FactsApi) approveFact(c echo.Context) error {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResult{Error: "id from request path is not a valid object id in hex string format"})
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
 	}
 
-	err = f.factsHandler.Approve(objID)
+	err = f.factsHandler.WithContext(c.Request().Context()).Approve(objID)
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return err
 	}
 
 	return c.String(http.StatusOK, "fact approved")
@@ -244,19 +291,18 @@ func (f *FactsApi) approveFact(c echo.Context) error {
 //	@Description  unapprove an existing fact, so that it is no longer available in the public API
 //	@Produce      json
 //	@Success      200  {string}  "fact unapproved"
-//	@Failure      500  {object}  ErrorResult
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts/:id/unapprove [post]
 func (f *FactsApi) unapproveFact(c echo.Context) error {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResult{Error: "id from request path is not a valid object id in hex string format"})
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
 	}
 
-	err = f.factsHandler.Unapprove(objID)
+	err = f.factsHandler.WithContext(c.Request().Context()).Unapprove(objID)
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return err
 	}
 
 	return c.String(http.StatusOK, "fact unapproved")
@@ -268,14 +314,42 @@ func (f *FactsApi) unapproveFact(c echo.Context) error {
 //	@Description  gets all facts (approved and unapproved) from the database
 //	@Produce      json
 //	@Success      200  {array}   []repository.Fact
-//	@Failure      500  {object}  ErrorResult
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts/all     [get]
 func (f *FactsApi) getAllFacts(c echo.Context) error {
-	facts, err := f.factsHandler.GetAll()
+	facts, err := f.factsHandler.WithContext(c.Request().Context()).GetAll()
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, &facts)
 }
+
+// getReviewQueue
+//
+//	@Summary      gets review queue
+//	@Description  gets all facts that are not approved yet or flagged because of several open reports
+//	@Produce      json
+//	@Success      200  {array}   []repository.Fact
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/review  [get]
+func (f *FactsApi) getReviewQueue(c echo.Context) error {
+	facts, err := f.factsHandler.WithContext(c.Request().Context()).GetReviewQueue()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &facts)
+}
+
+// decodeFact decodes the fact of the request body, unknown fields are field errors and malformed JSON is a bad request.
+func decodeFact(c echo.Context, fact *CreateUpdateFact) error {
+	err := validation.DecodeJSON(c.Request().Body, fact)
+
+	var validationErrs validation.Errors
+	if err != nil && !errors.As(err, &validationErrs) {
+		return problem.BadRequest(err.Error())
+	}
+
+	return err
+}
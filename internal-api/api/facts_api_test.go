@@ -45,7 +45,7 @@ func Test_RunApiIntegrationTests_create_update_delete(t *testing.T) {
 		e := echo.New()
 		createReqBody := CreateUpdateFact{
 			Fact:   "Some new animal fact.",
-			Source: "some-source.com/animalfact/23",
+			Source: "https://some-source.com/animalfact/23",
 		}
 		createJsonBody, err := json.Marshal(createReqBody)
 		if err != nil {
@@ -82,7 +82,7 @@ func Test_RunApiIntegrationTests_create_update_delete(t *testing.T) {
 
 		updateReqBody := CreateUpdateFact{
 			Fact:   "Some updated animal fact.",
-			Source: "some-source.com/animalfact/23",
+			Source: "https://some-source.com/animalfact/23",
 		}
 		updateJsonBody, err := json.Marshal(updateReqBody)
 		if err != nil {
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/middleware"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+var reportStatuses = []repository.ReportStatus{
+	repository.ReportStatusOpen,
+	repository.ReportStatusResolved,
+	repository.ReportStatusDismissed,
+}
+
+type ReportsApi struct {
+	reportsApiRoutes []router.Route
+	reportsHandler   *handler.ReportsHandler
+}
+
+func NewReportsApi(reportsHandler *handler.ReportsHandler) *ReportsApi {
+	return &ReportsApi{reportsHandler: reportsHandler}
+}
+
+func (r *ReportsApi) SetupRoutes() {
+	r.reportsApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/reports",
+			HandlerFunc: r.getReports,
+			Scope:       "get:report",
+		},
+		{
+			Method:      "POST",
+			Path:        "/reports/:id/resolve",
+			HandlerFunc: r.resolveReport,
+			Scope:       "update:report",
+		},
+		{
+			Method:      "POST",
+			Path:        "/reports/:id/dismiss",
+			HandlerFunc: r.dismissReport,
+			Scope:       "update:report",
+		},
+		{
+			Method:      "POST",
+			Path:        "/reports/:id/unapprove",
+			HandlerFunc: r.unapproveFactOfReport,
+			Scope:       "update:report",
+			Middlewares: []echo.MiddlewareFunc{middleware.VerifyScope("unapprove:fact")},
+		},
+	}
+}
+
+func (r *ReportsApi) GetRoutes() []router.Route {
+	return r.reportsApiRoutes
+}
+
+// getReports
+//
+//	@Summary      gets reports
+//	@Description  gets all reports of facts, optionally filtered by status (open, resolved or dismissed)
+//	@Produce      json
+//	@Param        status  query  string  false  "report status"
+//	@Success      200  {array}   []repository.Report
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /reports [get]
+func (r *ReportsApi) getReports(c echo.Context) error {
+	status := repository.ReportStatus(c.QueryParam("status"))
+	if status != "" && !slices.Contains(reportStatuses, status) {
+		return problem.BadRequest(fmt.Sprintf("report status '%s' is not valid", status))
+	}
+
+	reports, err := r.reportsHandler.GetAll(status)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &reports)
+}
+
+// resolveReport
+//
+//	@Summary      resolve report
+//	@Description  marks an open report as resolved, e.g. after the fact has been corrected
+//	@Produce      json
+//	@Success      200  {string}  "report resolved"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      409  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /reports/:id/resolve [post]
+func (r *ReportsApi) resolveReport(c echo.Context) error {
+	return r.handleReportAction(c, r.reportsHandler.Resolve, "report resolved")
+}
+
+// dismissReport
+//
+//	@Summary      dismiss report
+//	@Description  marks an open report as dismissed, e.g. if the fact was correct after all
+//	@Produce      json
+//	@Success      200  {string}  "report dismissed"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      409  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /reports/:id/dismiss [post]
+func (r *ReportsApi) dismissReport(c echo.Context) error {
+	return r.handleReportAction(c, r.reportsHandler.Dismiss, "report dismissed")
+}
+
+// unapproveFactOfReport
+//
+//	@Summary      unapprove fact of report
+//	@Description  unapproves the reported fact, so that it is no longer available in the public API, and resolves the report
+//	@Produce      json
+//	@Success      200  {string}  "fact unapproved"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      409  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /reports/:id/unapprove [post]
+func (r *ReportsApi) unapproveFactOfReport(c echo.Context) error {
+	return r.handleReportAction(c, r.reportsHandler.UnapproveFact, "fact unapproved")
+}
+
+func (r *ReportsApi) handleReportAction(c echo.Context, action func(reportID primitive.ObjectID) error, successMessage string) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	err = action(objID)
+	if errors.Is(err, handler.ErrReportNotFound) {
+		return problem.NotFound(fmt.Sprintf("report with ID '%s' not found", id))
+	} else if errors.Is(err, handler.ErrNotFound) {
+		return problem.NotFound(fmt.Sprintf("fact of report with ID '%s' not found", id))
+	} else if errors.Is(err, handler.ErrReportNotOpen) {
+		return problem.Conflict(fmt.Sprintf("report with ID '%s' is not open", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.String(http.StatusOK, successMessage)
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+var (
+	webhookDeliveryStatuses = []repository.WebhookDeliveryStatus{
+		repository.WebhookDeliveryStatusPending,
+		repository.WebhookDeliveryStatusSucceeded,
+		repository.WebhookDeliveryStatusDead,
+	}
+)
+
+type CreateUpdateWebhook struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	// Secret signs the deliveries, it is generated on creation and kept on update if it is empty.
+	Secret string `json:"secret"`
+	Active bool   `json:"active"`
+}
+
+type CreateWebhookResult struct {
+	Id     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+type WebhooksApi struct {
+	webhooksApiRoutes []router.Route
+	webhooksHandler   *handler.WebhooksHandler
+}
+
+func NewWebhooksApi(webhooksHandler *handler.WebhooksHandler) *WebhooksApi {
+	return &WebhooksApi{webhooksHandler: webhooksHandler}
+}
+
+func (w *WebhooksApi) SetupRoutes() {
+	w.webhooksApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/webhooks",
+			HandlerFunc: w.getWebhooks,
+			Scope:       "get:webhook",
+		},
+		{
+			Method:      "POST",
+			Path:        "/webhooks",
+			HandlerFunc: w.createWebhook,
+			Scope:       "create:webhook",
+		},
+		{
+			Method:      "GET",
+			Path:        "/webhooks/:id",
+			HandlerFunc: w.getWebhook,
+			Scope:       "get:webhook",
+		},
+		{
+			Method:      "PUT",
+			Path:        "/webhooks/:id",
+			HandlerFunc: w.updateWebhook,
+			Scope:       "update:webhook",
+		},
+		{
+			Method:      "DELETE",
+			Path:        "/webhooks/:id",
+			HandlerFunc: w.deleteWebhook,
+			Scope:       "delete:webhook",
+		},
+		{
+			Method:      "GET",
+			Path:        "/webhooks/:id/deliveries",
+			HandlerFunc: w.getDeliveries,
+			Scope:       "get:webhook",
+		},
+		{
+			Method:      "POST",
+			Path:        "/webhooks/:id/deliveries/:deliveryId/redeliver",
+			HandlerFunc: w.redeliver,
+			Scope:       "update:webhook",
+		},
+	}
+}
+
+func (w *WebhooksApi) GetRoutes() []router.Route {
+	return w.webhooksApiRoutes
+}
+
+// getWebhooks
+//
+//	@Summary      gets webhooks
+//	@Description  gets all webhook subscriptions, the secrets are not included
+//	@Produce      json
+//	@Success      200  {array}   []repository.WebhookSubscription
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /webhooks [get]
+func (w *WebhooksApi) getWebhooks(c echo.Context) error {
+	subscriptions, err := w.webhooksHandler.GetSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &subscriptions)
+}
+
+// createWebhook
+//
+//	@Summary      create webhook
+//	@Description  subscribes the url to fact events (fact.created, fact.updated, fact.approved, fact.unapproved, fact.deleted), the secret that signs the deliveries is only returned once
+//	@Produce      json
+//	@Param        request body CreateUpdateWebhook true "webhook"
+//	@Success      201  {object}  CreateWebhookResult
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /webhooks [post]
+func (w *WebhooksApi) createWebhook(c echo.Context) error {
+	webhook := &CreateUpdateWebhook{}
+	if err := c.Bind(webhook); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+
+	subscription, err := w.webhooksHandler.CreateSubscription(&handler.WebhookSubscription{
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Secret:     webhook.Secret,
+		Active:     webhook.Active,
+	})
+	if isInvalidWebhook(err) {
+		return problem.BadRequest(invalidWebhookMessage(err))
+	} else if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, CreateWebhookResult{Id: subscription.ID.Hex(), Secret: subscription.Secret})
+}
+
+// getWebhook
+//
+//	@Summary      gets webhook
+//	@Description  gets a webhook subscription by ID, the secret is not included
+//	@Produce      json
+//	@Success      200  {object}  repository.WebhookSubscription
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /webhooks/:id [get]
+func (w *WebhooksApi) getWebhook(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	subscription, err := w.webhooksHandler.GetSubscription(objID)
+	if errors.Is(err, handler.ErrWebhookSubscriptionNotFound) {
+		return problem.NotFound(fmt.Sprintf("webhook with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, subscription)
+}
+
+// updateWebhook
+//
+//	@Summary      update webhook
+//	@Description  update an existing webhook subscription, the secret is kept if it is empty
+//	@Produce      json
+//	@Param        request body CreateUpdateWebhook true "webhook"
+//	@Success      200  {string}  "webhook updated"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /webhooks/:id [put]
+func (w *WebhooksApi) updateWebhook(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	webhook := &CreateUpdateWebhook{}
+	if err := c.Bind(webhook); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+
+	err = w.webhooksHandler.UpdateSubscription(objID, &handler.WebhookSubscription{
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Secret:     webhook.Secret,
+		Active:     webhook.Active,
+	})
+	if isInvalidWebhook(err) {
+		return problem.BadRequest(invalidWebhookMessage(err))
+	} else if errors.Is(err, handler.ErrWebhookSubscriptionNotFound) {
+		return problem.NotFound(fmt.Sprintf("webhook with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.String(http.StatusOK, "webhook updated")
+}
+
+// deleteWebhook
+//
+//	@Summary      delete webhook
+//	@Description  delete a webhook subscription with its deliveries
+//	@Produce      json
+//	@Success      200  {string}  "webhook deleted"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /webhooks/:id [delete]
+func (w *WebhooksApi) deleteWebhook(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	err = w.webhooksHandler.DeleteSubscription(objID)
+	if errors.Is(err, handler.ErrWebhookSubscriptionNotFound) {
+		return problem.NotFound(fmt.Sprintf("webhook with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.String(http.StatusOK, "webhook deleted")
+}
+
+// getDeliveries
+//
+//	@Summary      gets webhook deliveries
+//	@Description  gets the latest 100 deliveries of a webhook subscription with all attempts, optionally filtered by status (pending, succeeded or dead), dead deliveries failed too often and are only sent again when they are redelivered
+//	@Produce      json
+//	@Param        status  query  string  false  "delivery status"
+//	@Success      200  {array}   []repository.WebhookDelivery
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /webhooks/:id/deliveries [get]
+func (w *WebhooksApi) getDeliveries(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	status := repository.WebhookDeliveryStatus(c.QueryParam("status"))
+	if status != "" && !slices.Contains(webhookDeliveryStatuses, status) {
+		return problem.BadRequest(fmt.Sprintf("delivery status '%s' is not valid", status))
+	}
+
+	deliveries, err := w.webhooksHandler.GetDeliveries(objID, status)
+	if errors.Is(err, handler.ErrWebhookSubscriptionNotFound) {
+		return problem.NotFound(fmt.Sprintf("webhook with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &deliveries)
+}
+
+// redeliver
+//
+//	@Summary      redeliver webhook delivery
+//	@Description  sends a delivery again as soon as possible with the full number of attempts, regardless of its status
+//	@Produce      json
+//	@Success      202  {string}  "delivery scheduled"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /webhooks/:id/deliveries/:deliveryId/redeliver [post]
+func (w *WebhooksApi) redeliver(c echo.Context) error {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+	deliveryID := c.Param("deliveryId")
+	deliveryObjID, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return problem.BadRequest("delivery id from request path is not a valid object id in hex string format")
+	}
+
+	err = w.webhooksHandler.Redeliver(objID, deliveryObjID)
+	if errors.Is(err, handler.ErrWebhookDeliveryNotFound) {
+		return problem.NotFound(fmt.Sprintf("delivery with ID '%s' not found", deliveryID))
+	} else if err != nil {
+		return err
+	}
+
+	return c.String(http.StatusAccepted, "delivery scheduled")
+}
+
+func isInvalidWebhook(err error) bool {
+	return errors.Is(err, handler.ErrInvalidWebhookURL) ||
+		errors.Is(err, handler.ErrInvalidWebhookEventTypes) ||
+		errors.Is(err, handler.ErrInvalidWebhookSecret)
+}
+
+func invalidWebhookMessage(err error) string {
+	switch {
+	case errors.Is(err, handler.ErrInvalidWebhookURL):
+		return "url must be an absolute http or https url"
+	case errors.Is(err, handler.ErrInvalidWebhookEventTypes):
+		return "eventTypes must contain at least one of fact.created, fact.updated, fact.approved, fact.unapproved and fact.deleted"
+	default:
+		return "secret must be at least 16 characters long"
+	}
+}
@@ -19,160 +19,301 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/facts": {
-            "post": {
-                "description": "create a new fact",
+        "/analytics/serves": {
+            "get": {
+                "description": "gets the number of times each fact was served by the public API in the time range, ordered by count",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "create fact",
+                "summary": "gets serve counts",
                 "parameters": [
                     {
-                        "description": "fact",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "start of the time range in RFC 3339 format (default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the time range in RFC 3339 format (default now)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.CreateUpdateFact"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.FactServeCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
+                }
+            }
+        },
+        "/analytics/serves/:id": {
+            "get": {
+                "description": "gets the number of times a fact was served by the public API per hour or day in the time range",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "gets serve time series of fact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "hour or day (default hour)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "start of the time range in RFC 3339 format (default 24 hours ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the time range in RFC 3339 format (default now)",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.CreateFactResult"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ServeCountPoint"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/facts/:id": {
-            "put": {
-                "description": "update an existing fact",
+        "/api-keys": {
+            "get": {
+                "description": "gets all api keys of the public api including the revoked ones, the keys themselves are not included",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "update fact",
+                "summary": "gets api keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/repository.APIKey"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "issues an api key for the public api (scopes: read:facts, create:report), the key is only returned once and only its hash is stored",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "issue api key",
                 "parameters": [
                     {
-                        "description": "fact",
+                        "description": "api key",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.CreateUpdateFact"
+                            "$ref": "#/definitions/api.CreateAPIKey"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "fact updated",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.IssueAPIKeyResult"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "delete an existing fact",
+            }
+        },
+        "/api-keys/:id": {
+            "get": {
+                "description": "gets an api key by ID, the key itself is not included",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "delete fact",
+                "summary": "gets api key",
                 "responses": {
                     "200": {
-                        "description": "fact deleted",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/repository.APIKey"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/facts/:id/approve": {
-            "post": {
-                "description": "approve an existing fact, so that it gets available in the public API",
+            },
+            "delete": {
+                "description": "revokes an api key, requests with it are rejected, the api key is kept with its usage",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "approve fact",
+                "summary": "revoke api key",
                 "responses": {
                     "200": {
-                        "description": "fact approved",
+                        "description": "api key revoked",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/facts/:id/unapprove": {
+        "/api-keys/:id/rotate": {
             "post": {
-                "description": "unapprove an existing fact, so that it is no longer available in the public API",
+                "description": "replaces the key of an api key with a new one, the old key is not valid anymore, settings and usage are kept",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "unapprove fact",
+                "summary": "rotate api key",
                 "responses": {
                     "200": {
-                        "description": "fact unapproved",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.IssueAPIKeyResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/facts/all": {
+        "/api-keys/:id/usage": {
             "get": {
-                "description": "gets all facts (approved and unapproved) from the database",
+                "description": "gets the requests of an api key per UTC day, from and to are days like 2024-01-31 (both included, the last 30 days by default, at most 366 days), days without requests are missing",
                 "produces": [
                     "application/json"
                 ],
-                "summary": "gets all facts",
+                "summary": "gets api key usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "first day",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "last day",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -181,55 +322,1146 @@ const docTemplate = `{
                             "items": {
                                 "type": "array",
                                 "items": {
-                                    "$ref": "#/definitions/repository.Fact"
+                                    "$ref": "#/definitions/repository.APIKeyUsage"
                                 }
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.CreateFactResult": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
+        },
+        "/events": {
+            "get": {
+                "description": "streams the created, updated, approved, unapproved and deleted facts as server-sent events, clients resume after the last received event with the Last-Event-ID header",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "streams fact events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "comma separated event types (fact.created, fact.updated, fact.approved, fact.unapproved, fact.deleted), all types if empty",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last received event, the Last-Event-ID header takes precedence",
+                        "name": "lastEventId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
                 }
             }
         },
-        "api.CreateUpdateFact": {
-            "type": "object",
-            "properties": {
-                "fact": {
-                    "type": "string"
-                },
-                "source": {
-                    "type": "string"
+        "/events/ws": {
+            "get": {
+                "description": "streams the created, updated, approved, unapproved and deleted facts as JSON messages of a websocket, clients resume after the last received event with the lastEventId query parameter",
+                "summary": "streams fact events via websocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "comma separated event types (fact.created, fact.updated, fact.approved, fact.unapproved, fact.deleted), all types if empty",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last received event",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
                 }
             }
         },
-        "api.ErrorResult": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
+        "/facts": {
+            "post": {
+                "description": "create a new fact",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "create fact",
+                "parameters": [
+                    {
+                        "description": "fact",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateUpdateFact"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateFactResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
                 }
             }
         },
-        "repository.Fact": {
-            "type": "object",
+        "/facts/:id": {
+            "put": {
+                "description": "update an existing fact",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "update fact",
+                "parameters": [
+                    {
+                        "description": "fact",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateUpdateFact"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "fact updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete an existing fact",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "delete fact",
+                "responses": {
+                    "200": {
+                        "description": "fact deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "changes the content of an existing fact (fact, source, animal, tags and language) with a JSON Merge Patch or a JSON Patch, the patch is applied to the current content and the patched content is validated like updated facts",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "patch fact",
+                "parameters": [
+                    {
+                        "description": "JSON Patch, or JSON Merge Patch of the content like handler.FactContent",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/patch.Operation"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "fact patched",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/:id/approve": {
+            "post": {
+                "description": "approve an existing fact, so that it gets available in the public API",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "approve fact",
+                "responses": {
+                    "200": {
+                        "description": "fact approved",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/:id/unapprove": {
+            "post": {
+                "description": "unapprove an existing fact, so that it is no longer available in the public API",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "unapprove fact",
+                "responses": {
+                    "200": {
+                        "description": "fact unapproved",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/all": {
+            "get": {
+                "description": "gets all facts (approved and unapproved) from the database",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "gets all facts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/repository.Fact"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/review": {
+            "get": {
+                "description": "gets all facts that are not approved yet or flagged because of several open reports",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "gets review queue",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/repository.Fact"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports": {
+            "get": {
+                "description": "gets all reports of facts, optionally filtered by status (open, resolved or dismissed)",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "gets reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "report status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/repository.Report"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/:id/dismiss": {
+            "post": {
+                "description": "marks an open report as dismissed, e.g. if the fact was correct after all",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "dismiss report",
+                "responses": {
+                    "200": {
+                        "description": "report dismissed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/:id/resolve": {
+            "post": {
+                "description": "marks an open report as resolved, e.g. after the fact has been corrected",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "resolve report",
+                "responses": {
+                    "200": {
+                        "description": "report resolved",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/:id/unapprove": {
+            "post": {
+                "description": "unapproves the reported fact, so that it is no longer available in the public API, and resolves the report",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "unapprove fact of report",
+                "responses": {
+                    "200": {
+                        "description": "fact unapproved",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "gets all webhook subscriptions, the secrets are not included",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "gets webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/repository.WebhookSubscription"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "subscribes the url to fact events (fact.created, fact.updated, fact.approved, fact.unapproved, fact.deleted), the secret that signs the deliveries is only returned once",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "create webhook",
+                "parameters": [
+                    {
+                        "description": "webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateUpdateWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateWebhookResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/:id": {
+            "get": {
+                "description": "gets a webhook subscription by ID, the secret is not included",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "gets webhook",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/repository.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update an existing webhook subscription, the secret is kept if it is empty",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "update webhook",
+                "parameters": [
+                    {
+                        "description": "webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateUpdateWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "webhook updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete a webhook subscription with its deliveries",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "delete webhook",
+                "responses": {
+                    "200": {
+                        "description": "webhook deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/:id/deliveries": {
+            "get": {
+                "description": "gets the latest 100 deliveries of a webhook subscription with all attempts, optionally filtered by status (pending, succeeded or dead), dead deliveries failed too often and are only sent again when they are redelivered",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "gets webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "delivery status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/repository.WebhookDelivery"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/:id/deliveries/:deliveryId/redeliver": {
+            "post": {
+                "description": "sends a delivery again as soon as possible with the full number of attempts, regardless of its status",
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "redeliver webhook delivery",
+                "responses": {
+                    "202": {
+                        "description": "delivery scheduled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.CreateAPIKey": {
+            "type": "object",
+            "properties": {
+                "dailyQuota": {
+                    "description": "DailyQuota is the number of requests per UTC day, 0 for no quota.",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.CreateFactResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateUpdateFact": {
+            "type": "object",
+            "properties": {
+                "animal": {
+                    "type": "string"
+                },
+                "fact": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.CreateUpdateWebhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "eventTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Secret signs the deliveries, it is generated on creation and kept on update if it is empty.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateWebhookResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.IssueAPIKeyResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "factId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/events.Type"
+                }
+            }
+        },
+        "events.Type": {
+            "type": "string",
+            "enum": [
+                "fact.created",
+                "fact.updated",
+                "fact.approved",
+                "fact.unapproved",
+                "fact.deleted"
+            ],
+            "x-enum-varnames": [
+                "TypeFactCreated",
+                "TypeFactUpdated",
+                "TypeFactApproved",
+                "TypeFactUnapproved",
+                "TypeFactDeleted"
+            ]
+        },
+        "handler.FactServeCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "factId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ServeCountPoint": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "periodStart": {
+                    "type": "string"
+                }
+            }
+        },
+        "patch.Operation": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "object"
+                }
+            }
+        },
+        "problem.Code": {
+            "type": "string",
+            "enum": [
+                "bad_request",
+                "validation_failed",
+                "unauthorized",
+                "forbidden",
+                "not_found",
+                "method_not_allowed",
+                "not_acceptable",
+                "conflict",
+                "unsupported_media_type",
+                "rate_limited",
+                "internal_error",
+                "not_implemented",
+                "service_unavailable"
+            ],
+            "x-enum-varnames": [
+                "CodeBadRequest",
+                "CodeValidationFailed",
+                "CodeUnauthorized",
+                "CodeForbidden",
+                "CodeNotFound",
+                "CodeMethodNotAllowed",
+                "CodeNotAcceptable",
+                "CodeConflict",
+                "CodeUnsupportedMedia",
+                "CodeRateLimited",
+                "CodeInternal",
+                "CodeNotImplemented",
+                "CodeUnavailable"
+            ]
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the machine readable code of the problem, the last segment of the type.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/problem.Code"
+                        }
+                    ]
+                },
+                "correlationId": {
+                    "description": "CorrelationID is the ID of the request in the logs, it is the X-Request-ID header of the response.",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors are the field errors of requests that failed the validation.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "dailyQuota": {
+                    "description": "DailyQuota is the number of requests per UTC day, 0 for no quota.",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the key, it identifies the key without revealing it.",
+                    "type": "string"
+                },
+                "revoked": {
+                    "type": "boolean"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "rotatedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.APIKeyUsage": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string"
+                },
+                "keyId": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "repository.Fact": {
+            "type": "object",
             "properties": {
+                "animal": {
+                    "type": "string"
+                },
                 "approved": {
                     "type": "boolean"
                 },
+                "approvedAt": {
+                    "description": "ApprovedAt is the time the fact was approved the last time, it is zero for facts that are not approved.",
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
@@ -239,17 +1471,204 @@ const docTemplate = `{
                 "fact": {
                     "type": "string"
                 },
+                "flagged": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
+                "language": {
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Revision is incremented on every update of the fact.",
+                    "type": "integer"
+                },
                 "source": {
                     "type": "string"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.Report": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "factId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "$ref": "#/definitions/repository.ReportReason"
+                },
+                "status": {
+                    "$ref": "#/definitions/repository.ReportStatus"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.ReportReason": {
+            "type": "string",
+            "enum": [
+                "incorrect",
+                "outdated",
+                "missing-source",
+                "offensive",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "ReportReasonIncorrect",
+                "ReportReasonOutdated",
+                "ReportReasonMissingSource",
+                "ReportReasonOffensive",
+                "ReportReasonOther"
+            ]
+        },
+        "repository.ReportStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "resolved",
+                "dismissed"
+            ],
+            "x-enum-varnames": [
+                "ReportStatusOpen",
+                "ReportStatusResolved",
+                "ReportStatusDismissed"
+            ]
+        },
+        "repository.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "type": "integer"
+                }
+            }
+        },
+        "repository.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.WebhookAttempt"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "type": "string"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nextAttemptAt": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/repository.WebhookDeliveryStatus"
+                },
+                "subscriptionId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.WebhookDeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "succeeded",
+                "dead"
+            ],
+            "x-enum-varnames": [
+                "WebhookDeliveryStatusPending",
+                "WebhookDeliveryStatusSucceeded",
+                "WebhookDeliveryStatusDead"
+            ]
+        },
+        "repository.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "eventTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
                 "updatedAt": {
                     "type": "string"
                 },
                 "updatedBy": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Field is the JSON name of the field, items of lists have their index like tags[1].",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+type FactServeCount struct {
+	FactID primitive.ObjectID `json:"factId"`
+	Count  int                `json:"count"`
+}
+
+type ServeCountPoint struct {
+	PeriodStart time.Time `json:"periodStart"`
+	Count       int       `json:"count"`
+}
+
+type AnalyticsHandler struct {
+	serveStatsRepository repository.ServeStatsRepository
+}
+
+func NewAnalyticsHandler(serveStatsRepository repository.ServeStatsRepository) *AnalyticsHandler {
+	return &AnalyticsHandler{serveStatsRepository}
+}
+
+// GetServeCounts returns the number of serves per fact in the time range [from, to), ordered by the count descending.
+func (a *AnalyticsHandler) GetServeCounts(from time.Time, to time.Time) ([]*FactServeCount, error) {
+	serveCounts, err := a.serveStatsRepository.ReadCounts(repository.ServeCountsFilter{
+		Granularity: repository.GranularityHour,
+		From:        repository.GranularityHour.Truncate(from),
+		To:          to,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get serve counts")
+	}
+
+	totals := map[primitive.ObjectID]int{}
+	for _, serveCount := range serveCounts {
+		totals[serveCount.FactID] += serveCount.Count
+	}
+
+	result := make([]*FactServeCount, 0, len(totals))
+	for factID, count := range totals {
+		result = append(result, &FactServeCount{FactID: factID, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count == result[j].Count {
+			return result[i].FactID.Hex() < result[j].FactID.Hex()
+		}
+		return result[i].Count > result[j].Count
+	})
+
+	return result, nil
+}
+
+// GetServeTimeSeries returns the serve counts of a fact for every period of the granularity in the time range [from, to),
+// periods without serves are included with a count of zero.
+func (a *AnalyticsHandler) GetServeTimeSeries(factID primitive.ObjectID, granularity repository.Granularity, from time.Time, to time.Time) ([]*ServeCountPoint, error) {
+	from = granularity.Truncate(from)
+	serveCounts, err := a.serveStatsRepository.ReadCounts(repository.ServeCountsFilter{
+		FactID:      &factID,
+		Granularity: granularity,
+		From:        from,
+		To:          to,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get serve counts of fact %v", factID)
+	}
+
+	counts := map[time.Time]int{}
+	for _, serveCount := range serveCounts {
+		counts[serveCount.PeriodStart.UTC()] += serveCount.Count
+	}
+
+	result := []*ServeCountPoint{}
+	for periodStart := from; periodStart.Before(to); periodStart = periodStart.Add(granularity.Duration()) {
+		result = append(result, &ServeCountPoint{PeriodStart: periodStart, Count: counts[periodStart]})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/apikey"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	// maxAPIKeyUsageDays is the longest period the usage of a key can be queried for at once
+	maxAPIKeyUsageDays = 366
+)
+
+var (
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+	ErrAPIKeyRevoked          = errors.New("api key is revoked")
+	ErrInvalidAPIKeyOwner     = errors.New("invalid api key owner")
+	ErrInvalidAPIKeyScopes    = errors.New("invalid api key scopes")
+	ErrInvalidAPIKeyQuota     = errors.New("invalid api key daily quota")
+	ErrInvalidAPIKeyUsageDays = errors.New("invalid api key usage days")
+)
+
+type APIKeySettings struct {
+	Owner       string
+	Description string
+	Scopes      []string
+	// DailyQuota is the number of requests per UTC day, 0 for no quota.
+	DailyQuota int
+}
+
+type APIKeysHandler struct {
+	apiKeysRepository repository.APIKeysRepository
+}
+
+func NewAPIKeysHandler(apiKeysRepository repository.APIKeysRepository) *APIKeysHandler {
+	return &APIKeysHandler{apiKeysRepository}
+}
+
+func validateAPIKeySettings(settings *APIKeySettings) error {
+	if strings.TrimSpace(settings.Owner) == "" {
+		return ErrInvalidAPIKeyOwner
+	}
+
+	if len(settings.Scopes) == 0 {
+		return ErrInvalidAPIKeyScopes
+	}
+	for _, scope := range settings.Scopes {
+		if !slices.Contains(apikey.Scopes, scope) {
+			return ErrInvalidAPIKeyScopes
+		}
+	}
+
+	if settings.DailyQuota < 0 {
+		return ErrInvalidAPIKeyQuota
+	}
+
+	return nil
+}
+
+// IssueKey creates a key with the settings and returns it with the key itself, which is not stored.
+func (a *APIKeysHandler) IssueKey(settings *APIKeySettings) (*repository.APIKey, string, error) {
+	if err := validateAPIKeySettings(settings); err != nil {
+		return nil, "", err
+	}
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	scopes := slices.Clone(settings.Scopes)
+	slices.Sort(scopes)
+
+	now := time.Now()
+	apiKey := &repository.APIKey{
+		ID:          primitive.NewObjectID(),
+		Prefix:      prefix,
+		Hash:        hash,
+		Owner:       strings.TrimSpace(settings.Owner),
+		Description: settings.Description,
+		Scopes:      slices.Compact(scopes),
+		DailyQuota:  settings.DailyQuota,
+		CreatedAt:   now,
+		CreatedBy:   "user.name", // TODO set user name
+		UpdatedAt:   now,
+		UpdatedBy:   "user.name", // TODO set user name
+	}
+
+	err = a.apiKeysRepository.CreateKey(apiKey)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to create api key")
+	}
+
+	return apiKey, key, nil
+}
+
+func (a *APIKeysHandler) GetKeys() ([]*repository.APIKey, error) {
+	keys, err := a.apiKeysRepository.ReadKeys(func(key *repository.APIKey) bool {
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get api keys")
+	}
+
+	return keys, nil
+}
+
+func (a *APIKeysHandler) GetKey(id primitive.ObjectID) (*repository.APIKey, error) {
+	key, err := a.apiKeysRepository.ReadKey(id)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, ErrAPIKeyNotFound
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "could not get api key by ID %v", id)
+	}
+
+	return key, nil
+}
+
+// RotateKey replaces the key with a new one, which is returned, the old key is not valid anymore. Settings and usage
+// are kept.
+func (a *APIKeysHandler) RotateKey(id primitive.ObjectID) (*repository.APIKey, string, error) {
+	apiKey, err := a.GetKey(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if apiKey.Revoked {
+		return nil, "", ErrAPIKeyRevoked
+	}
+
+	key, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	err = a.apiKeysRepository.UpdateKey(id, func(apiKey *repository.APIKey) *repository.APIKey {
+		apiKey.Prefix = prefix
+		apiKey.Hash = hash
+		apiKey.RotatedAt = now
+		apiKey.UpdatedAt = now
+		apiKey.UpdatedBy = "user.name" // TODO set user name
+		return apiKey
+	})
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, "", ErrAPIKeyNotFound
+	} else if err != nil {
+		return nil, "", errors.Wrap(err, "failed to rotate api key")
+	}
+
+	apiKey.Prefix, apiKey.Hash, apiKey.RotatedAt, apiKey.UpdatedAt = prefix, hash, now, now
+	return apiKey, key, nil
+}
+
+// RevokeKey makes the key invalid, the key is kept with its usage. Revoking a revoked key does nothing.
+func (a *APIKeysHandler) RevokeKey(id primitive.ObjectID) error {
+	err := a.apiKeysRepository.UpdateKey(id, func(apiKey *repository.APIKey) *repository.APIKey {
+		if apiKey.Revoked {
+			return apiKey
+		}
+
+		now := time.Now()
+		apiKey.Revoked = true
+		apiKey.RevokedAt = now
+		apiKey.UpdatedAt = now
+		apiKey.UpdatedBy = "user.name" // TODO set user name
+		return apiKey
+	})
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return ErrAPIKeyNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "failed to revoke api key")
+	}
+
+	return nil
+}
+
+// GetUsage returns the requests of the key per UTC day from the first to the last day, both included. Days without
+// requests are missing.
+func (a *APIKeysHandler) GetUsage(id primitive.ObjectID, firstDay time.Time, lastDay time.Time) ([]*repository.APIKeyUsage, error) {
+	if lastDay.Before(firstDay) || lastDay.Sub(firstDay) >= maxAPIKeyUsageDays*24*time.Hour {
+		return nil, ErrInvalidAPIKeyUsageDays
+	}
+
+	if _, err := a.GetKey(id); err != nil {
+		return nil, err
+	}
+
+	usage, err := a.apiKeysRepository.ReadUsage(id, firstDay.UTC().Format(apikey.DayFormat), lastDay.UTC().Format(apikey.DayFormat))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get api key usage")
+	}
+
+	return usage, nil
+}
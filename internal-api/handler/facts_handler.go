@@ -1,12 +1,21 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/cafo13/animal-facts/pkg/patch"
 	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/tracing"
+	"github.com/cafo13/animal-facts/pkg/validation"
 )
 
 var (
@@ -17,15 +26,39 @@ type Fact struct {
 	ID       primitive.ObjectID `json:"id"`
 	Fact     string             `json:"fact"`
 	Source   string             `json:"source"`
+	Animal   string             `json:"animal"`
+	Tags     []string           `json:"tags"`
+	Language string             `json:"language"`
 	Approved bool               `json:"approved"`
 }
 
+// FactContent is the content of a fact that users edit, it is the document that patches of a fact are applied to.
+type FactContent struct {
+	Fact     string   `json:"fact"`
+	Source   string   `json:"source"`
+	Animal   string   `json:"animal"`
+	Tags     []string `json:"tags"`
+	Language string   `json:"language"`
+}
+
 type FactsHandler struct {
+	ctx             context.Context
 	factsRepository repository.FactsRepository
 }
 
 func NewFactsHandler(factsRepository repository.FactsRepository) *FactsHandler {
-	return &FactsHandler{factsRepository}
+	return &FactsHandler{context.Background(), factsRepository}
+}
+
+// WithContext returns the handler running its operations with the context, e.g. to trace them as part of a request.
+func (f *FactsHandler) WithContext(ctx context.Context) *FactsHandler {
+	return &FactsHandler{ctx, f.factsRepository}
+}
+
+// trace starts the span of an operation, the returned handler runs the repository calls of the operation in the span.
+func (f *FactsHandler) trace(operation string) (*FactsHandler, trace.Span) {
+	ctx, span := tracing.Start(f.ctx, "FactsHandler."+operation)
+	return &FactsHandler{ctx, f.factsRepository.WithContext(ctx)}, span
 }
 
 func (f *FactsHandler) mapFactToHandler(fact *repository.Fact) *Fact {
@@ -36,17 +69,54 @@ func (f *FactsHandler) mapFactToHandler(fact *repository.Fact) *Fact {
 	}
 }
 
+// normalize lower cases and trims values that are used as filters, so that filtering doesn't depend on the spelling.
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func normalizeTags(tags []string) []string {
+	normalizedTags := []string{}
+	for _, tag := range tags {
+		if tag = normalize(tag); tag != "" && !slices.Contains(normalizedTags, tag) {
+			normalizedTags = append(normalizedTags, tag)
+		}
+	}
+
+	return normalizedTags
+}
+
+func normalizeLanguage(language string) string {
+	if language = normalize(language); language == "" {
+		return repository.DefaultLanguage
+	}
+
+	return language
+}
+
 func (f *FactsHandler) Create(fact *Fact) error {
+	f, span := f.trace("Create")
+	defer span.End()
+
+	now := time.Now()
 	factToCreate := &repository.Fact{
 		ID:        fact.ID,
-		Fact:      fact.Fact,
-		Source:    fact.Source,
+		Fact:      strings.TrimSpace(fact.Fact),
+		Source:    strings.TrimSpace(fact.Source),
+		Animal:    normalize(fact.Animal),
+		Tags:      normalizeTags(fact.Tags),
+		Language:  normalizeLanguage(fact.Language),
 		Approved:  fact.Approved,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 		CreatedBy: "user.name", // TODO set user name
-		UpdatedAt: time.Now(),
+		UpdatedAt: now,
 		UpdatedBy: "user.name", // TODO set user name
 	}
+	if fact.Approved {
+		factToCreate.ApprovedAt = now
+	}
+	if err := validation.ValidateFact(factToCreate); err != nil {
+		return err
+	}
 
 	err := f.factsRepository.Create(factToCreate)
 	if err != nil {
@@ -57,32 +127,116 @@ func (f *FactsHandler) Create(fact *Fact) error {
 }
 
 func (f *FactsHandler) Update(fact *Fact) error {
-	err := f.factsRepository.Update(fact.ID, func(f *repository.Fact) *repository.Fact {
-		if fact.Fact != f.Fact {
-			f.Fact = fact.Fact
+	f, span := f.trace("Update")
+	defer span.End()
+
+	content, err := normalizeContent(&FactContent{
+		Fact:     fact.Fact,
+		Source:   fact.Source,
+		Animal:   fact.Animal,
+		Tags:     fact.Tags,
+		Language: fact.Language,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = f.factsRepository.Update(fact.ID, func(f *repository.Fact) (*repository.Fact, error) {
+		return setContent(f, content), nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update fact")
+	}
+
+	return nil
+}
+
+// Patch applies the patch to the content of the fact with the ID. The patch is applied to the current content in the
+// transaction of the update, so that concurrent changes are not lost. Errors of the patch are returned wrapped, like
+// patch.ErrTestFailed, and so are the validation errors of the patched content.
+func (f *FactsHandler) Patch(id primitive.ObjectID, factPatch patch.Patch) error {
+	f, span := f.trace("Patch")
+	defer span.End()
+
+	err := f.factsRepository.Update(id, func(fact *repository.Fact) (*repository.Fact, error) {
+		document, err := json.Marshal(&FactContent{
+			Fact:     fact.Fact,
+			Source:   fact.Source,
+			Animal:   fact.Animal,
+			Tags:     normalizeTags(fact.Tags),
+			Language: fact.Language,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal content of fact")
 		}
-		if fact.Source != f.Source {
-			f.Source = fact.Source
+
+		patchedDocument, err := factPatch.Apply(document)
+		if err != nil {
+			return nil, err
 		}
-		f.UpdatedAt = time.Now()
-		f.UpdatedBy = "user.name" // TODO set user name
-		return f
+
+		patchedContent := &FactContent{}
+		err = validation.DecodeJSON(bytes.NewReader(patchedDocument), patchedContent)
+		var validationErrs validation.Errors
+		if err != nil && !errors.As(err, &validationErrs) {
+			return nil, errors.Wrap(patch.ErrInvalidPatch, "patched fact is not a JSON object")
+		} else if err != nil {
+			return nil, err
+		}
+
+		content, err := normalizeContent(patchedContent)
+		if err != nil {
+			return nil, err
+		}
+
+		return setContent(fact, content), nil
 	})
 	if err != nil {
-		return errors.Wrapf(err, "failed to update fact")
+		return errors.Wrapf(err, "failed to patch fact")
 	}
 
 	return nil
 }
 
+// normalizeContent returns the normalized content as repository fact if it is valid.
+func normalizeContent(content *FactContent) (*repository.Fact, error) {
+	normalizedContent := &repository.Fact{
+		Fact:     strings.TrimSpace(content.Fact),
+		Source:   strings.TrimSpace(content.Source),
+		Animal:   normalize(content.Animal),
+		Tags:     normalizeTags(content.Tags),
+		Language: normalizeLanguage(content.Language),
+	}
+	if err := validation.ValidateFact(normalizedContent); err != nil {
+		return nil, err
+	}
+
+	return normalizedContent, nil
+}
+
+func setContent(fact *repository.Fact, content *repository.Fact) *repository.Fact {
+	fact.Fact = content.Fact
+	fact.Source = content.Source
+	fact.Animal = content.Animal
+	fact.Tags = content.Tags
+	fact.Language = content.Language
+	fact.UpdatedAt = time.Now()
+	fact.UpdatedBy = "user.name" // TODO set user name
+	return fact
+}
+
 func (f *FactsHandler) Approve(factID primitive.ObjectID) error {
-	err := f.factsRepository.Update(factID, func(f *repository.Fact) *repository.Fact {
+	f, span := f.trace("Approve")
+	defer span.End()
+
+	err := f.factsRepository.Update(factID, func(f *repository.Fact) (*repository.Fact, error) {
 		if !f.Approved {
 			f.Approved = true
+			f.ApprovedAt = time.Now()
 		}
 		f.UpdatedAt = time.Now()
 		f.UpdatedBy = "user.name" // TODO set user name
-		return f
+		return f, nil
 	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to approve fact")
@@ -92,13 +246,17 @@ func (f *FactsHandler) Approve(factID primitive.ObjectID) error {
 }
 
 func (f *FactsHandler) Unapprove(factID primitive.ObjectID) error {
-	err := f.factsRepository.Update(factID, func(f *repository.Fact) *repository.Fact {
+	f, span := f.trace("Unapprove")
+	defer span.End()
+
+	err := f.factsRepository.Update(factID, func(f *repository.Fact) (*repository.Fact, error) {
 		if f.Approved {
 			f.Approved = false
+			f.ApprovedAt = time.Time{}
 		}
 		f.UpdatedAt = time.Now()
 		f.UpdatedBy = "user.name" // TODO set user name
-		return f
+		return f, nil
 	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to unapprove fact")
@@ -108,10 +266,16 @@ func (f *FactsHandler) Unapprove(factID primitive.ObjectID) error {
 }
 
 func (f *FactsHandler) Delete(id primitive.ObjectID) error {
+	f, span := f.trace("Delete")
+	defer span.End()
+
 	return f.factsRepository.Delete(id)
 }
 
 func (f *FactsHandler) GetAll() ([]*repository.Fact, error) {
+	f, span := f.trace("GetAll")
+	defer span.End()
+
 	repositoryFacts, err := f.factsRepository.ReadAll()
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not get all facts")
@@ -119,3 +283,23 @@ func (f *FactsHandler) GetAll() ([]*repository.Fact, error) {
 
 	return repositoryFacts, nil
 }
+
+// GetReviewQueue returns all facts that are either not approved yet or flagged because of open reports.
+func (f *FactsHandler) GetReviewQueue() ([]*repository.Fact, error) {
+	f, span := f.trace("GetReviewQueue")
+	defer span.End()
+
+	repositoryFacts, err := f.factsRepository.ReadAll()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get all facts")
+	}
+
+	reviewQueue := []*repository.Fact{}
+	for _, fact := range repositoryFacts {
+		if !fact.Approved || fact.Flagged {
+			reviewQueue = append(reviewQueue, fact)
+		}
+	}
+
+	return reviewQueue, nil
+}
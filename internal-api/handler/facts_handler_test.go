@@ -0,0 +1,107 @@
+package handler_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/patch"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/validation"
+)
+
+func TestFactsHandler_Patch(t *testing.T) {
+	id := primitive.NewObjectID()
+	original := repository.Fact{
+		ID:       id,
+		Fact:     "Octopuses have three hearts.",
+		Source:   "https://factanimal.com/octopus/",
+		Animal:   "octopus",
+		Tags:     []string{"ocean"},
+		Language: "en",
+		Approved: true,
+	}
+
+	tests := []struct {
+		name      string
+		mediaType string
+		patch     string
+		want      func(fact *repository.Fact)
+		wantErr   error
+	}{
+		{
+			name:      "merge patch keeps omitted fields",
+			mediaType: patch.MediaTypeMergePatch,
+			patch:     `{"fact":"  Octopuses have blue blood. ","animal":null}`,
+			want: func(fact *repository.Fact) {
+				fact.Fact = "Octopuses have blue blood."
+				fact.Animal = ""
+			},
+		},
+		{
+			name:      "json patch with successful test",
+			mediaType: patch.MediaTypeJSONPatch,
+			patch:     `[{"op":"test","path":"/source","value":"https://factanimal.com/octopus/"},{"op":"add","path":"/tags/-","value":"Cephalopod"}]`,
+			want: func(fact *repository.Fact) {
+				fact.Tags = []string{"ocean", "cephalopod"}
+			},
+		},
+		{
+			name:      "json patch with failed test",
+			mediaType: patch.MediaTypeJSONPatch,
+			patch:     `[{"op":"replace","path":"/fact","value":"Octopuses have blue blood."},{"op":"test","path":"/animal","value":"squid"}]`,
+			wantErr:   patch.ErrTestFailed,
+		},
+		{
+			name:      "patched fact is not valid",
+			mediaType: patch.MediaTypeMergePatch,
+			patch:     `{"source":null}`,
+			wantErr:   validation.Errors{},
+		},
+		{
+			name:      "unknown field",
+			mediaType: patch.MediaTypeJSONPatch,
+			patch:     `[{"op":"add","path":"/approved","value":false}]`,
+			wantErr:   validation.Errors{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := original
+			factsRepository := repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{id: &fact}, false)
+			factsHandler := handler.NewFactsHandler(factsRepository)
+
+			factPatch, err := patch.Parse(tt.mediaType, []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+
+			err = factsHandler.Patch(id, factPatch)
+			got, _ := factsRepository.ReadOne(id)
+			if tt.wantErr != nil {
+				var validationErrs validation.Errors
+				if _, wantValidation := tt.wantErr.(validation.Errors); (wantValidation && !errors.As(err, &validationErrs)) ||
+					(!wantValidation && !errors.Is(err, tt.wantErr)) {
+					t.Errorf("Patch() error = %v, want %v", err, tt.wantErr)
+				}
+				if got.Revision != 0 || got.Fact != original.Fact {
+					t.Errorf("Patch() changed the fact to %+v, want the fact unchanged", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Patch() unexpected error = %v", err)
+			}
+
+			want := original
+			tt.want(&want)
+			if got.Fact != want.Fact || got.Source != want.Source || got.Animal != want.Animal ||
+				!reflect.DeepEqual(got.Tags, want.Tags) || got.Language != want.Language || !got.Approved || got.Revision != 1 {
+				t.Errorf("Patch() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+var (
+	ErrReportNotFound = errors.New("report not found")
+	ErrReportNotOpen  = errors.New("report is not open")
+)
+
+type ReportsHandler struct {
+	factsRepository   repository.FactsRepository
+	reportsRepository repository.ReportsRepository
+}
+
+func NewReportsHandler(factsRepository repository.FactsRepository, reportsRepository repository.ReportsRepository) *ReportsHandler {
+	return &ReportsHandler{factsRepository, reportsRepository}
+}
+
+// GetAll returns all reports with the given status, or all reports if status is empty.
+func (r *ReportsHandler) GetAll(status repository.ReportStatus) ([]*repository.Report, error) {
+	reports, err := r.reportsRepository.ReadMany(func(report *repository.Report) bool {
+		return status == "" || report.Status == status
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get reports")
+	}
+
+	return reports, nil
+}
+
+func (r *ReportsHandler) Resolve(reportID primitive.ObjectID) error {
+	return r.close(reportID, repository.ReportStatusResolved)
+}
+
+func (r *ReportsHandler) Dismiss(reportID primitive.ObjectID) error {
+	return r.close(reportID, repository.ReportStatusDismissed)
+}
+
+// UnapproveFact unapproves the fact of the report, so that it is no longer available in the public API, and resolves the report.
+func (r *ReportsHandler) UnapproveFact(reportID primitive.ObjectID) error {
+	report, err := r.getOpenReport(reportID)
+	if err != nil {
+		return err
+	}
+
+	err = r.factsRepository.Update(report.FactID, func(f *repository.Fact) (*repository.Fact, error) {
+		f.Approved = false
+		f.ApprovedAt = time.Time{}
+		f.UpdatedAt = time.Now()
+		f.UpdatedBy = "user.name" // TODO set user name
+		return f, nil
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to unapprove fact of report")
+	}
+
+	return r.close(reportID, repository.ReportStatusResolved)
+}
+
+func (r *ReportsHandler) getOpenReport(reportID primitive.ObjectID) (*repository.Report, error) {
+	report, err := r.reportsRepository.ReadOne(reportID)
+	if errors.Is(err, repository.ErrReportNotFound) {
+		return nil, ErrReportNotFound
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "could not get report by ID %v", reportID)
+	}
+
+	if report.Status != repository.ReportStatusOpen {
+		return nil, ErrReportNotOpen
+	}
+
+	return report, nil
+}
+
+// close sets the status of an open report and removes the review flag of its fact once no open reports are left.
+func (r *ReportsHandler) close(reportID primitive.ObjectID, status repository.ReportStatus) error {
+	report, err := r.getOpenReport(reportID)
+	if err != nil {
+		return err
+	}
+
+	err = r.reportsRepository.Update(reportID, func(report *repository.Report) *repository.Report {
+		report.Status = status
+		report.UpdatedAt = time.Now()
+		report.UpdatedBy = "user.name" // TODO set user name
+		return report
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set status of report to %s", status)
+	}
+
+	openReports, err := r.reportsRepository.CountOpen(report.FactID)
+	if err != nil {
+		return errors.Wrapf(err, "could not count open reports of fact %v", report.FactID)
+	}
+
+	if openReports == 0 {
+		err = r.factsRepository.Update(report.FactID, func(f *repository.Fact) (*repository.Fact, error) {
+			f.Flagged = false
+			return f, nil
+		})
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return errors.Wrapf(err, "failed to remove flag of fact %v", report.FactID)
+		}
+	}
+
+	return nil
+}
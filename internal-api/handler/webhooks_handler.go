@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	minWebhookSecretLength = 16
+	maxWebhookDeliveries   = 100
+)
+
+var (
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL           = errors.New("invalid webhook url")
+	ErrInvalidWebhookEventTypes    = errors.New("invalid webhook event types")
+	ErrInvalidWebhookSecret        = errors.New("invalid webhook secret")
+)
+
+type WebhookSubscription struct {
+	URL        string
+	EventTypes []string
+	// Secret signs the deliveries, a random secret is generated if it is empty.
+	Secret string
+	Active bool
+}
+
+type WebhooksHandler struct {
+	webhooksRepository repository.WebhooksRepository
+}
+
+func NewWebhooksHandler(webhooksRepository repository.WebhooksRepository) *WebhooksHandler {
+	return &WebhooksHandler{webhooksRepository}
+}
+
+func validateWebhookSubscription(subscription *WebhookSubscription) error {
+	webhookURL, err := url.Parse(subscription.URL)
+	if err != nil || (webhookURL.Scheme != "https" && webhookURL.Scheme != "http") || webhookURL.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	if len(subscription.EventTypes) == 0 {
+		return ErrInvalidWebhookEventTypes
+	}
+	for _, eventType := range subscription.EventTypes {
+		if !slices.Contains(events.Types, events.Type(eventType)) {
+			return ErrInvalidWebhookEventTypes
+		}
+	}
+
+	if subscription.Secret != "" && len(subscription.Secret) < minWebhookSecretLength {
+		return ErrInvalidWebhookSecret
+	}
+
+	return nil
+}
+
+func normalizeEventTypes(eventTypes []string) []string {
+	normalizedEventTypes := slices.Clone(eventTypes)
+	slices.Sort(normalizedEventTypes)
+	return slices.Compact(normalizedEventTypes)
+}
+
+// CreateSubscription creates the subscription and returns it with its secret.
+func (w *WebhooksHandler) CreateSubscription(subscription *WebhookSubscription) (*repository.WebhookSubscription, error) {
+	if err := validateWebhookSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	secret := subscription.Secret
+	if secret == "" {
+		randomSecret := make([]byte, 32)
+		if _, err := rand.Read(randomSecret); err != nil {
+			return nil, errors.Wrap(err, "failed to generate webhook secret")
+		}
+		secret = hex.EncodeToString(randomSecret)
+	}
+
+	now := time.Now()
+	subscriptionToCreate := &repository.WebhookSubscription{
+		ID:         primitive.NewObjectID(),
+		URL:        subscription.URL,
+		EventTypes: normalizeEventTypes(subscription.EventTypes),
+		Secret:     secret,
+		Active:     subscription.Active,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		UpdatedBy:  "user.name", // TODO set user name
+	}
+
+	err := w.webhooksRepository.CreateSubscription(subscriptionToCreate)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create webhook subscription")
+	}
+
+	return subscriptionToCreate, nil
+}
+
+func (w *WebhooksHandler) GetSubscriptions() ([]*repository.WebhookSubscription, error) {
+	subscriptions, err := w.webhooksRepository.ReadSubscriptions(func(subscription *repository.WebhookSubscription) bool {
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get webhook subscriptions")
+	}
+
+	return subscriptions, nil
+}
+
+func (w *WebhooksHandler) GetSubscription(id primitive.ObjectID) (*repository.WebhookSubscription, error) {
+	subscription, err := w.webhooksRepository.ReadSubscription(id)
+	if errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+		return nil, ErrWebhookSubscriptionNotFound
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "could not get webhook subscription by ID %v", id)
+	}
+
+	return subscription, nil
+}
+
+// UpdateSubscription replaces url, event types and active flag, the secret is only replaced if it is set.
+func (w *WebhooksHandler) UpdateSubscription(id primitive.ObjectID, subscription *WebhookSubscription) error {
+	if err := validateWebhookSubscription(subscription); err != nil {
+		return err
+	}
+
+	err := w.webhooksRepository.UpdateSubscription(id, func(s *repository.WebhookSubscription) *repository.WebhookSubscription {
+		s.URL = subscription.URL
+		s.EventTypes = normalizeEventTypes(subscription.EventTypes)
+		if subscription.Secret != "" {
+			s.Secret = subscription.Secret
+		}
+		s.Active = subscription.Active
+		s.UpdatedAt = time.Now()
+		s.UpdatedBy = "user.name" // TODO set user name
+		return s
+	})
+	if errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+		return ErrWebhookSubscriptionNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "failed to update webhook subscription")
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes the subscription with its deliveries.
+func (w *WebhooksHandler) DeleteSubscription(id primitive.ObjectID) error {
+	err := w.webhooksRepository.DeleteSubscription(id)
+	if errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+		return ErrWebhookSubscriptionNotFound
+	} else if err != nil {
+		return errors.Wrap(err, "failed to delete webhook subscription")
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the latest deliveries of the subscription, all statuses if status is empty. The dead-lettered
+// deliveries are the deliveries with status dead.
+func (w *WebhooksHandler) GetDeliveries(subscriptionID primitive.ObjectID, status repository.WebhookDeliveryStatus) ([]*repository.WebhookDelivery, error) {
+	if _, err := w.GetSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := w.webhooksRepository.ReadDeliveries(subscriptionID, status, maxWebhookDeliveries)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver sends the delivery of the subscription again as soon as possible with the full number of attempts,
+// regardless of its status.
+func (w *WebhooksHandler) Redeliver(subscriptionID primitive.ObjectID, deliveryID primitive.ObjectID) error {
+	delivery, err := w.webhooksRepository.ReadDelivery(deliveryID)
+	if errors.Is(err, repository.ErrWebhookDeliveryNotFound) || (err == nil && delivery.SubscriptionID != subscriptionID) {
+		return ErrWebhookDeliveryNotFound
+	} else if err != nil {
+		return errors.Wrapf(err, "could not get webhook delivery by ID %v", deliveryID)
+	}
+
+	err = w.webhooksRepository.UpdateDelivery(deliveryID, func(delivery *repository.WebhookDelivery) *repository.WebhookDelivery {
+		now := time.Now()
+		delivery.Status = repository.WebhookDeliveryStatusPending
+		delivery.Failures = 0
+		delivery.NextAttemptAt = now
+		delivery.UpdatedAt = now
+		return delivery
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to redeliver webhook delivery")
+	}
+
+	return nil
+}
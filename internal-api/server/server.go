@@ -2,24 +2,47 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/labstack/echo/v4"
 	"github.com/neko-neko/echo-logrus/v2/log"
 	"github.com/pkg/errors"
 
+	animalfacts "github.com/cafo13/animal-facts"
+	grpcapi "github.com/cafo13/animal-facts/grpc-api/api"
+	grpcserver "github.com/cafo13/animal-facts/grpc-api/server"
 	"github.com/cafo13/animal-facts/internal-api/api"
 	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/internal-api/webhook"
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/health"
 	logger "github.com/cafo13/animal-facts/pkg/log"
+	"github.com/cafo13/animal-facts/pkg/metrics"
+	"github.com/cafo13/animal-facts/pkg/middleware"
+	"github.com/cafo13/animal-facts/pkg/outbox"
 	"github.com/cafo13/animal-facts/pkg/repository"
 	"github.com/cafo13/animal-facts/pkg/router"
 	"github.com/cafo13/animal-facts/pkg/service"
+	"github.com/cafo13/animal-facts/pkg/tracing"
+	publichandler "github.com/cafo13/animal-facts/public-api/handler"
 )
 
 var (
-	mongoDbUri string
+	mongoDbUri  string
+	grpcPort    int
+	metricsPort int
+
+	tracingExporter = tracing.ExporterNone
+	// shutdownDrainPeriod is the time the router keeps serving requests after the readiness check fails on shutdown
+	shutdownDrainPeriod = 5 * time.Second
+
+	webhookMaxAttempts = webhook.DefaultMaxAttempts
+	outboxHTTPSinkURL  string
 )
 
 // Run
@@ -42,12 +65,15 @@ func Run() {
 
 	loadEnv()
 
-	factsRouter, err := setupServiceDependencies()
+	healthChecks := health.New()
+	factsRouter, workers, err := setupServiceDependencies(healthChecks)
 	if err != nil {
 		panic(errors.Wrap(err, "failed to setup service dependencies"))
 	}
 
-	svc := service.NewService(factsRouter)
+	svc := service.NewService(factsRouter, workers...)
+	svc.SetDrainPeriod(shutdownDrainPeriod)
+	healthChecks.AddLivenessCheck(health.Check{Name: "workers", Func: svc.CheckWorkers})
 
 	apiPortStr, ok := os.LookupEnv("INTERNAL_API_PORT")
 	if !ok {
@@ -81,26 +107,149 @@ func loadEnv() {
 	if !ok {
 		panic("MONGODB_URI environment variable is not set")
 	}
+
+	grpcPortStr, ok := os.LookupEnv("GRPC_PORT")
+	if !ok {
+		grpcPortStr = "9090"
+		log.Logger().Infof("GRPC_PORT environment variable is not set, using default value %s", grpcPortStr)
+	}
+
+	grpcPort, err = strconv.Atoi(grpcPortStr)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to parse GRPC_PORT environment variable, only integer values are allowed (like 9090)"))
+	}
+
+	metricsPortStr, ok := os.LookupEnv("INTERNAL_API_METRICS_PORT")
+	if !ok {
+		metricsPortStr = "9100"
+		log.Logger().Infof("INTERNAL_API_METRICS_PORT environment variable is not set, using default value %s", metricsPortStr)
+	}
+
+	metricsPort, err = strconv.Atoi(metricsPortStr)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to parse INTERNAL_API_METRICS_PORT environment variable, only integer values are allowed (like 9100)"))
+	}
+
+	webhookMaxAttemptsStr, ok := os.LookupEnv("WEBHOOK_MAX_ATTEMPTS")
+	if ok && webhookMaxAttemptsStr != "" {
+		webhookMaxAttempts, err = strconv.Atoi(webhookMaxAttemptsStr)
+		if err != nil || webhookMaxAttempts < 1 {
+			panic(fmt.Sprintf("failed to parse WEBHOOK_MAX_ATTEMPTS environment variable, only positive integer values are allowed (like %d)", webhook.DefaultMaxAttempts))
+		}
+	}
+
+	outboxHTTPSinkURL = os.Getenv("OUTBOX_HTTP_SINK_URL")
+
+	tracingExporterStr, ok := os.LookupEnv("TRACING_EXPORTER")
+	if ok && tracingExporterStr != "" {
+		var err error
+		tracingExporter, err = tracing.ParseExporter(tracingExporterStr)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to parse TRACING_EXPORTER environment variable"))
+		}
+	}
+
+	shutdownDrainPeriodStr, ok := os.LookupEnv("SHUTDOWN_DRAIN_PERIOD")
+	if ok && shutdownDrainPeriodStr != "" {
+		var err error
+		shutdownDrainPeriod, err = time.ParseDuration(shutdownDrainPeriodStr)
+		if err != nil || shutdownDrainPeriod < 0 {
+			panic("failed to parse SHUTDOWN_DRAIN_PERIOD environment variable, only durations are allowed (like 5s)")
+		}
+	}
 }
 
-func setupServiceDependencies() (*router.Router, error) {
-	factsRepository, err := repository.NewMongoDBFactsRepository(mongoDbUri)
+func setupServiceDependencies(healthChecks *health.Health) (*router.Router, []service.Worker, error) {
+	tracingProvider, err := tracing.NewProvider(context.Background(), tracingExporter, "internal-api", animalfacts.Version())
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to setup mongo db facts repository")
+		return nil, nil, errors.Wrap(err, "failed to setup tracing")
 	}
 
+	metricsRegistry := metrics.NewRegistry("internal-api", animalfacts.Version())
+	mongoDBConnection, err := repository.NewMongoDBConnection(mongoDbUri, metrics.NewPoolMonitor(metricsRegistry), tracing.NewCommandMonitor())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup mongo db connection")
+	}
+
+	factsRepository := tracing.NewTracingFactsRepository(
+		metrics.NewInstrumentingFactsRepository(repository.NewMongoDBFactsRepositoryFromConnection(mongoDBConnection), metricsRegistry),
+	)
+	reportsRepository := repository.NewMongoDBReportsRepository(mongoDBConnection)
+	serveStatsRepository, err := repository.NewMongoDBServeStatsRepository(mongoDBConnection)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup serve stats repository")
+	}
+
+	// the writes of the facts repository append their events to the outbox, the relay publishes them to the event bus
+	eventBus := events.NewBus()
+	outboxSinks := []outbox.Sink{outbox.NewLogSink(), outbox.NewBusSink(eventBus)}
+	if outboxHTTPSinkURL != "" {
+		outboxSinks = append(outboxSinks, outbox.NewHTTPSink(outboxHTTPSinkURL))
+	}
+	outboxRepository, err := repository.NewMongoDBOutboxRepository(mongoDBConnection)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup outbox repository")
+	}
+	outboxRelay := outbox.NewRelay(outboxRepository, outboxSinks...)
+
 	factsHandler := handler.NewFactsHandler(factsRepository)
 	factsApi := api.NewFactsApi(factsHandler)
-	factsApi.SetupRoutes()
-	factsRouter := router.NewRouter()
-	for _, route := range factsApi.GetRoutes() {
-		err := factsRouter.RegisterRoute(route)
-		if err != nil {
-			log.Logger().WithError(err).Errorf("failed to register route %s", route.Path)
-		} else {
-			log.Logger().Infof("registered route %s", route.Path)
-		}
+
+	reportsHandler := handler.NewReportsHandler(factsRepository, reportsRepository)
+	reportsApi := api.NewReportsApi(reportsHandler)
+
+	analyticsHandler := handler.NewAnalyticsHandler(serveStatsRepository)
+	analyticsApi := api.NewAnalyticsApi(analyticsHandler)
+
+	eventsApi := api.NewEventsApi(eventBus)
+
+	webhooksRepository, err := repository.NewMongoDBWebhooksRepository(mongoDBConnection)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup webhooks repository")
 	}
+	webhooksHandler := handler.NewWebhooksHandler(webhooksRepository)
+	webhooksApi := api.NewWebhooksApi(webhooksHandler)
+	webhookDispatcher := webhook.NewDispatcher(webhooksRepository, eventBus, webhookMaxAttempts)
+
+	apiKeysRepository, err := repository.NewMongoDBAPIKeysRepository(mongoDBConnection)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup api keys repository")
+	}
+	apiKeysHandler := handler.NewAPIKeysHandler(apiKeysRepository)
+	apiKeysApi := api.NewAPIKeysApi(apiKeysHandler)
+
+	healthChecks.AddReadinessCheck(health.Check{Name: "mongodb", Func: factsRepository.Ping})
+	healthChecks.AddReadinessCheck(health.Check{Name: "jwks", Func: middleware.CheckJWKS, Timeout: 3 * time.Second, CacheTTL: 30 * time.Second})
+	healthApi := health.NewHealthApi(healthChecks, "/health-internal").ShowErrors()
+
+	factsRouter := router.NewRouter("internal-api")
+	factsRouter.Use(metricsRegistry.Middleware())
+	err = factsRouter.Mount(api.NewDocsApi(), healthApi)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to mount routes")
+	}
+	// the routes of the internal api need a valid token of a user with the scope of the route
+	err = factsRouter.Version("v1").
+		Authorize(func(scope string) []echo.MiddlewareFunc {
+			return []echo.MiddlewareFunc{middleware.EnsureValidToken(), middleware.VerifyScope(scope)}
+		}).
+		Mount(factsApi, reportsApi, analyticsApi, eventsApi, webhooksApi, apiKeysApi)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to mount routes of api v1")
+	}
+
+	// the grpc services of the public and the internal facts run in this process, as the admin service needs the
+	// same jwt validation as the internal api
+	grpcServer := grpcserver.NewServer(
+		grpcPort,
+		grpcapi.NewFactsService(publichandler.NewFactsHandler(factsRepository)),
+		grpcapi.NewFactsAdminService(factsHandler),
+		middleware.NewJWTValidator().ValidateToken,
+	)
+
+	metricsServer := metrics.NewServer(metricsPort, metricsRegistry)
+	metricsServer.Handle("/debug/routes", factsRouter.RoutesHandler())
+	factsGauges := metrics.NewFactsGauges(factsRepository, metricsRegistry)
 
-	return factsRouter, nil
+	return factsRouter, []service.Worker{eventBus, outboxRelay, webhookDispatcher, grpcServer, metricsServer, factsGauges, tracingProvider, healthChecks}, nil
 }
@@ -0,0 +1,341 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	DefaultMaxAttempts = 8
+
+	defaultInitialBackoff = time.Minute
+	defaultMaxBackoff     = 6 * time.Hour
+	defaultPollInterval   = 5 * time.Second
+	defaultRequestTimeout = 10 * time.Second
+	defaultConcurrency    = 8
+	maxLoggedAttempts     = 50
+	userAgent             = "animal-facts-webhooks"
+)
+
+// Dispatcher creates a delivery for every active subscription of an event published to the event bus and sends the
+// pending deliveries in the background. Failed deliveries are retried with exponential backoff and marked as dead
+// after maxAttempts failures.
+type Dispatcher struct {
+	webhooksRepository repository.WebhooksRepository
+	eventBus           *events.Bus
+	client             *http.Client
+	maxAttempts        int
+	initialBackoff     time.Duration
+	maxBackoff         time.Duration
+	pollInterval       time.Duration
+	concurrency        int
+	// wakeup makes the sender look for due deliveries immediately, e.g. after new deliveries were created
+	wakeup chan struct{}
+}
+
+func NewDispatcher(webhooksRepository repository.WebhooksRepository, eventBus *events.Bus, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		webhooksRepository: webhooksRepository,
+		eventBus:           eventBus,
+		client: &http.Client{
+			Timeout: defaultRequestTimeout,
+			// redirects are not followed, receivers have to answer at the URL of the subscription
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts:    maxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		pollInterval:   defaultPollInterval,
+		concurrency:    defaultConcurrency,
+		wakeup:         make(chan struct{}, 1),
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) error {
+	errgrp, ctx := errgroup.WithContext(ctx)
+	errgrp.Go(func() error {
+		d.createDeliveries(ctx)
+		return nil
+	})
+	errgrp.Go(func() error {
+		d.sendDeliveries(ctx)
+		return nil
+	})
+
+	return errgrp.Wait()
+}
+
+// createDeliveries subscribes to the event bus until the context is done. If the subscription is closed because
+// events came in faster than the deliveries were created, it resumes after the last handled event.
+func (d *Dispatcher) createDeliveries(ctx context.Context) {
+	var lastEventID uint64
+	for ctx.Err() == nil {
+		subscription := d.eventBus.Subscribe(lastEventID)
+		for event := range subscription.Events {
+			lastEventID = event.ID
+			if err := d.createDeliveriesOfEvent(event); err != nil {
+				log.Logger().WithError(err).Errorf("failed to create webhook deliveries of event %d", event.ID)
+			}
+		}
+		subscription.Close()
+	}
+}
+
+func (d *Dispatcher) createDeliveriesOfEvent(event events.Event) error {
+	subscriptions, err := d.webhooksRepository.ReadSubscriptions(func(subscription *repository.WebhookSubscription) bool {
+		return subscription.Active && slices.Contains(subscription.EventTypes, string(event.Type))
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not get webhook subscriptions")
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	data, err := payloadData(event.Data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]*repository.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		id := deliveryID(event, subscription.ID)
+		payload, err := json.Marshal(Payload{
+			ID:     id.Hex(),
+			Type:   string(event.Type),
+			FactID: event.FactID,
+			Time:   event.Time,
+			Data:   data,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal webhook payload")
+		}
+
+		deliveries = append(deliveries, &repository.WebhookDelivery{
+			ID:             id,
+			SubscriptionID: subscription.ID,
+			EventType:      string(event.Type),
+			Payload:        string(payload),
+			Status:         repository.WebhookDeliveryStatusPending,
+			Attempts:       []repository.WebhookAttempt{},
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+
+	if err := d.webhooksRepository.CreateDeliveries(deliveries); err != nil {
+		return errors.Wrap(err, "failed to create webhook deliveries")
+	}
+
+	select {
+	case d.wakeup <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// payloadData maps the data of an event, which is the fact of the repository after the write, to its public
+// representation. Facts that are not approved have no data, so that their content is not sent to the receivers before
+// it was approved or after it was unapproved.
+func payloadData(data interface{}) (*Fact, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	// the data is the JSON of the fact if the event comes from the outbox
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event data")
+	}
+	var fact repository.Fact
+	if err := json.Unmarshal(encoded, &fact); err != nil {
+		return nil, errors.Wrap(err, "event data is no fact")
+	}
+	if !fact.Approved {
+		return nil, nil
+	}
+
+	tags := fact.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &Fact{
+		ID:       fact.ID.Hex(),
+		Fact:     fact.Fact,
+		Source:   fact.Source,
+		Animal:   fact.Animal,
+		Tags:     tags,
+		Language: fact.Language,
+	}, nil
+}
+
+// deliveryID returns the ID of the delivery of the event to the subscription. Events with a key, e.g. the ID of their
+// outbox event, get the same ID every time they are published, so that an event that is published again after a
+// restart of the relay is not delivered twice.
+func deliveryID(event events.Event, subscriptionID primitive.ObjectID) primitive.ObjectID {
+	if event.Key == "" {
+		return primitive.NewObjectID()
+	}
+
+	hash := sha256.Sum256([]byte(event.Key + ":" + subscriptionID.Hex()))
+	var id primitive.ObjectID
+	copy(id[:], hash[:len(id)])
+	return id
+}
+
+// sendDeliveries sends the due deliveries until the context is done.
+func (d *Dispatcher) sendDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wakeup:
+		}
+
+		if err := d.sendDue(ctx); err != nil {
+			log.Logger().WithError(err).Error("failed to send due webhook deliveries")
+		}
+	}
+}
+
+// sendDue claims and sends due deliveries concurrently until no delivery is due.
+func (d *Dispatcher) sendDue(ctx context.Context) error {
+	errgrp := errgroup.Group{}
+	errgrp.SetLimit(d.concurrency)
+	defer errgrp.Wait()
+
+	for ctx.Err() == nil {
+		// the lease is longer than an attempt can take, so that claimed deliveries are retried if the process dies
+		delivery, err := d.webhooksRepository.ClaimDueDelivery(time.Now(), 2*defaultRequestTimeout)
+		if errors.Is(err, repository.ErrWebhookDeliveryNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		errgrp.Go(func() error {
+			if err := d.send(ctx, delivery); err != nil {
+				log.Logger().WithError(err).Errorf("failed to send webhook delivery %s", delivery.ID.Hex())
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *repository.WebhookDelivery) error {
+	subscription, err := d.webhooksRepository.ReadSubscription(delivery.SubscriptionID)
+	if err != nil && !errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+		return errors.Wrap(err, "could not get webhook subscription")
+	}
+
+	var attempt repository.WebhookAttempt
+	switch {
+	case subscription == nil:
+		attempt = repository.WebhookAttempt{At: time.Now(), Error: "subscription was deleted"}
+	case !subscription.Active:
+		// deliveries of inactive subscriptions are not retried, they can be redelivered after the subscription is activated
+		attempt = repository.WebhookAttempt{At: time.Now(), Error: "subscription is not active"}
+	default:
+		attempt = d.post(ctx, subscription, delivery)
+		if ctx.Err() != nil {
+			// the attempt was canceled by the shutdown, the delivery is sent again when the lease expires
+			return nil
+		}
+	}
+
+	return d.webhooksRepository.UpdateDelivery(delivery.ID, func(delivery *repository.WebhookDelivery) *repository.WebhookDelivery {
+		delivery.Attempts = append(delivery.Attempts, attempt)
+		if len(delivery.Attempts) > maxLoggedAttempts {
+			delivery.Attempts = delivery.Attempts[len(delivery.Attempts)-maxLoggedAttempts:]
+		}
+		delivery.UpdatedAt = time.Now()
+
+		switch {
+		case attempt.Error == "":
+			delivery.Status = repository.WebhookDeliveryStatusSucceeded
+		case subscription == nil || !subscription.Active:
+			delivery.Status = repository.WebhookDeliveryStatusDead
+		default:
+			delivery.Failures++
+			if delivery.Failures >= d.maxAttempts {
+				delivery.Status = repository.WebhookDeliveryStatusDead
+			} else {
+				delivery.NextAttemptAt = attempt.At.Add(d.backoff(delivery.Failures))
+			}
+		}
+		return delivery
+	})
+}
+
+// post sends the delivery to the receiver, the attempt has an error if the receiver didn't respond with status 2xx.
+func (d *Dispatcher) post(ctx context.Context, subscription *repository.WebhookSubscription, delivery *repository.WebhookDelivery) repository.WebhookAttempt {
+	start := time.Now()
+	attempt := repository.WebhookAttempt{At: start}
+
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set(HeaderID, delivery.ID.Hex())
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(start.Unix(), 10))
+	request.Header.Set(HeaderSignature, Sign(subscription.Secret, start, body))
+
+	response, err := d.client.Do(request)
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer response.Body.Close()
+	// the body is drained, so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	attempt.StatusCode = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("receiver responded with status %d", response.StatusCode)
+	}
+
+	return attempt
+}
+
+// backoff doubles the wait time after every failure, starting with initialBackoff.
+func (d *Dispatcher) backoff(failures int) time.Duration {
+	backoff := d.initialBackoff
+	for i := 1; i < failures && backoff < d.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, d.maxBackoff)
+}
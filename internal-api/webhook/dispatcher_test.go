@@ -0,0 +1,279 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/internal-api/handler"
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// receiver records the deliveries it receives and responds with the status codes in order, the last one repeats.
+type receiver struct {
+	t           *testing.T
+	statusCodes []int
+	mutex       sync.Mutex
+	requests    int
+	payloads    []Payload
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	if err := Verify(testSecret, req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderSignature), body); err != nil {
+		r.t.Errorf("delivery has an invalid signature: %v", err)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		r.t.Errorf("delivery has an invalid payload: %v", err)
+	}
+	if req.Header.Get(HeaderID) != payload.ID || req.Header.Get(HeaderEvent) != payload.Type {
+		r.t.Errorf("headers %v don't match the payload %+v", req.Header, payload)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.payloads = append(r.payloads, payload)
+	w.WriteHeader(r.statusCodes[min(r.requests, len(r.statusCodes)-1)])
+	r.requests++
+}
+
+func (r *receiver) received() []Payload {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Payload{}, r.payloads...)
+}
+
+type testSetup struct {
+	bus                *events.Bus
+	webhooksRepository repository.WebhooksRepository
+	webhooksHandler    *handler.WebhooksHandler
+	subscriptionID     primitive.ObjectID
+}
+
+func newTestSetup(t *testing.T, r *receiver, maxAttempts int, subscription handler.WebhookSubscription) *testSetup {
+	t.Helper()
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	webhooksRepository := repository.NewMockWebhooksRepository(map[primitive.ObjectID]*repository.WebhookSubscription{}, false)
+	webhooksHandler := handler.NewWebhooksHandler(webhooksRepository)
+	subscription.URL = server.URL
+	subscription.Secret = testSecret
+	created, err := webhooksHandler.CreateSubscription(&subscription)
+	if err != nil {
+		t.Fatalf("CreateSubscription() unexpected error = %v", err)
+	}
+
+	bus := events.NewBus()
+	dispatcher := NewDispatcher(webhooksRepository, bus, maxAttempts)
+	dispatcher.initialBackoff = 10 * time.Millisecond
+	dispatcher.pollInterval = 5 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = bus.Run(ctx)
+	}()
+	go func() {
+		_ = dispatcher.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// gives the dispatcher time to subscribe to the bus, events published before are not delivered
+	time.Sleep(50 * time.Millisecond)
+
+	return &testSetup{bus, webhooksRepository, webhooksHandler, created.ID}
+}
+
+// waitForDelivery waits until the only delivery of the subscription has the status.
+func (s *testSetup) waitForDelivery(t *testing.T, status repository.WebhookDeliveryStatus) *repository.WebhookDelivery {
+	t.Helper()
+
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(5 * time.Millisecond) {
+		deliveries, err := s.webhooksHandler.GetDeliveries(s.subscriptionID, "")
+		if err != nil {
+			t.Fatalf("GetDeliveries() unexpected error = %v", err)
+		}
+		if len(deliveries) == 1 && deliveries[0].Status == status {
+			return deliveries[0]
+		}
+	}
+
+	deliveries, _ := s.webhooksHandler.GetDeliveries(s.subscriptionID, "")
+	t.Fatalf("deliveries = %+v, want one delivery with status %s", deliveries, status)
+	return nil
+}
+
+func TestDispatcher_delivers(t *testing.T) {
+	r := &receiver{t: t, statusCodes: []int{http.StatusNoContent}}
+	setup := newTestSetup(t, r, 3, handler.WebhookSubscription{EventTypes: []string{"fact.approved"}, Active: true})
+
+	factID, _ := primitive.ObjectIDFromHex("6578bf140e487ecc049c7594")
+	fact := &repository.Fact{ID: factID, Fact: "Whales sing.", Animal: "whale", Approved: true, CreatedBy: "auth0|admin", Revision: 2}
+	setup.bus.Publish(events.TypeFactCreated, "6578bf140e487ecc049c7594", nil)
+	setup.bus.Publish(events.TypeFactApproved, "6578bf140e487ecc049c7594", fact)
+
+	delivery := setup.waitForDelivery(t, repository.WebhookDeliveryStatusSucceeded)
+	if len(delivery.Attempts) != 1 || delivery.Attempts[0].StatusCode != http.StatusNoContent || delivery.Failures != 0 {
+		t.Errorf("delivery = %+v, want one successful attempt", delivery)
+	}
+
+	payloads := r.received()
+	if len(payloads) != 1 || payloads[0].ID != delivery.ID.Hex() || payloads[0].Type != "fact.approved" || payloads[0].FactID != "6578bf140e487ecc049c7594" {
+		t.Fatalf("received %+v, want only the approval", payloads)
+	}
+	if data := payloads[0].Data; data == nil || data.ID != factID.Hex() || data.Fact != fact.Fact || data.Animal != "whale" {
+		t.Errorf("received data %+v, want the public fields of the fact", payloads[0].Data)
+	}
+}
+
+func TestDispatcher_payloadData(t *testing.T) {
+	fact := repository.Fact{ID: primitive.NewObjectID(), Fact: "Whales sing.", CreatedBy: "auth0|admin", UpdatedBy: "auth0|admin", Flagged: true}
+	if data, err := payloadData(&fact); err != nil || data != nil {
+		t.Errorf("payloadData() = %+v, %v, want no data of a fact that is not approved", data, err)
+	}
+
+	fact.Approved = true
+	encoded, _ := json.Marshal(fact)
+	data, err := payloadData(json.RawMessage(encoded))
+	if err != nil || data == nil {
+		t.Fatalf("payloadData() = %+v, %v, want the approved fact", data, err)
+	}
+	payload, _ := json.Marshal(data)
+	if want := `{"id":"` + fact.ID.Hex() + `","fact":"Whales sing.","source":"","animal":"","tags":[],"language":""}`; string(payload) != want {
+		t.Errorf("payloadData() = %s, want %s", payload, want)
+	}
+}
+
+func TestDispatcher_retries(t *testing.T) {
+	r := &receiver{t: t, statusCodes: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}}
+	setup := newTestSetup(t, r, 3, handler.WebhookSubscription{EventTypes: []string{"fact.deleted"}, Active: true})
+
+	setup.bus.Publish(events.TypeFactDeleted, "6578bf140e487ecc049c7594", nil)
+
+	delivery := setup.waitForDelivery(t, repository.WebhookDeliveryStatusSucceeded)
+	if len(delivery.Attempts) != 3 || delivery.Failures != 2 || delivery.Attempts[0].Error == "" {
+		t.Errorf("delivery = %+v, want two failed and one successful attempt", delivery)
+	}
+	if gap := delivery.Attempts[2].At.Sub(delivery.Attempts[1].At); gap < 20*time.Millisecond {
+		t.Errorf("third attempt was %v after the second, want the backoff to double", gap)
+	}
+
+	payloads := r.received()
+	if len(payloads) != 3 || payloads[0].ID != payloads[2].ID {
+		t.Errorf("received %+v, want the same delivery three times", payloads)
+	}
+}
+
+func TestDispatcher_deadLetterAndRedeliver(t *testing.T) {
+	r := &receiver{t: t, statusCodes: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK}}
+	setup := newTestSetup(t, r, 2, handler.WebhookSubscription{EventTypes: []string{"fact.approved"}, Active: true})
+
+	setup.bus.Publish(events.TypeFactApproved, "6578bf140e487ecc049c7594", nil)
+
+	delivery := setup.waitForDelivery(t, repository.WebhookDeliveryStatusDead)
+	if len(delivery.Attempts) != 2 || delivery.Failures != 2 {
+		t.Errorf("delivery = %+v, want two failed attempts", delivery)
+	}
+	dead, err := setup.webhooksHandler.GetDeliveries(setup.subscriptionID, repository.WebhookDeliveryStatusDead)
+	if err != nil || len(dead) != 1 {
+		t.Errorf("GetDeliveries() = %v, %v, want the dead delivery", dead, err)
+	}
+
+	if err := setup.webhooksHandler.Redeliver(primitive.NewObjectID(), delivery.ID); err != handler.ErrWebhookDeliveryNotFound {
+		t.Errorf("Redeliver() error = %v, want ErrWebhookDeliveryNotFound for another subscription", err)
+	}
+	if err := setup.webhooksHandler.Redeliver(setup.subscriptionID, delivery.ID); err != nil {
+		t.Fatalf("Redeliver() unexpected error = %v", err)
+	}
+
+	delivery = setup.waitForDelivery(t, repository.WebhookDeliveryStatusSucceeded)
+	if len(delivery.Attempts) != 3 || delivery.Failures != 0 {
+		t.Errorf("delivery = %+v, want the log of all attempts and the failures to be reset", delivery)
+	}
+}
+
+func TestDispatcher_inactiveSubscription(t *testing.T) {
+	r := &receiver{t: t, statusCodes: []int{http.StatusOK}}
+	setup := newTestSetup(t, r, 3, handler.WebhookSubscription{EventTypes: []string{"fact.approved"}, Active: false})
+
+	setup.bus.Publish(events.TypeFactApproved, "6578bf140e487ecc049c7594", nil)
+	time.Sleep(50 * time.Millisecond)
+
+	deliveries, err := setup.webhooksHandler.GetDeliveries(setup.subscriptionID, "")
+	if err != nil || len(deliveries) != 0 || len(r.received()) != 0 {
+		t.Errorf("GetDeliveries() = %v, %v, want no deliveries for an inactive subscription", deliveries, err)
+	}
+}
+
+func TestDispatcher_deduplicatesKeyedEvents(t *testing.T) {
+	webhooksRepository := repository.NewMockWebhooksRepository(map[primitive.ObjectID]*repository.WebhookSubscription{}, false)
+	subscription := &repository.WebhookSubscription{ID: primitive.NewObjectID(), EventTypes: []string{"fact.approved"}, Active: true}
+	if err := webhooksRepository.CreateSubscription(subscription); err != nil {
+		t.Fatalf("CreateSubscription() unexpected error = %v", err)
+	}
+	dispatcher := NewDispatcher(webhooksRepository, nil, DefaultMaxAttempts)
+
+	// the event is published again with a new ID of the bus, e.g. by the relay of another process
+	for id := uint64(1); id <= 2; id++ {
+		event := events.Event{ID: id, Type: events.TypeFactApproved, FactID: "6578bf140e487ecc049c7594", Key: "6578bf140e487ecc049c7595"}
+		if err := dispatcher.createDeliveriesOfEvent(event); err != nil {
+			t.Fatalf("createDeliveriesOfEvent() unexpected error = %v", err)
+		}
+	}
+
+	deliveries, err := webhooksRepository.ReadDeliveries(subscription.ID, "", 10)
+	if err != nil || len(deliveries) != 1 {
+		t.Errorf("ReadDeliveries() = %v, %v, want one delivery of the event", deliveries, err)
+	}
+}
+
+func TestDispatcher_backoff(t *testing.T) {
+	dispatcher := NewDispatcher(nil, nil, DefaultMaxAttempts)
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: time.Minute},
+		{failures: 2, want: 2 * time.Minute},
+		{failures: 5, want: 16 * time.Minute},
+		{failures: 20, want: 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := dispatcher.backoff(tt.failures); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign(testSecret, time.Unix(1700000000, 0), body)
+
+	if err := Verify(testSecret, "1700000000", signature, body); err != nil {
+		t.Errorf("Verify() unexpected error = %v", err)
+	}
+	if err := Verify(testSecret, "1700000001", signature, body); err == nil {
+		t.Error("Verify() accepted a signature of another timestamp")
+	}
+	if err := Verify("another secret", "1700000000", signature, body); err == nil {
+		t.Error("Verify() accepted a signature of another secret")
+	}
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Payload is the JSON body of a delivery. The ID is the same for all attempts of a delivery, so that receivers can
+// ignore deliveries they already processed.
+type Payload struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	FactID string    `json:"factId"`
+	Time   time.Time `json:"time"`
+	// Data is the fact after the event, it is only set for approved facts, as the receivers are outside partners.
+	Data *Fact `json:"data,omitempty"`
+}
+
+// Fact is the public representation of a fact in the payloads, internals like the users that wrote the fact are not
+// sent to the receivers.
+type Fact struct {
+	ID       string   `json:"id"`
+	Fact     string   `json:"fact"`
+	Source   string   `json:"source"`
+	Animal   string   `json:"animal"`
+	Tags     []string `json:"tags"`
+	Language string   `json:"language"`
+}
+
+// Sign returns the value of the signature header, which is the hex encoded HMAC-SHA256 of the timestamp in unix
+// seconds, a dot and the body, keyed with the secret of the subscription. The timestamp is signed as well, so that
+// receivers can reject old deliveries that are replayed.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a delivery like receivers should do it, it is used by the tests.
+func Verify(secret string, timestampHeader string, signatureHeader string, body []byte) error {
+	unixSeconds, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestampHeader)
+	}
+
+	expected := Sign(secret, time.Unix(unixSeconds, 0), body)
+	if !hmac.Equal([]byte(expected), []byte(signatureHeader)) {
+		return fmt.Errorf("signature does not match")
+	}
+
+	return nil
+}
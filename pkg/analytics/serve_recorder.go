@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	ClientTypeBrowser = "browser"
+	ClientTypeCli     = "cli"
+	ClientTypeBot     = "bot"
+	ClientTypeOther   = "other"
+
+	defaultBufferSize    = 4096
+	defaultBatchSize     = 200
+	defaultFlushInterval = 10 * time.Second
+)
+
+// ServeRecorder buffers serve events and writes them in batches to the serve stats repository in the background,
+// so that recording a serve never blocks a request. Events are dropped if the buffer is full.
+type ServeRecorder struct {
+	serveStatsRepository repository.ServeStatsRepository
+	events               chan *repository.ServeEvent
+	batchSize            int
+	flushInterval        time.Duration
+	dropped              atomic.Int64
+}
+
+func NewServeRecorder(serveStatsRepository repository.ServeStatsRepository) *ServeRecorder {
+	return &ServeRecorder{
+		serveStatsRepository: serveStatsRepository,
+		events:               make(chan *repository.ServeEvent, defaultBufferSize),
+		batchSize:            defaultBatchSize,
+		flushInterval:        defaultFlushInterval,
+	}
+}
+
+// Record queues a serve event without blocking. Recording on a nil ServeRecorder is a no-op.
+func (s *ServeRecorder) Record(event *repository.ServeEvent) {
+	if s == nil {
+		return
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events that were dropped because the buffer was full.
+func (s *ServeRecorder) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Run writes the recorded events until the context is done, the remaining buffered events are written before returning.
+func (s *ServeRecorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*repository.ServeEvent, 0, s.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.serveStatsRepository.RecordServes(batch); err != nil {
+			log.Logger().WithError(err).Errorf("failed to record %d serve events", len(batch))
+		}
+		batch = make([]*repository.ServeEvent, 0, s.batchSize)
+	}
+
+	for {
+		select {
+		case event := <-s.events:
+			batch = append(batch, event)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-s.events:
+					batch = append(batch, event)
+				default:
+					flush()
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// ClientType derives a coarse client type from the user agent of a request.
+func ClientType(userAgent string) string {
+	userAgent = strings.ToLower(userAgent)
+	switch {
+	case userAgent == "":
+		return ClientTypeOther
+	case strings.Contains(userAgent, "bot") || strings.Contains(userAgent, "spider") || strings.Contains(userAgent, "crawl"):
+		return ClientTypeBot
+	case strings.HasPrefix(userAgent, "curl/") || strings.HasPrefix(userAgent, "wget/") || strings.HasPrefix(userAgent, "httpie/"):
+		return ClientTypeCli
+	case strings.HasPrefix(userAgent, "mozilla/"):
+		return ClientTypeBrowser
+	}
+
+	return ClientTypeOther
+}
@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+// recordingServeStatsRepository keeps the recorded serve events in memory.
+type recordingServeStatsRepository struct {
+	mutex  sync.Mutex
+	events []*repository.ServeEvent
+}
+
+func (r *recordingServeStatsRepository) RecordServes(events []*repository.ServeEvent) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *recordingServeStatsRepository) ReadCounts(filter repository.ServeCountsFilter) ([]*repository.ServeCount, error) {
+	return nil, nil
+}
+
+func (r *recordingServeStatsRepository) recorded() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.events)
+}
+
+func newTestServeRecorder(serveStatsRepository repository.ServeStatsRepository, bufferSize int) *ServeRecorder {
+	return &ServeRecorder{
+		serveStatsRepository: serveStatsRepository,
+		events:               make(chan *repository.ServeEvent, bufferSize),
+		batchSize:            100,
+		flushInterval:        time.Hour,
+	}
+}
+
+func newServeEvent() *repository.ServeEvent {
+	return &repository.ServeEvent{FactID: primitive.NewObjectID(), Endpoint: "random", ClientType: ClientTypeCli, Timestamp: time.Now()}
+}
+
+func TestServeRecorder_Record_dropsWhenBufferIsFull(t *testing.T) {
+	serveRecorder := newTestServeRecorder(&recordingServeStatsRepository{}, 2)
+
+	done := make(chan struct{})
+	go func() {
+		// nothing reads the buffer, so the third event only doesn't block if it is dropped
+		for i := 0; i < 3; i++ {
+			serveRecorder.Record(newServeEvent())
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Record() blocked with a full buffer")
+	}
+	if dropped := serveRecorder.Dropped(); dropped != 1 {
+		t.Errorf("Dropped() = %d, want 1", dropped)
+	}
+
+	var nilRecorder *ServeRecorder
+	nilRecorder.Record(newServeEvent())
+}
+
+func TestServeRecorder_Run_flushesOnShutdown(t *testing.T) {
+	serveStatsRepository := &recordingServeStatsRepository{}
+	serveRecorder := newTestServeRecorder(serveStatsRepository, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() {
+		stopped <- serveRecorder.Run(ctx)
+	}()
+
+	for i := 0; i < 3; i++ {
+		serveRecorder.Record(newServeEvent())
+	}
+	// the batch is neither full nor is the flush interval over, so the events are only written on shutdown
+	cancel()
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("Run() unexpected error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Run() didn't return after the context was canceled")
+	}
+	if recorded := serveStatsRepository.recorded(); recorded != 3 {
+		t.Errorf("Run() recorded %d events, want 3", recorded)
+	}
+}
@@ -0,0 +1,46 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// HeaderAPIKey is the header clients send their API key in.
+	HeaderAPIKey = "X-API-Key"
+
+	// keyPrefix marks the keys of this API, so that leaked keys are easy to find, e.g. by secret scanners
+	keyPrefix = "af_"
+	// visiblePrefixLength is the length of the start of a key that is stored to identify it
+	visiblePrefixLength = len(keyPrefix) + 8
+)
+
+const (
+	// ScopeReadFacts allows GET and HEAD requests, other routes require their own scopes.
+	ScopeReadFacts    = "read:facts"
+	ScopeCreateReport = "create:report"
+)
+
+// Scopes are all scopes an API key can have.
+var Scopes = []string{ScopeReadFacts, ScopeCreateReport}
+
+// Generate returns a new random key with its prefix and hash, only the prefix and the hash are stored.
+func Generate() (key string, prefix string, hash string, err error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", "", errors.Wrap(err, "failed to generate api key")
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(random)
+	return key, key[:visiblePrefixLength], Hash(key), nil
+}
+
+// Hash returns the hash a key is stored with. The keys are random, so a hash without salt is enough.
+func Hash(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
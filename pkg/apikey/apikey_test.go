@@ -0,0 +1,233 @@
+package apikey
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/ratelimit"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+func TestGenerate(t *testing.T) {
+	key, prefix, hash, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate() unexpected error = %v", err)
+	}
+	if !strings.HasPrefix(key, "af_") || !strings.HasPrefix(key, prefix) || len(prefix) != 11 || hash != Hash(key) || strings.Contains(hash, key) {
+		t.Errorf("Generate() = %s, %s, %s, want key with its prefix and hash", key, prefix, hash)
+	}
+
+	otherKey, _, _, _ := Generate()
+	if otherKey == key {
+		t.Errorf("Generate() returned the same key twice")
+	}
+}
+
+type testSetup struct {
+	repository    repository.APIKeysRepository
+	authenticator *Authenticator
+	echo          *echo.Echo
+	keyID         primitive.ObjectID
+}
+
+func newTestSetup(apiKey repository.APIKey) *testSetup {
+	apiKeysRepository := repository.NewMockAPIKeysRepository(map[primitive.ObjectID]*repository.APIKey{apiKey.ID: &apiKey}, false)
+	authenticator := NewAuthenticator(apiKeysRepository)
+	authenticator.now = func() time.Time {
+		return time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC)
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.HTTPErrorHandler
+	e.Use(authenticator.Middleware())
+	handler := func(c echo.Context) error {
+		if FromContext(c) == nil {
+			return c.String(http.StatusOK, "anonymous")
+		}
+		return c.String(http.StatusOK, FromContext(c).Owner)
+	}
+	e.GET("/facts", handler)
+	e.POST("/facts/:id/reports", handler, RequireScope(ScopeCreateReport))
+
+	return &testSetup{repository: apiKeysRepository, authenticator: authenticator, echo: e, keyID: apiKey.ID}
+}
+
+func (s *testSetup) serve(method string, path string, key string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	if key != "" {
+		request.Header.Set(HeaderAPIKey, key)
+	}
+	recorder := httptest.NewRecorder()
+	s.echo.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiKey   repository.APIKey
+		method   string
+		path     string
+		key      string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "anonymous",
+			method:   http.MethodGet,
+			path:     "/facts",
+			wantCode: http.StatusOK,
+			wantBody: "anonymous",
+		},
+		{
+			name:     "valid key",
+			apiKey:   repository.APIKey{Hash: Hash("af_valid"), Owner: "partner", Scopes: []string{ScopeReadFacts}},
+			method:   http.MethodGet,
+			path:     "/facts",
+			key:      "af_valid",
+			wantCode: http.StatusOK,
+			wantBody: "partner",
+		},
+		{
+			name:     "unknown key",
+			apiKey:   repository.APIKey{Hash: Hash("af_valid"), Owner: "partner", Scopes: []string{ScopeReadFacts}},
+			method:   http.MethodGet,
+			path:     "/facts",
+			key:      "af_unknown",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "revoked key",
+			apiKey:   repository.APIKey{Hash: Hash("af_valid"), Owner: "partner", Scopes: []string{ScopeReadFacts}, Revoked: true},
+			method:   http.MethodGet,
+			path:     "/facts",
+			key:      "af_valid",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "read without scope",
+			apiKey:   repository.APIKey{Hash: Hash("af_valid"), Owner: "partner", Scopes: []string{ScopeCreateReport}},
+			method:   http.MethodGet,
+			path:     "/facts",
+			key:      "af_valid",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "route scope",
+			apiKey:   repository.APIKey{Hash: Hash("af_valid"), Owner: "partner", Scopes: []string{ScopeCreateReport}},
+			method:   http.MethodPost,
+			path:     "/facts/1/reports",
+			key:      "af_valid",
+			wantCode: http.StatusOK,
+			wantBody: "partner",
+		},
+		{
+			name:     "route without scope",
+			apiKey:   repository.APIKey{Hash: Hash("af_valid"), Owner: "partner", Scopes: []string{ScopeReadFacts}},
+			method:   http.MethodPost,
+			path:     "/facts/1/reports",
+			key:      "af_valid",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "anonymous route with scope",
+			method:   http.MethodPost,
+			path:     "/facts/1/reports",
+			wantCode: http.StatusOK,
+			wantBody: "anonymous",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.apiKey.ID = primitive.NewObjectID()
+			setup := newTestSetup(tt.apiKey)
+
+			recorder := setup.serve(tt.method, tt.path, tt.key)
+			if recorder.Code != tt.wantCode || (tt.wantBody != "" && recorder.Body.String() != tt.wantBody) {
+				t.Errorf("Middleware() = %d %s, want %d %s", recorder.Code, recorder.Body, tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_MiddlewareQuota(t *testing.T) {
+	setup := newTestSetup(repository.APIKey{
+		ID:         primitive.NewObjectID(),
+		Hash:       Hash("af_valid"),
+		Scopes:     []string{ScopeReadFacts},
+		DailyQuota: 2,
+	})
+
+	for i := 0; i < 2; i++ {
+		if recorder := setup.serve(http.MethodGet, "/facts", "af_valid"); recorder.Code != http.StatusOK {
+			t.Fatalf("Middleware() = %d, want requests within the quota to be allowed", recorder.Code)
+		}
+	}
+	recorder := setup.serve(http.MethodGet, "/facts", "af_valid")
+	if recorder.Code != http.StatusTooManyRequests || recorder.Header().Get(echo.HeaderRetryAfter) != "3601" {
+		t.Errorf("Middleware() = %d %v, want 429 until the next day", recorder.Code, recorder.Header())
+	}
+	setup.serve(http.MethodGet, "/facts", "")
+
+	usage, err := setup.repository.ReadUsage(setup.keyID, "2024-01-01", "2024-01-31")
+	if err != nil || len(usage) != 1 || usage[0].Day != "2024-01-02" || usage[0].Requests != 3 {
+		t.Errorf("ReadUsage() = %v, %v, want the 3 requests of the key on the day", usage, err)
+	}
+}
+
+func TestAuthenticator_MiddlewareFailureLimit(t *testing.T) {
+	setup := newTestSetup(repository.APIKey{ID: primitive.NewObjectID(), Hash: Hash("af_valid"), Scopes: []string{ScopeReadFacts}})
+	limit := ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Requests: 3, Period: time.Minute}
+	setup.authenticator.LimitFailures(ratelimit.NewLimiter("test", ratelimit.NewMemoryStore(), limit), ratelimit.KeyByIP)
+
+	for i := 0; i < 3; i++ {
+		if recorder := setup.serve(http.MethodGet, "/facts", fmt.Sprintf("af_guessed%d", i)); recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("Middleware() = %d, want 401 for invalid keys within the limit", recorder.Code)
+		}
+	}
+	for _, key := range []string{"af_guessed3", "af_valid"} {
+		recorder := setup.serve(http.MethodGet, "/facts", key)
+		if recorder.Code != http.StatusTooManyRequests || recorder.Header().Get(echo.HeaderRetryAfter) == "" {
+			t.Errorf("Middleware() = %d %v, want 429 once the invalid keys used up the limit", recorder.Code, recorder.Header())
+		}
+	}
+	if recorder := setup.serve(http.MethodGet, "/facts", ""); recorder.Code != http.StatusOK {
+		t.Errorf("Middleware() = %d, want anonymous requests to be left to the rate limit", recorder.Code)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	marker := func(name string) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				c.Response().Header().Set("X-Middleware", name)
+				return next(c)
+			}
+		}
+	}
+	handler := Select(marker("anonymous"), marker("authenticated"))(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, apiKey := range []*repository.APIKey{nil, {ID: primitive.NewObjectID()}} {
+		recorder := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), recorder)
+		want := "anonymous"
+		if apiKey != nil {
+			c.Set(contextKey, apiKey)
+			want = "authenticated"
+		}
+
+		if err := handler(c); err != nil || recorder.Header().Get("X-Middleware") != want {
+			t.Errorf("Select() applied %s, want %s", recorder.Header().Get("X-Middleware"), want)
+		}
+	}
+}
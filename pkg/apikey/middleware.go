@@ -0,0 +1,164 @@
+package apikey
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/ratelimit"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+// contextKey is the key of the authenticated API key in the echo context
+const contextKey = "apiKey"
+
+// DayFormat is the format of the days of the usage.
+const DayFormat = "2006-01-02"
+
+// Authenticator authenticates the requests with an API key and counts their usage.
+type Authenticator struct {
+	apiKeysRepository repository.APIKeysRepository
+	failureLimiter    *ratelimit.Limiter
+	failureKeyFunc    ratelimit.KeyFunc
+	now               func() time.Time
+}
+
+func NewAuthenticator(apiKeysRepository repository.APIKeysRepository) *Authenticator {
+	return &Authenticator{apiKeysRepository: apiKeysRepository, now: time.Now}
+}
+
+// LimitFailures takes the requests with unknown or revoked keys from the limit of the limiter by the key of the
+// keyFunc, e.g. the limit of the client IP address. Once the limit is used up, requests with a key get 429 Too Many
+// Requests without reading the key, so that guessed keys can't flood the database. A nil limiter disables it.
+func (a *Authenticator) LimitFailures(limiter *ratelimit.Limiter, keyFunc ratelimit.KeyFunc) *Authenticator {
+	a.failureLimiter = limiter
+	a.failureKeyFunc = keyFunc
+	return a
+}
+
+// Middleware authenticates requests with an API key in the HeaderAPIKey header, requests without the header stay
+// anonymous. Unknown and revoked keys get 401 Unauthorized, GET and HEAD requests of keys without ScopeReadFacts get
+// 403 Forbidden and requests over the daily quota of the key or over the limit of LimitFailures get 429 Too Many
+// Requests. All requests of a key count for its usage, the rejected ones as well.
+func (a *Authenticator) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderAPIKey)
+			if key == "" {
+				return next(c)
+			}
+
+			if err := a.checkFailureLimit(c); err != nil {
+				return err
+			}
+
+			apiKey, err := a.apiKeysRepository.ReadKeyByHash(Hash(key))
+			if errors.Is(err, repository.ErrAPIKeyNotFound) || (err == nil && apiKey.Revoked) {
+				a.takeFailure(c)
+				return problem.Unauthorized("api key is not valid")
+			} else if err != nil {
+				return errors.Wrap(err, "failed to read api key")
+			}
+			c.Set(contextKey, apiKey)
+
+			now := a.now().UTC()
+			requests, err := a.apiKeysRepository.IncrementUsage(apiKey.ID, now.Format(DayFormat))
+			if err != nil {
+				// the usage is only statistics and the quota is a soft limit, so the request is not rejected
+				log.Logger().WithError(err).Warnf("failed to count usage of api key %s", apiKey.Prefix)
+			} else if apiKey.DailyQuota > 0 && requests > apiKey.DailyQuota {
+				nextDay := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+				detail := fmt.Sprintf("daily quota of %d requests exceeded", apiKey.DailyQuota)
+				return problem.RateLimited(detail, nextDay.Sub(now)+time.Second)
+			}
+
+			method := c.Request().Method
+			if (method == http.MethodGet || method == http.MethodHead) && !slices.Contains(apiKey.Scopes, ScopeReadFacts) {
+				return forbidden(ScopeReadFacts)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// checkFailureLimit rejects the request if the client used up the limit of LimitFailures. If the store of the limiter
+// fails, the request is allowed, like by the rate limit middleware.
+func (a *Authenticator) checkFailureLimit(c echo.Context) error {
+	if a.failureLimiter == nil {
+		return nil
+	}
+
+	result, err := a.failureLimiter.Check(a.failureKeyFunc(c))
+	if err != nil {
+		log.Logger().WithError(err).Warn("failed to check rate limit of invalid api keys, allowing request")
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+
+	detail := fmt.Sprintf("too many requests with invalid api keys, retry in %d seconds", int(math.Ceil(result.RetryAfter.Seconds())))
+	return problem.RateLimited(detail, result.RetryAfter)
+}
+
+func (a *Authenticator) takeFailure(c echo.Context) {
+	if a.failureLimiter == nil {
+		return
+	}
+
+	if _, err := a.failureLimiter.Allow(a.failureKeyFunc(c)); err != nil {
+		log.Logger().WithError(err).Warn("failed to take request with invalid api key from rate limit")
+	}
+}
+
+// RequireScope rejects requests with an API key without the scope, anonymous requests are allowed. Routes that are no
+// GET or HEAD routes declare their scope with it.
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if apiKey := FromContext(c); apiKey != nil && !slices.Contains(apiKey.Scopes, scope) {
+				return forbidden(scope)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func forbidden(scope string) error {
+	return problem.Forbidden(fmt.Sprintf("api key has not the scope %s", scope))
+}
+
+// FromContext returns the API key of the request, nil for anonymous requests.
+func FromContext(c echo.Context) *repository.APIKey {
+	apiKey, _ := c.Get(contextKey).(*repository.APIKey)
+	return apiKey
+}
+
+// KeyByID limits every API key on its own, it is a ratelimit.KeyFunc for requests with an API key.
+func KeyByID(c echo.Context) string {
+	return "api-key:" + FromContext(c).ID.Hex()
+}
+
+// Select applies the anonymous middleware to anonymous requests and the authenticated middleware to requests with an
+// API key, e.g. to give them different rate limits. It has to run after the Middleware of an Authenticator.
+func Select(anonymous echo.MiddlewareFunc, authenticated echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		anonymousNext, authenticatedNext := anonymous(next), authenticated(next)
+		return func(c echo.Context) error {
+			if FromContext(c) != nil {
+				return authenticatedNext(c)
+			}
+
+			return anonymousNext(c)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHistorySize      = 1000
+	defaultSubscriberBuffer = 64
+)
+
+type Type string
+
+const (
+	TypeFactCreated    Type = "fact.created"
+	TypeFactUpdated    Type = "fact.updated"
+	TypeFactApproved   Type = "fact.approved"
+	TypeFactUnapproved Type = "fact.unapproved"
+	TypeFactDeleted    Type = "fact.deleted"
+)
+
+var Types = []Type{
+	TypeFactCreated,
+	TypeFactUpdated,
+	TypeFactApproved,
+	TypeFactUnapproved,
+	TypeFactDeleted,
+}
+
+// Event is a change of a fact. IDs increase with every published event of a bus and start at 1, so that clients can
+// resume a stream after the last event they received.
+type Event struct {
+	ID     uint64      `json:"id"`
+	Type   Type        `json:"type"`
+	FactID string      `json:"factId"`
+	Time   time.Time   `json:"time"`
+	Data   interface{} `json:"data,omitempty"`
+	// Key identifies the source of the event across processes, e.g. the ID of the outbox event, it is empty for events
+	// without such a source.
+	Key string `json:"-"`
+}
+
+// Bus distributes events to the subscribers of the process and keeps the latest events, so that subscribers can resume
+// after reconnecting. Publishing never blocks, subscribers that don't keep up are closed and have to resubscribe.
+type Bus struct {
+	mutex       sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		historySize: defaultHistorySize,
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
+
+// Publish sends the event to all subscribers. Publishing on a nil Bus is a no-op.
+func (b *Bus) Publish(eventType Type, factID string, data interface{}) {
+	b.PublishOnce("", eventType, factID, data)
+}
+
+// PublishOnce publishes the event with the key, unless an event with the same key is among the kept events, e.g. an
+// outbox event that is delivered again because its delivery was not recorded. An empty key is never deduplicated.
+func (b *Bus) PublishOnce(key string, eventType Type, factID string, data interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if key != "" && slices.ContainsFunc(b.history, func(event Event) bool { return event.Key == key }) {
+		return
+	}
+
+	b.lastID++
+	event := Event{ID: b.lastID, Type: eventType, FactID: factID, Time: time.Now().UTC(), Data: data, Key: key}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for subscription := range b.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			b.unsubscribe(subscription)
+		}
+	}
+}
+
+// Subscription receives the events of a bus until it is closed, Events is closed as well when the subscriber didn't
+// keep up with the published events.
+type Subscription struct {
+	Events <-chan Event
+	events chan Event
+	bus    *Bus
+}
+
+// Subscribe starts receiving events, the kept events after lastEventID are received first. With lastEventID 0 only
+// new events are received.
+func (b *Bus) Subscribe(lastEventID uint64) *Subscription {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var missed []Event
+	if lastEventID > 0 {
+		for _, event := range b.history {
+			if event.ID > lastEventID {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	events := make(chan Event, len(missed)+defaultSubscriberBuffer)
+	for _, event := range missed {
+		events <- event
+	}
+
+	subscription := &Subscription{Events: events, events: events, bus: b}
+	b.subscribers[subscription] = struct{}{}
+	if b.closed {
+		b.unsubscribe(subscription)
+	}
+
+	return subscription
+}
+
+// Run closes all subscriptions when the context is done, so that open streams end and the server can shut down.
+func (b *Bus) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	for subscription := range b.subscribers {
+		b.unsubscribe(subscription)
+	}
+
+	return nil
+}
+
+// Close stops receiving events, it can be called more than once.
+func (s *Subscription) Close() {
+	s.bus.mutex.Lock()
+	defer s.bus.mutex.Unlock()
+
+	s.bus.unsubscribe(s)
+}
+
+// unsubscribe removes the subscription, the mutex has to be locked.
+func (b *Bus) unsubscribe(subscription *Subscription) {
+	if _, subscribed := b.subscribers[subscription]; subscribed {
+		delete(b.subscribers, subscription)
+		close(subscription.events)
+	}
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, subscription *Subscription) (Event, bool) {
+	t.Helper()
+
+	select {
+	case event, ok := <-subscription.Events:
+		return event, ok
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}, false
+	}
+}
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	bus := NewBus()
+	bus.Publish(TypeFactCreated, "1", nil)
+
+	subscription := bus.Subscribe(0)
+	defer subscription.Close()
+
+	bus.Publish(TypeFactApproved, "1", map[string]string{"fact": "Whales sing."})
+	event, ok := receive(t, subscription)
+	if !ok || event.ID != 2 || event.Type != TypeFactApproved || event.FactID != "1" || event.Time.IsZero() {
+		t.Errorf("received %+v, want only the event published after subscribing", event)
+	}
+}
+
+func TestBus_PublishOnce(t *testing.T) {
+	bus := NewBus()
+	subscription := bus.Subscribe(0)
+	defer subscription.Close()
+
+	bus.PublishOnce("outbox-1", TypeFactApproved, "1", nil)
+	bus.PublishOnce("outbox-1", TypeFactApproved, "1", nil)
+	bus.PublishOnce("outbox-2", TypeFactDeleted, "1", nil)
+	bus.Publish(TypeFactUpdated, "1", nil)
+	bus.Publish(TypeFactUpdated, "1", nil)
+
+	var keys []string
+	for len(subscription.Events) > 0 {
+		event, _ := receive(t, subscription)
+		keys = append(keys, event.Key)
+	}
+	if len(keys) != 4 || keys[0] != "outbox-1" || keys[1] != "outbox-2" {
+		t.Errorf("received events with keys %q, want the event with a known key once and all events without key", keys)
+	}
+}
+
+func TestBus_SubscribeResume(t *testing.T) {
+	bus := NewBus()
+	bus.historySize = 3
+	for i := 0; i < 5; i++ {
+		bus.Publish(TypeFactUpdated, "1", nil)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID uint64
+		wantIDs     []uint64
+	}{
+		{name: "after the last event", lastEventID: 5, wantIDs: nil},
+		{name: "within the history", lastEventID: 3, wantIDs: []uint64{4, 5}},
+		{name: "before the history", lastEventID: 1, wantIDs: []uint64{3, 4, 5}},
+		{name: "from a previous process", lastEventID: 100, wantIDs: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := bus.Subscribe(tt.lastEventID)
+			defer subscription.Close()
+
+			if got := len(subscription.Events); got != len(tt.wantIDs) {
+				t.Fatalf("Subscribe(%d) replayed %d events, want %d", tt.lastEventID, got, len(tt.wantIDs))
+			}
+			for _, wantID := range tt.wantIDs {
+				if event, _ := receive(t, subscription); event.ID != wantID {
+					t.Errorf("received event %d, want %d", event.ID, wantID)
+				}
+			}
+		})
+	}
+}
+
+func TestBus_slowSubscriberIsClosed(t *testing.T) {
+	bus := NewBus()
+	slow := bus.Subscribe(0)
+	defer slow.Close()
+
+	for i := 0; i <= defaultSubscriberBuffer; i++ {
+		bus.Publish(TypeFactUpdated, "1", nil)
+	}
+
+	for i := 0; i < defaultSubscriberBuffer; i++ {
+		receive(t, slow)
+	}
+	if _, ok := receive(t, slow); ok {
+		t.Error("subscription is still open, want it to be closed after the buffer was full")
+	}
+
+	// the subscriber can resume with the history
+	resumed := bus.Subscribe(defaultSubscriberBuffer)
+	defer resumed.Close()
+	if event, _ := receive(t, resumed); event.ID != defaultSubscriberBuffer+1 {
+		t.Errorf("received event %d, want the missed event %d", event.ID, defaultSubscriberBuffer+1)
+	}
+}
+
+func TestBus_Run(t *testing.T) {
+	bus := NewBus()
+	subscription := bus.Subscribe(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- bus.Run(ctx)
+	}()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() unexpected error = %v", err)
+	}
+
+	if _, ok := receive(t, subscription); ok {
+		t.Error("subscription is still open after the bus stopped")
+	}
+	if _, ok := receive(t, bus.Subscribe(0)); ok {
+		t.Error("new subscription is open after the bus stopped")
+	}
+	subscription.Close()
+
+	var nilBus *Bus
+	nilBus.Publish(TypeFactDeleted, "1", nil)
+}
@@ -0,0 +1,150 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	heartbeatInterval = 15 * time.Second
+	// retryMilliseconds tells clients of server-sent events how long to wait before reconnecting
+	retryMilliseconds = 3000
+)
+
+// Filter selects the events of a stream.
+type Filter func(event Event) bool
+
+// TypeFilter parses a comma separated list of event types, all events pass the filter if the list is empty.
+func TypeFilter(types string) Filter {
+	allowed := map[Type]bool{}
+	for _, eventType := range strings.Split(types, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			allowed[Type(eventType)] = true
+		}
+	}
+
+	return func(event Event) bool {
+		return len(allowed) == 0 || allowed[event.Type]
+	}
+}
+
+// LastEventID returns the ID of the last event a client received from the Last-Event-ID header, which browsers send
+// when they reconnect to server-sent events, or from the lastEventId query parameter for the first connection and for
+// WebSockets. It is 0 if the client didn't receive events before.
+func LastEventID(c echo.Context) (uint64, error) {
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("lastEventId")
+	}
+	if lastEventID == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(lastEventID, 10, 64)
+}
+
+// ServeSSE streams the events of the bus as server-sent events until the client disconnects. The stream ends if the
+// client doesn't keep up with the events, it can resume with the Last-Event-ID then.
+func ServeSSE(c echo.Context, bus *Bus, lastEventID uint64, filter Filter) error {
+	subscription := bus.Subscribe(lastEventID)
+	defer subscription.Close()
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, "text/event-stream")
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	response.Header().Set(echo.HeaderConnection, "keep-alive")
+	// disables buffering of reverse proxies like nginx, the events have to reach the client immediately
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(response, "retry: %d\n\n", retryMilliseconds); err != nil {
+		return nil
+	}
+	response.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(response, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return nil
+			}
+			if !filter(event) {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(response, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return nil
+			}
+		}
+		response.Flush()
+	}
+}
+
+// ServeWebSocket streams the events of the bus as JSON text messages of a WebSocket until the client disconnects.
+// Messages of the client are ignored. The connection is closed if the client doesn't keep up with the events, it can
+// resume with the lastEventId query parameter then.
+func ServeWebSocket(c echo.Context, bus *Bus, lastEventID uint64, filter Filter) error {
+	// the origin is not checked, as clients authenticate with tokens instead of cookies
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+		// plain writes are only used for the heartbeat, messages are sent with websocket.JSON
+		conn.PayloadType = websocket.PingFrame
+
+		subscription := bus.Subscribe(lastEventID)
+		defer subscription.Close()
+
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var message string
+			for websocket.Message.Receive(conn, &message) == nil {
+			}
+		}()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case <-heartbeat.C:
+				if _, err := conn.Write(nil); err != nil {
+					return
+				}
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+				if !filter(event) {
+					continue
+				}
+				if err := websocket.JSON.Send(conn, event); err != nil {
+					return
+				}
+			}
+		}
+	}}
+
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+func newStreamServer(bus *Bus, serve func(c echo.Context, bus *Bus, lastEventID uint64, filter Filter) error) *httptest.Server {
+	e := echo.New()
+	e.GET("/events", func(c echo.Context) error {
+		lastEventID, err := LastEventID(c)
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+		return serve(c, bus, lastEventID, TypeFilter(c.QueryParam("types")))
+	})
+
+	return httptest.NewServer(e)
+}
+
+// waitForSubscribers waits until the stream subscribed, events that are published before are not received.
+func waitForSubscribers(t *testing.T, bus *Bus, count int) {
+	t.Helper()
+
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(time.Millisecond) {
+		bus.mutex.Lock()
+		subscribers := len(bus.subscribers)
+		bus.mutex.Unlock()
+		if subscribers == count {
+			return
+		}
+	}
+	t.Fatalf("stream did not subscribe")
+}
+
+func TestServeSSE(t *testing.T) {
+	bus := NewBus()
+	bus.Publish(TypeFactCreated, "1", nil)
+	bus.Publish(TypeFactUpdated, "1", nil)
+	server := newStreamServer(bus, ServeSSE)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events?types=fact.updated,fact.approved", nil)
+	request.Header.Set("Last-Event-ID", "1")
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("GET /events unexpected error = %v", err)
+	}
+	defer response.Body.Close()
+
+	if contentType := response.Header.Get(echo.HeaderContentType); contentType != "text/event-stream" {
+		t.Errorf("Content-Type = %s, want text/event-stream", contentType)
+	}
+
+	waitForSubscribers(t, bus, 1)
+	bus.Publish(TypeFactDeleted, "1", nil)
+	bus.Publish(TypeFactApproved, "2", map[string]string{"fact": "Whales sing."})
+
+	reader := bufio.NewReader(response.Body)
+	var messages []string
+	var message strings.Builder
+	for len(messages) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading the stream failed: %v", err)
+		}
+		if line == "\n" {
+			messages = append(messages, message.String())
+			message.Reset()
+			continue
+		}
+		message.WriteString(line)
+	}
+
+	if messages[0] != "retry: 3000\n" {
+		t.Errorf("first message = %q, want the retry interval", messages[0])
+	}
+	if !strings.HasPrefix(messages[1], "id: 2\nevent: fact.updated\ndata: ") {
+		t.Errorf("second message = %q, want the missed update", messages[1])
+	}
+	if !strings.HasPrefix(messages[2], "id: 4\nevent: fact.approved\ndata: ") {
+		t.Errorf("third message = %q, want the approval without the filtered deletion", messages[2])
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(strings.Split(messages[2], "\n")[2], "data: ")), &event); err != nil || event.FactID != "2" {
+		t.Errorf("data = %+v, %v, want the event as JSON", event, err)
+	}
+}
+
+func TestServeSSE_invalidLastEventID(t *testing.T) {
+	server := newStreamServer(NewBus(), ServeSSE)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/events?lastEventId=abc")
+	if err != nil {
+		t.Fatalf("GET /events unexpected error = %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", response.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestServeWebSocket(t *testing.T) {
+	bus := NewBus()
+	bus.Publish(TypeFactCreated, "1", nil)
+	bus.Publish(TypeFactApproved, "1", nil)
+	server := newStreamServer(bus, ServeWebSocket)
+	defer server.Close()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/events?lastEventId=1", "", server.URL)
+	if err != nil {
+		t.Fatalf("Dial() unexpected error = %v", err)
+	}
+	defer conn.Close()
+
+	waitForSubscribers(t, bus, 1)
+	bus.Publish(TypeFactDeleted, "1", nil)
+
+	for _, wantID := range []uint64{2, 3} {
+		var event Event
+		if err := websocket.JSON.Receive(conn, &event); err != nil {
+			t.Fatalf("Receive() unexpected error = %v", err)
+		}
+		if event.ID != wantID {
+			t.Errorf("received event %d, want %d", event.ID, wantID)
+		}
+	}
+
+	conn.Close()
+	waitForSubscribers(t, bus, 0)
+}
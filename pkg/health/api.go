@@ -0,0 +1,119 @@
+package health
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	logger "github.com/cafo13/animal-facts/pkg/log"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+const (
+	LivenessPath  = "/livez"
+	ReadinessPath = "/readyz"
+)
+
+type HealthApi struct {
+	healthApiRoutes []router.Route
+	health          *Health
+	// legacyPath is the path of the plain text health check of earlier versions, which is the readiness check now
+	legacyPath string
+	// showErrors adds the errors of failed checks to the reports, otherwise they are only logged as they can contain
+	// internals like addresses of dependencies
+	showErrors bool
+}
+
+func NewHealthApi(health *Health, legacyPath string) *HealthApi {
+	return &HealthApi{health: health, legacyPath: legacyPath}
+}
+
+// ShowErrors adds the errors of failed checks to the reports, only for apis that are not public.
+func (h *HealthApi) ShowErrors() *HealthApi {
+	h.showErrors = true
+	return h
+}
+
+func (h *HealthApi) SetupRoutes() {
+	h.healthApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        LivenessPath,
+			HandlerFunc: h.getLiveness,
+		},
+		{
+			Method:      "GET",
+			Path:        ReadinessPath,
+			HandlerFunc: h.getReadiness,
+		},
+		{
+			Method:      "GET",
+			Path:        h.legacyPath,
+			HandlerFunc: h.getLegacyHealth,
+		},
+	}
+}
+
+func (h *HealthApi) GetRoutes() []router.Route {
+	return h.healthApiRoutes
+}
+
+// ExceptProbes skips the middleware for the health routes, e.g. so that probes of load balancers are not rate limited.
+func (h *HealthApi) ExceptProbes(middleware echo.MiddlewareFunc) echo.MiddlewareFunc {
+	probePaths := []string{LivenessPath, ReadinessPath, h.legacyPath}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withMiddleware := middleware(next)
+		return func(c echo.Context) error {
+			if slices.Contains(probePaths, c.Path()) {
+				return next(c)
+			}
+			return withMiddleware(c)
+		}
+	}
+}
+
+func (h *HealthApi) getLiveness(c echo.Context) error {
+	return h.reportJSON(c, h.health.Live(c.Request().Context()))
+}
+
+func (h *HealthApi) getReadiness(c echo.Context) error {
+	return h.reportJSON(c, h.health.Ready(c.Request().Context()))
+}
+
+func (h *HealthApi) getLegacyHealth(c echo.Context) error {
+	if !h.health.Ready(c.Request().Context()).OK() {
+		return c.String(http.StatusServiceUnavailable, "Unhealthy")
+	}
+
+	return c.String(http.StatusOK, "Healthy")
+}
+
+func (h *HealthApi) reportJSON(c echo.Context, report *Report) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	if !h.showErrors {
+		report = h.withoutErrors(c, report)
+	}
+	if !report.OK() {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// withoutErrors logs the errors of the failed checks and returns the report without them.
+func (h *HealthApi) withoutErrors(c echo.Context, report *Report) *Report {
+	checks := make([]*CheckResult, len(report.Checks))
+	for i, result := range report.Checks {
+		if result.Error != "" {
+			logger.FromContext(c.Request().Context()).
+				WithField("check", result.Name).
+				Warnf("health check failed: %s", result.Error)
+		}
+		withoutError := *result
+		withoutError.Error = ""
+		checks[i] = &withoutError
+	}
+
+	return &Report{Status: report.Status, Checks: checks}
+}
@@ -0,0 +1,204 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+
+	// DefaultTimeout is the timeout of checks without their own timeout.
+	DefaultTimeout = 2 * time.Second
+	// DefaultCacheTTL is the time the result of checks without their own TTL is reused, so that frequent probes of
+	// several load balancers don't hit the dependencies with every probe.
+	DefaultCacheTTL = 5 * time.Second
+)
+
+var (
+	ErrShuttingDown = errors.New("service is shutting down")
+)
+
+// CheckFunc checks a dependency or component, it fails with an error. It has to stop when the context is done.
+type CheckFunc func(ctx context.Context) error
+
+type Check struct {
+	Name string
+	Func CheckFunc
+	// Timeout of a run of the check, DefaultTimeout if zero.
+	Timeout time.Duration
+	// CacheTTL is the time the result of the check is reused, DefaultCacheTTL if zero.
+	CacheTTL time.Duration
+}
+
+type CheckResult struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// Cached is true if the result is reused from an earlier run of the check.
+	Cached bool `json:"cached"`
+}
+
+type Report struct {
+	Status string         `json:"status"`
+	Checks []*CheckResult `json:"checks"`
+}
+
+func (r *Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+type registeredCheck struct {
+	Check
+	mutex  sync.Mutex
+	result *CheckResult
+}
+
+// Health runs the registered checks of the components of a service. Liveness checks fail if the service has to be
+// restarted, readiness checks fail if the service can't serve requests right now. Every liveness check is a readiness
+// check too. Checks have to be registered before the service runs.
+//
+// Health is a worker, the service is not ready anymore as soon as it is shutting down, so that load balancers stop
+// sending requests before the router stops.
+type Health struct {
+	livenessChecks  []*registeredCheck
+	readinessChecks []*registeredCheck
+	shuttingDown    atomic.Bool
+	now             func() time.Time
+}
+
+func New() *Health {
+	return &Health{now: time.Now}
+}
+
+func (h *Health) AddLivenessCheck(check Check) {
+	registered := newRegisteredCheck(check)
+	h.livenessChecks = append(h.livenessChecks, registered)
+	h.readinessChecks = append(h.readinessChecks, registered)
+}
+
+func (h *Health) AddReadinessCheck(check Check) {
+	h.readinessChecks = append(h.readinessChecks, newRegisteredCheck(check))
+}
+
+func newRegisteredCheck(check Check) *registeredCheck {
+	if check.Timeout == 0 {
+		check.Timeout = DefaultTimeout
+	}
+	if check.CacheTTL == 0 {
+		check.CacheTTL = DefaultCacheTTL
+	}
+
+	return &registeredCheck{Check: check}
+}
+
+func (h *Health) Run(ctx context.Context) error {
+	<-ctx.Done()
+	h.shuttingDown.Store(true)
+	return nil
+}
+
+// Live runs the liveness checks.
+func (h *Health) Live(ctx context.Context) *Report {
+	return h.report(h.run(ctx, h.livenessChecks))
+}
+
+// Ready runs the readiness checks, it fails while the service is shutting down.
+func (h *Health) Ready(ctx context.Context) *Report {
+	results := h.run(ctx, h.readinessChecks)
+	if h.shuttingDown.Load() {
+		results = append(results, &CheckResult{
+			Name:      "shutdown",
+			Status:    StatusFailed,
+			Error:     ErrShuttingDown.Error(),
+			Duration:  time.Duration(0).String(),
+			CheckedAt: h.now(),
+		})
+	}
+
+	return h.report(results)
+}
+
+func (h *Health) report(results []*CheckResult) *Report {
+	report := &Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFailed
+		}
+	}
+
+	return report
+}
+
+// run runs the checks concurrently, the results are in the order of the checks.
+func (h *Health) run(ctx context.Context, checks []*registeredCheck) []*CheckResult {
+	results := make([]*CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// runCheck returns the cached result of the check or runs it, concurrent requests wait for the same run.
+func (h *Health) runCheck(ctx context.Context, check *registeredCheck) *CheckResult {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	if check.result != nil && h.now().Sub(check.result.CheckedAt) < check.CacheTTL {
+		cached := *check.result
+		cached.Cached = true
+		return &cached
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	start := h.now()
+	err := runWithTimeout(checkCtx, check.Func)
+	result := &CheckResult{
+		Name:      check.Name,
+		Status:    StatusOK,
+		Duration:  h.now().Sub(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+
+	// results of canceled requests are not cached, they say nothing about the dependency
+	if ctx.Err() == nil {
+		check.result = result
+	}
+
+	return result
+}
+
+// runWithTimeout returns when the check is done or the context is done, even if the check ignores the context.
+func runWithTimeout(ctx context.Context, checkFunc CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- checkFunc(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "check timed out")
+	}
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestHealth_Ready(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	h := New()
+	h.now = func() time.Time { return now }
+
+	calls := 0
+	var checkErr error
+	h.AddReadinessCheck(Check{Name: "database", Func: func(ctx context.Context) error {
+		calls++
+		return checkErr
+	}})
+	h.AddLivenessCheck(Check{Name: "workers", Func: func(ctx context.Context) error { return nil }})
+
+	if report := h.Ready(context.Background()); !report.OK() || len(report.Checks) != 2 || calls != 1 {
+		t.Fatalf("Ready() = %+v with %d calls, want ok with 2 checks", report, calls)
+	}
+
+	// the result is cached for the TTL
+	checkErr = errors.New("connection refused")
+	report := h.Ready(context.Background())
+	if !report.OK() || calls != 1 || !report.Checks[0].Cached {
+		t.Errorf("Ready() = %+v with %d calls, want cached ok result", report, calls)
+	}
+
+	now = now.Add(DefaultCacheTTL)
+	report = h.Ready(context.Background())
+	if report.OK() || calls != 2 || report.Checks[0].Error != "connection refused" {
+		t.Errorf("Ready() = %+v with %d calls, want failed database check", report, calls)
+	}
+
+	if report := h.Live(context.Background()); !report.OK() || len(report.Checks) != 1 || report.Checks[0].Name != "workers" {
+		t.Errorf("Live() = %+v, want only the ok workers check", report)
+	}
+}
+
+func TestHealth_Timeout(t *testing.T) {
+	h := New()
+	h.AddReadinessCheck(Check{Name: "slow", Timeout: 10 * time.Millisecond, Func: func(ctx context.Context) error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	}})
+
+	start := time.Now()
+	report := h.Ready(context.Background())
+	if report.OK() || time.Since(start) > 100*time.Millisecond {
+		t.Errorf("Ready() = %+v after %s, want failed check after the timeout", report, time.Since(start))
+	}
+}
+
+func TestHealth_ShuttingDown(t *testing.T) {
+	h := New()
+	h.AddLivenessCheck(Check{Name: "workers", Func: func(ctx context.Context) error { return nil }})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- h.Run(ctx)
+	}()
+	if !h.Ready(context.Background()).OK() {
+		t.Fatalf("Ready() failed before shutdown")
+	}
+
+	cancel()
+	<-done
+
+	if h.Ready(context.Background()).OK() {
+		t.Errorf("Ready() ok while shutting down")
+	}
+	if !h.Live(context.Background()).OK() {
+		t.Errorf("Live() failed while shutting down, the service is still alive")
+	}
+}
+
+func TestHealthApi(t *testing.T) {
+	h := New()
+	h.AddReadinessCheck(Check{Name: "database", Func: func(ctx context.Context) error { return errors.New("unreachable") }})
+	healthApi := NewHealthApi(h, "/health")
+	healthApi.SetupRoutes()
+
+	limited := 0
+	e := echo.New()
+	e.Use(healthApi.ExceptProbes(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			limited++
+			return next(c)
+		}
+	}))
+	for _, route := range healthApi.GetRoutes() {
+		e.GET(route.Path, route.HandlerFunc)
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/livez", wantStatus: http.StatusOK},
+		{path: "/readyz", wantStatus: http.StatusServiceUnavailable},
+		{path: "/health", wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report Report
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil || report.Status != StatusFailed ||
+		report.Checks[0].Status != StatusFailed || strings.Contains(recorder.Body.String(), "unreachable") {
+		t.Errorf("readiness report = %s, want failed database check without its error", recorder.Body.String())
+	}
+
+	healthApi.ShowErrors()
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	report = Report{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil || report.Checks[0].Error != "unreachable" {
+		t.Errorf("readiness report = %s, want failed database check with its error", recorder.Body.String())
+	}
+	if limited != 0 {
+		t.Errorf("middleware ran %d times for probes, want 0", limited)
+	}
+}
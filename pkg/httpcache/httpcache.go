@@ -0,0 +1,165 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfNoneMatch = "If-None-Match"
+
+	// NoStore is the policy of responses that must not be cached, e.g. random facts.
+	NoStore = "no-store"
+)
+
+// Policies are the Cache-Control header values of routes by the name of the route.
+type Policies map[string]string
+
+// ParsePolicies parses policies in the format "name=policy;name=policy", e.g. "fact=public, max-age=60;random=no-store".
+func ParsePolicies(value string) (Policies, error) {
+	policies := Policies{}
+	for _, entry := range strings.Split(value, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		name, policy, found := strings.Cut(entry, "=")
+		name, policy = strings.TrimSpace(name), strings.TrimSpace(policy)
+		if !found || name == "" || strings.ContainsAny(name, " ,") || policy == "" {
+			return nil, fmt.Errorf("cache policy '%s' is not in the format name=policy", entry)
+		}
+		policies[name] = policy
+	}
+
+	return policies, nil
+}
+
+// With returns the policies with the overrides, the policies are not changed.
+func (p Policies) With(overrides Policies) Policies {
+	result := Policies{}
+	for name, policy := range p {
+		result[name] = policy
+	}
+	for name, policy := range overrides {
+		result[name] = policy
+	}
+
+	return result
+}
+
+// Names returns the sorted names of the policies.
+func (p Policies) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Middleware sets the Cache-Control header of successful and not modified responses to the policy of the route, error
+// responses are never cached. Handlers can still set another policy. Routes without a policy get no header.
+func (p Policies) Middleware(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			policy, ok := p[name]
+			if !ok {
+				return next(c)
+			}
+
+			response := c.Response()
+			response.Before(func() {
+				if response.Header().Get(echo.HeaderCacheControl) != "" {
+					return
+				}
+				if response.Status < http.StatusBadRequest {
+					response.Header().Set(echo.HeaderCacheControl, policy)
+				} else {
+					response.Header().Set(echo.HeaderCacheControl, NoStore)
+				}
+			})
+			return next(c)
+		}
+	}
+}
+
+// StrongETag quotes the value as strong entity tag, the value must not contain quotes.
+func StrongETag(value string) string {
+	return `"` + value + `"`
+}
+
+// HashETag returns an unquoted ETag value from the hash of the values, e.g. of the fields of a representation.
+func HashETag(values ...string) string {
+	hash := sha256.New()
+	for _, value := range values {
+		// the length prefix keeps the boundaries of the values in the hash
+		_ = binary.Write(hash, binary.BigEndian, uint32(len(value)))
+		hash.Write([]byte(value))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// ETagMatches checks the If-None-Match header, which can contain a list of (weak) ETags or *. The comparison is weak,
+// as it should be for If-None-Match.
+func ETagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotModifiedSince checks the If-Modified-Since header, which only has a resolution of seconds. It is ignored if the
+// request has an If-None-Match header.
+func NotModifiedSince(request *http.Request, lastModified time.Time) bool {
+	if request.Header.Get(HeaderIfNoneMatch) != "" || lastModified.IsZero() {
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(request.Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+// NotModified sets the ETag and Last-Modified headers of the response, empty values are skipped, and checks the
+// conditional headers of the request. Handlers respond with 304 Not Modified if it returns true.
+func NotModified(c echo.Context, etag string, lastModified time.Time) bool {
+	header := c.Response().Header()
+	if etag != "" {
+		header.Set(HeaderETag, etag)
+	}
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	request := c.Request()
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		return false
+	}
+	if ifNoneMatch := request.Header.Get(HeaderIfNoneMatch); ifNoneMatch != "" {
+		return etag != "" && ETagMatches(ifNoneMatch, etag)
+	}
+
+	return NotModifiedSince(request, lastModified)
+}
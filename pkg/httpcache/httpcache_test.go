@@ -0,0 +1,241 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParsePolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Policies
+		wantErr bool
+	}{
+		{
+			name:  "policies",
+			value: "fact=public, max-age=60; random=no-store;",
+			want:  Policies{"fact": "public, max-age=60", "random": "no-store"},
+		},
+		{
+			name:  "empty",
+			value: "",
+			want:  Policies{},
+		},
+		{
+			name:    "missing policy",
+			value:   "fact=",
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			value:   "public, max-age=60",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicies(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolicies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicies_With(t *testing.T) {
+	defaults := Policies{"fact": "public, max-age=60", "random": NoStore}
+	got := defaults.With(Policies{"fact": "no-cache"})
+
+	if want := (Policies{"fact": "no-cache", "random": NoStore}); !reflect.DeepEqual(got, want) {
+		t.Errorf("With() = %v, want %v", got, want)
+	}
+	if defaults["fact"] != "public, max-age=60" {
+		t.Errorf("With() changed the policies to %v", defaults)
+	}
+	if names := got.Names(); !reflect.DeepEqual(names, []string{"fact", "random"}) {
+		t.Errorf("Names() = %v, want sorted names", names)
+	}
+}
+
+func TestPolicies_Middleware(t *testing.T) {
+	policies := Policies{"fact": "public, max-age=60"}
+	tests := []struct {
+		name    string
+		route   string
+		handler echo.HandlerFunc
+		want    string
+	}{
+		{
+			name:    "success",
+			route:   "fact",
+			handler: func(c echo.Context) error { return c.String(http.StatusOK, "fact") },
+			want:    "public, max-age=60",
+		},
+		{
+			name:    "not modified",
+			route:   "fact",
+			handler: func(c echo.Context) error { return c.NoContent(http.StatusNotModified) },
+			want:    "public, max-age=60",
+		},
+		{
+			name:    "error",
+			route:   "fact",
+			handler: func(c echo.Context) error { return c.String(http.StatusNotFound, "not found") },
+			want:    NoStore,
+		},
+		{
+			name:  "policy of handler",
+			route: "fact",
+			handler: func(c echo.Context) error {
+				c.Response().Header().Set(echo.HeaderCacheControl, "private")
+				return c.String(http.StatusOK, "fact")
+			},
+			want: "private",
+		},
+		{
+			name:    "route without policy",
+			route:   "unknown",
+			handler: func(c echo.Context) error { return c.String(http.StatusOK, "fact") },
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), recorder)
+
+			if err := policies.Middleware(tt.route)(tt.handler)(c); err != nil {
+				t.Fatalf("Middleware() unexpected error = %v", err)
+			}
+			if got := recorder.Header().Get(echo.HeaderCacheControl); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "no header", ifNoneMatch: "", want: false},
+		{name: "same", ifNoneMatch: `"abc"`, want: true},
+		{name: "weak", ifNoneMatch: `W/"abc"`, want: true},
+		{name: "list", ifNoneMatch: `"xyz", "abc"`, want: true},
+		{name: "any", ifNoneMatch: "*", want: true},
+		{name: "other", ifNoneMatch: `"xyz"`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ETagMatches(tt.ifNoneMatch, `"abc"`); got != tt.want {
+				t.Errorf("ETagMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashETag(t *testing.T) {
+	if HashETag("a", "b") != HashETag("a", "b") {
+		t.Errorf("HashETag() is not stable")
+	}
+	if HashETag("ab", "") == HashETag("a", "b") {
+		t.Errorf("HashETag() ignores the boundaries of the values")
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	tests := []struct {
+		name         string
+		method       string
+		etag         string
+		headers      map[string]string
+		want         bool
+		wantETag     string
+		wantModified string
+	}{
+		{
+			name:         "unconditional",
+			method:       http.MethodGet,
+			etag:         `"abc"`,
+			want:         false,
+			wantETag:     `"abc"`,
+			wantModified: "Tue, 02 Jan 2024 03:04:05 GMT",
+		},
+		{
+			name:         "etag matches",
+			method:       http.MethodGet,
+			etag:         `"abc"`,
+			headers:      map[string]string{HeaderIfNoneMatch: `"abc"`},
+			want:         true,
+			wantETag:     `"abc"`,
+			wantModified: "Tue, 02 Jan 2024 03:04:05 GMT",
+		},
+		{
+			name:   "etag takes precedence over date",
+			method: http.MethodGet,
+			etag:   `"abc"`,
+			headers: map[string]string{
+				HeaderIfNoneMatch:          `"xyz"`,
+				echo.HeaderIfModifiedSince: "Tue, 02 Jan 2024 03:04:05 GMT",
+			},
+			want:         false,
+			wantETag:     `"abc"`,
+			wantModified: "Tue, 02 Jan 2024 03:04:05 GMT",
+		},
+		{
+			name:         "not modified since",
+			method:       http.MethodGet,
+			headers:      map[string]string{echo.HeaderIfModifiedSince: "Tue, 02 Jan 2024 03:04:05 GMT"},
+			want:         true,
+			wantModified: "Tue, 02 Jan 2024 03:04:05 GMT",
+		},
+		{
+			name:         "modified since",
+			method:       http.MethodGet,
+			headers:      map[string]string{echo.HeaderIfModifiedSince: "Tue, 02 Jan 2024 03:04:04 GMT"},
+			want:         false,
+			wantModified: "Tue, 02 Jan 2024 03:04:05 GMT",
+		},
+		{
+			name:         "only safe methods",
+			method:       http.MethodPost,
+			etag:         `"abc"`,
+			headers:      map[string]string{HeaderIfNoneMatch: `"abc"`},
+			want:         false,
+			wantETag:     `"abc"`,
+			wantModified: "Tue, 02 Jan 2024 03:04:05 GMT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, "/", nil)
+			for name, value := range tt.headers {
+				request.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+			c := echo.New().NewContext(request, recorder)
+
+			if got := NotModified(c, tt.etag, lastModified); got != tt.want {
+				t.Errorf("NotModified() = %v, want %v", got, tt.want)
+			}
+			if got := recorder.Header().Get(HeaderETag); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+			if got := recorder.Header().Get(echo.HeaderLastModified); got != tt.wantModified {
+				t.Errorf("Last-Modified = %q, want %q", got, tt.wantModified)
+			}
+		})
+	}
+}
@@ -15,5 +15,6 @@ func SetupLogger() {
 	log.Logger().SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: time.RFC3339,
 	})
+	log.Logger().AddHook(TraceHook{})
 	log.Logger().Info("logger enabled")
 }
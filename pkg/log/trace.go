@@ -0,0 +1,82 @@
+package log
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceHook adds the trace_id and span_id fields to log entries with the context of a span, see FromContext.
+type TraceHook struct{}
+
+func (h TraceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h TraceHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	spanContext := trace.SpanContextFromContext(entry.Context)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	entry.Data["trace_id"] = spanContext.TraceID().String()
+	entry.Data["span_id"] = spanContext.SpanID().String()
+
+	return nil
+}
+
+// FromContext returns the log entry of the context, the entries contain the IDs of the trace and the span of the context.
+func FromContext(ctx context.Context) *logrus.Entry {
+	return log.Logger().WithContext(ctx)
+}
+
+// RequestLogger logs every request in the format of the echo-logrus logger, with the IDs of the trace and the span of
+// the request. The span has to be started by an earlier middleware.
+func RequestLogger() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			res := c.Response()
+			start := time.Now()
+
+			var err error
+			if err = next(c); err != nil {
+				c.Error(err)
+			}
+			stop := time.Now()
+
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if id == "" {
+				id = res.Header().Get(echo.HeaderXRequestID)
+			}
+			reqSize := req.Header.Get(echo.HeaderContentLength)
+			if reqSize == "" {
+				reqSize = "0"
+			}
+
+			FromContext(req.Context()).Infof("%s %s [%v] %s %-7s %s %3d %s %s %13v %s %s",
+				id,
+				c.RealIP(),
+				stop.Format(time.RFC3339),
+				req.Host,
+				req.Method,
+				req.RequestURI,
+				res.Status,
+				reqSize,
+				strconv.FormatInt(res.Size, 10),
+				stop.Sub(start).String(),
+				req.Referer(),
+				req.UserAgent(),
+			)
+			return err
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHook(t *testing.T) {
+	var output bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&output)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(TraceHook{})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	logger.WithContext(ctx).Info("with span")
+	logger.Info("without span")
+
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(&output)
+	for decoder.More() {
+		var entry map[string]interface{}
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatalf("failed to decode log entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(entries))
+	}
+	if entries[0]["trace_id"] != traceID.String() || entries[0]["span_id"] != spanID.String() {
+		t.Errorf("entry with span = %v, want trace_id and span_id", entries[0])
+	}
+	if _, ok := entries[1]["trace_id"]; ok {
+		t.Errorf("entry without span = %v, want no trace_id", entries[1])
+	}
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+// factsGaugesInterval is how often the facts are counted, the facts change rarely
+const factsGaugesInterval = time.Minute
+
+// FactsGauges counts the approved and unapproved facts in the background, so that scrapes don't read the facts.
+type FactsGauges struct {
+	factsRepository repository.FactsRepository
+	facts           *prometheus.GaugeVec
+}
+
+func NewFactsGauges(factsRepository repository.FactsRepository, registry *Registry) *FactsGauges {
+	f := &FactsGauges{
+		factsRepository: factsRepository,
+		facts: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "facts",
+			Help:      "Number of facts by status (approved or unapproved).",
+		}, []string{"status"}),
+	}
+	registry.MustRegister(f.facts)
+
+	return f
+}
+
+func (f *FactsGauges) Run(ctx context.Context) error {
+	ticker := time.NewTicker(factsGaugesInterval)
+	defer ticker.Stop()
+
+	for {
+		f.update()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *FactsGauges) update() {
+	facts, err := f.factsRepository.ReadAll()
+	if err != nil {
+		log.Logger().WithError(err).Warn("failed to count facts for metrics")
+		return
+	}
+
+	approved := 0
+	for _, fact := range facts {
+		if fact.Approved {
+			approved++
+		}
+	}
+	f.facts.WithLabelValues("approved").Set(float64(approved))
+	f.facts.WithLabelValues("unapproved").Set(float64(len(facts) - approved))
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+// InstrumentingFactsRepository observes the latency and the errors of every operation of the facts repository it
+// decorates. Facts that are not found are no errors.
+type InstrumentingFactsRepository struct {
+	next     repository.FactsRepository
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func NewInstrumentingFactsRepository(next repository.FactsRepository, registry *Registry) repository.FactsRepository {
+	i := &InstrumentingFactsRepository{
+		next: next,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "repository_operation_duration_seconds",
+			Help:        "Latency of repository operations by operation.",
+			ConstLabels: prometheus.Labels{"repository": "facts"},
+			Buckets:     []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "repository_operation_errors_total",
+			Help:        "Number of failed repository operations by operation.",
+			ConstLabels: prometheus.Labels{"repository": "facts"},
+		}, []string{"operation"}),
+	}
+	registry.MustRegister(i.duration, i.errors)
+
+	return i
+}
+
+func (i *InstrumentingFactsRepository) observe(operation string, start time.Time, err error) {
+	i.duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		i.errors.WithLabelValues(operation).Inc()
+	}
+}
+
+func (i *InstrumentingFactsRepository) WithContext(ctx context.Context) repository.FactsRepository {
+	return &InstrumentingFactsRepository{next: i.next.WithContext(ctx), duration: i.duration, errors: i.errors}
+}
+
+func (i *InstrumentingFactsRepository) Create(fact *repository.Fact) error {
+	start := time.Now()
+	err := i.next.Create(fact)
+	i.observe("create", start, err)
+	return err
+}
+
+func (i *InstrumentingFactsRepository) ReadOne(id primitive.ObjectID) (*repository.Fact, error) {
+	start := time.Now()
+	result, err := i.next.ReadOne(id)
+	i.observe("read_one", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) ReadMany(ids []primitive.ObjectID) ([]*repository.Fact, error) {
+	start := time.Now()
+	result, err := i.next.ReadMany(ids)
+	i.observe("read_many", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) ReadManyIDs(filterFunc func(fact *repository.Fact) bool) ([]primitive.ObjectID, error) {
+	start := time.Now()
+	result, err := i.next.ReadManyIDs(filterFunc)
+	i.observe("read_many_ids", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) ReadAll() ([]*repository.Fact, error) {
+	start := time.Now()
+	result, err := i.next.ReadAll()
+	i.observe("read_all", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) ReadPage(query repository.FactsPageQuery) ([]*repository.Fact, error) {
+	start := time.Now()
+	result, err := i.next.ReadPage(query)
+	i.observe("read_page", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) ReadRecentlyApproved(query repository.RecentlyApprovedQuery) ([]*repository.Fact, error) {
+	start := time.Now()
+	result, err := i.next.ReadRecentlyApproved(query)
+	i.observe("read_recently_approved", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) ReadRelated(query repository.RelatedFactsQuery) ([]*repository.Fact, error) {
+	start := time.Now()
+	result, err := i.next.ReadRelated(query)
+	i.observe("read_related", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) ReadLastRemoval() (time.Time, error) {
+	start := time.Now()
+	result, err := i.next.ReadLastRemoval()
+	i.observe("read_last_removal", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) Update(id primitive.ObjectID, updateFunc func(fact *repository.Fact) (*repository.Fact, error)) error {
+	start := time.Now()
+	err := i.next.Update(id, updateFunc)
+	i.observe("update", start, err)
+	return err
+}
+
+func (i *InstrumentingFactsRepository) Delete(id primitive.ObjectID) error {
+	start := time.Now()
+	err := i.next.Delete(id)
+	i.observe("delete", start, err)
+	return err
+}
+
+func (i *InstrumentingFactsRepository) Count() (int, error) {
+	start := time.Now()
+	result, err := i.next.Count()
+	i.observe("count", start, err)
+	return result, err
+}
+
+func (i *InstrumentingFactsRepository) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := i.next.Ping(ctx)
+	i.observe("ping", start, err)
+	return err
+}
+
+func (i *InstrumentingFactsRepository) Close() error {
+	return i.next.Close()
+}
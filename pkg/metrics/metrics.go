@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+)
+
+const namespace = "animal_facts"
+
+// Registry contains the metrics of a server, every server has its own registry.
+type Registry struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// NewRegistry creates the registry with the HTTP metrics, the Go runtime and process metrics and the build info of
+// the service.
+func NewRegistry(service string, version string) *Registry {
+	registry := prometheus.NewRegistry()
+	r := &Registry{
+		registry: registry,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "build_info",
+		Help:        "Build info of the service, the value is always 1.",
+		ConstLabels: prometheus.Labels{"service": service, "version": version, "go_version": runtime.Version()},
+	})
+	buildInfo.Set(1)
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		buildInfo,
+		r.requests,
+		r.requestDuration,
+	)
+
+	return r
+}
+
+// MustRegister registers further collectors, it panics if a collector is registered twice.
+func (r *Registry) MustRegister(collectors ...prometheus.Collector) {
+	r.registry.MustRegister(collectors...)
+}
+
+// Handler serves the metrics in the Prometheus format.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
+
+// Middleware counts the requests and observes their latency. The route is the path of the route, e.g.
+// /api/v1/facts/:id, so that the number of label values doesn't grow with the IDs. Requests without route are counted
+// as route "unmatched".
+func (r *Registry) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			// errors are written by the error handler of echo after the middlewares, so their status is taken from the
+			// error
+			status := c.Response().Status
+			if err != nil && !c.Response().Committed {
+				status = problem.StatusOf(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			labels := prometheus.Labels{"method": c.Request().Method, "route": route, "status": strconv.Itoa(status)}
+			r.requests.With(labels).Inc()
+			r.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+			return err
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/validation"
+)
+
+func scrape(t *testing.T, registry *Registry) string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d, want 200", recorder.Code)
+	}
+	return recorder.Body.String()
+}
+
+func assertMetrics(t *testing.T, metrics string, want ...string) {
+	t.Helper()
+
+	for _, line := range want {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("metrics don't contain %s", line)
+		}
+	}
+}
+
+func TestRegistry_Middleware(t *testing.T) {
+	registry := NewRegistry("test-api", "1.2.3")
+	e := echo.New()
+	e.Use(registry.Middleware())
+	e.GET("/facts/:id", func(c echo.Context) error {
+		switch c.Param("id") {
+		case "error":
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid id")
+		case "missing":
+			return problem.NotFound("fact not found")
+		case "invalid":
+			return validation.Errors{{Field: "fact", Message: "must not be empty"}}
+		case "failure":
+			return errors.New("failed to read fact")
+		}
+		return c.String(http.StatusOK, "fact")
+	})
+
+	for _, path := range []string{"/facts/1", "/facts/2", "/facts/error", "/facts/missing", "/facts/invalid", "/facts/failure", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assertMetrics(t, scrape(t, registry),
+		`animal_facts_http_requests_total{method="GET",route="/facts/:id",status="200"} 2`,
+		`animal_facts_http_requests_total{method="GET",route="/facts/:id",status="400"} 1`,
+		`animal_facts_http_requests_total{method="GET",route="/facts/:id",status="404"} 1`,
+		`animal_facts_http_requests_total{method="GET",route="/facts/:id",status="422"} 1`,
+		`animal_facts_http_requests_total{method="GET",route="/facts/:id",status="500"} 1`,
+		`animal_facts_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`animal_facts_http_request_duration_seconds_count{method="GET",route="/facts/:id",status="200"} 2`,
+		`animal_facts_build_info{go_version="`+runtime.Version()+`",service="test-api",version="1.2.3"} 1`,
+	)
+}
+
+func TestInstrumentingFactsRepository(t *testing.T) {
+	registry := NewRegistry("test-api", "1.2.3")
+	id := primitive.NewObjectID()
+	factsRepository := NewInstrumentingFactsRepository(repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{
+		id: {ID: id, Fact: "Whales sing.", Approved: true},
+	}, false), registry)
+
+	if _, err := factsRepository.ReadOne(id); err != nil {
+		t.Fatalf("ReadOne() unexpected error = %v", err)
+	}
+	if _, err := factsRepository.ReadOne(primitive.NewObjectID()); err == nil {
+		t.Fatalf("ReadOne() want not found error")
+	}
+
+	failingRepository := NewInstrumentingFactsRepository(repository.NewMockFactsRepository(nil, true), NewRegistry("failing-api", "1.2.3"))
+	if _, err := failingRepository.Count(); err == nil {
+		t.Fatalf("Count() want error")
+	}
+
+	metrics := scrape(t, registry)
+	assertMetrics(t, metrics,
+		`animal_facts_repository_operation_duration_seconds_count{operation="read_one",repository="facts"} 2`,
+	)
+	if strings.Contains(metrics, `animal_facts_repository_operation_errors_total{operation="read_one"`) {
+		t.Errorf("facts that are not found are counted as errors")
+	}
+}
+
+func TestFactsGauges(t *testing.T) {
+	registry := NewRegistry("test-api", "1.2.3")
+	approvedID, unapprovedID := primitive.NewObjectID(), primitive.NewObjectID()
+	gauges := NewFactsGauges(repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{
+		approvedID:   {ID: approvedID, Approved: true},
+		unapprovedID: {ID: unapprovedID},
+	}, false), registry)
+
+	gauges.update()
+
+	assertMetrics(t, scrape(t, registry),
+		`animal_facts_facts{status="approved"} 1`,
+		`animal_facts_facts{status="unapproved"} 1`,
+	)
+}
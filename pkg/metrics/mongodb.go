@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// NewPoolMonitor returns a monitor of the connection pools of a mongo db client, which exposes the open and checked
+// out connections and the failed checkouts of all pools of the client.
+func NewPoolMonitor(registry *Registry) *event.PoolMonitor {
+	connections := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "mongodb_pool_connections",
+		Help:      "Number of open connections of the mongo db connection pools.",
+	})
+	connectionsInUse := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "mongodb_pool_connections_in_use",
+		Help:      "Number of checked out connections of the mongo db connection pools.",
+	})
+	checkoutFailures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mongodb_pool_checkout_failures_total",
+		Help:      "Number of failed connection checkouts of the mongo db connection pools by reason.",
+	}, []string{"reason"})
+	registry.MustRegister(connections, connectionsInUse, checkoutFailures)
+
+	return &event.PoolMonitor{
+		Event: func(poolEvent *event.PoolEvent) {
+			switch poolEvent.Type {
+			case event.ConnectionCreated:
+				connections.Inc()
+			case event.ConnectionClosed:
+				connections.Dec()
+			case event.GetSucceeded:
+				connectionsInUse.Inc()
+			case event.ConnectionReturned:
+				connectionsInUse.Dec()
+			case event.GetFailed:
+				checkoutFailures.WithLabelValues(poolEvent.Reason).Inc()
+			}
+		},
+	}
+}
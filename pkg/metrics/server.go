@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+)
+
+// Server serves the metrics on /metrics on its own port, so that the metrics are not public with the API.
+type Server struct {
+	port     int
+	registry *Registry
+	mux      *http.ServeMux
+}
+
+func NewServer(port int, registry *Registry) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	return &Server{port: port, registry: registry, mux: mux}
+}
+
+// Handle serves the handler on the pattern next to the metrics, e.g. for debug endpoints that must not be public.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{Addr: fmt.Sprintf(":%d", s.port), Handler: s.mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Logger().WithError(err).Warn("failed to shut down metrics server")
+		}
+	}()
+
+	log.Logger().Infof("serving metrics on :%d/metrics", s.port)
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrapf(err, "failed to serve metrics on port %d", s.port)
+	}
+
+	return nil
+}
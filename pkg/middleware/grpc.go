@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/auth0/go-jwt-middleware/v2/validator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenValidator validates a JWT and returns its claims, like validator.Validator.ValidateToken.
+type TokenValidator func(ctx context.Context, token string) (interface{}, error)
+
+// EnsureValidTokenGRPC is the gRPC counterpart of EnsureValidToken and VerifyScope. Methods of the protectedServices
+// (full service names like facts.v1.FactsAdminService) and methods that are a key of requiredScopes need a valid JWT in
+// the authorization metadata ("Bearer <token>") with the scope of the method. Methods of the protectedServices without
+// a scope in requiredScopes are denied, so that a new method is not public by default. Methods of other services are
+// not authenticated.
+func EnsureValidTokenGRPC(validateToken TokenValidator, protectedServices []string, requiredScopes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		scope, hasScope := requiredScopes[info.FullMethod]
+		protected := slices.ContainsFunc(protectedServices, func(service string) bool {
+			return strings.HasPrefix(info.FullMethod, "/"+service+"/")
+		})
+		if !hasScope && !protected {
+			return handler(ctx, req)
+		}
+
+		token, ok := bearerToken(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata with a bearer token is missing")
+		}
+
+		validatedToken, err := validateToken(ctx, token)
+		if err != nil {
+			log.Printf("Encountered error while validating JWT: %v", err)
+			return nil, status.Error(codes.Unauthenticated, "failed to validate JWT")
+		}
+
+		claims, ok := validatedToken.(*validator.ValidatedClaims)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "failed to validate JWT")
+		}
+		if !hasScope {
+			log.Printf("Denied gRPC method %s of a protected service without required scope", info.FullMethod)
+			return nil, status.Error(codes.PermissionDenied, "method has no required scope")
+		}
+		customClaims, ok := claims.CustomClaims.(*CustomClaims)
+		if !ok || !customClaims.HasScope(scope) {
+			return nil, status.Error(codes.PermissionDenied, "user does not have sufficient permission")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	for _, authorization := range md.Get("authorization") {
+		scheme, token, found := strings.Cut(authorization, " ")
+		if found && strings.EqualFold(scheme, "Bearer") && token != "" {
+			return token, true
+		}
+	}
+
+	return "", false
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"net/url"
@@ -13,6 +14,8 @@ import (
 	"github.com/auth0/go-jwt-middleware/v2/jwks"
 	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
 )
 
 // CustomClaims contains custom data we want from the token.
@@ -26,8 +29,9 @@ func (c CustomClaims) Validate(ctx context.Context) error {
 	return nil
 }
 
-// EnsureValidToken is a middleware that will check the validity of our JWT.
-func EnsureValidToken() func(next echo.HandlerFunc) echo.HandlerFunc {
+// NewJWTValidator sets up the validator for the JWTs of our Auth0 tenant, which is shared by the HTTP middleware and
+// the gRPC interceptor.
+func NewJWTValidator() *validator.Validator {
 	issuerURL, err := url.Parse("https://" + os.Getenv("AUTH0_DOMAIN") + "/")
 	if err != nil {
 		log.Fatalf("Failed to parse the issuer url: %v", err)
@@ -51,12 +55,15 @@ func EnsureValidToken() func(next echo.HandlerFunc) echo.HandlerFunc {
 		log.Fatalf("Failed to set up the jwt validator")
 	}
 
+	return jwtValidator
+}
+
+// EnsureValidToken is a middleware that will check the validity of our JWT.
+func EnsureValidToken() func(next echo.HandlerFunc) echo.HandlerFunc {
+	jwtValidator := NewJWTValidator()
+
 	errorHandler := func(w http.ResponseWriter, r *http.Request, err error) {
 		log.Printf("Encountered error while validating JWT: %v", err)
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"message":"Failed to validate JWT."}`))
 	}
 
 	middleware := jwtmiddleware.New(
@@ -64,11 +71,23 @@ func EnsureValidToken() func(next echo.HandlerFunc) echo.HandlerFunc {
 		jwtmiddleware.WithErrorHandler(errorHandler),
 	)
 
+	// the handler runs with the echo context of the request, so that its errors reach the error handler of the router,
+	// if the JWT is not valid the handler is not called and the request is unauthorized
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			next(echo.New().NewContext(r, w))
-		})
-		return echo.WrapHandler(middleware.CheckJWT(httpHandler))
+		return func(c echo.Context) error {
+			called := false
+			var err error
+			middleware.CheckJWT(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				c.SetRequest(r)
+				err = next(c)
+			})).ServeHTTP(c.Response(), c.Request())
+
+			if !called {
+				return problem.Unauthorized("failed to validate JWT")
+			}
+			return err
+		}
 	}
 }
 
@@ -83,3 +102,25 @@ func (c CustomClaims) HasScope(expectedScope string) bool {
 
 	return false
 }
+
+// CheckJWKS checks that the JWKS of our Auth0 tenant can be fetched, without it no JWT can be validated once the cached
+// keys expire. It is the health check of the JWT validation.
+func CheckJWKS(ctx context.Context) error {
+	jwksURL := "https://" + os.Getenv("AUTH0_DOMAIN") + "/.well-known/jwks.json"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request of JWKS %s: %w", jwksURL, err)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS %s: %w", jwksURL, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS %s: status %d", jwksURL, response.StatusCode)
+	}
+
+	return nil
+}
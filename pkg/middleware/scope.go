@@ -1,16 +1,12 @@
 package middleware
 
 import (
-	"net/http"
-
 	jwtmiddleware "github.com/auth0/go-jwt-middleware/v2"
 	"github.com/auth0/go-jwt-middleware/v2/validator"
 	"github.com/labstack/echo/v4"
-)
 
-type ErrorResult struct {
-	Error string `json:"error"`
-}
+	"github.com/cafo13/animal-facts/pkg/problem"
+)
 
 func VerifyScope(scope string) func(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -19,7 +15,7 @@ func VerifyScope(scope string) func(next echo.HandlerFunc) echo.HandlerFunc {
 
 			claims := token.CustomClaims.(*CustomClaims)
 			if !claims.HasScope(scope) {
-				return c.JSON(http.StatusUnauthorized, ErrorResult{Error: "user does not have sufficient permission"})
+				return problem.Forbidden("user does not have sufficient permission")
 			}
 			return next(c)
 		}
@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultLease           = 30 * time.Second
+	defaultInitialBackoff  = time.Second
+	defaultMaxBackoff      = 5 * time.Minute
+	defaultRetention       = 7 * 24 * time.Hour
+	defaultCleanupInterval = time.Hour
+)
+
+// Sink receives the events of the outbox. Deliver is called again for the same event if it failed or the relay
+// stopped before recording the delivery, so sinks have to use the ID of the event to ignore duplicates.
+type Sink interface {
+	// Name identifies the sink in the deliveries recorded in the outbox, it has to be unique and must not change
+	// between restarts.
+	Name() string
+	Deliver(ctx context.Context, event *repository.OutboxEvent) error
+}
+
+// Relay delivers the pending events of the outbox to the sinks in the order they were written. Events are delivered
+// at least once: failed sinks are retried with exponential backoff until they succeed, the sinks that already
+// succeeded don't get the event again. Delivered events are deleted after the retention.
+type Relay struct {
+	outboxRepository repository.OutboxRepository
+	sinks            []Sink
+	pollInterval     time.Duration
+	lease            time.Duration
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	retention        time.Duration
+	cleanupInterval  time.Duration
+}
+
+func NewRelay(outboxRepository repository.OutboxRepository, sinks ...Sink) *Relay {
+	return &Relay{
+		outboxRepository: outboxRepository,
+		sinks:            sinks,
+		pollInterval:     defaultPollInterval,
+		lease:            defaultLease,
+		initialBackoff:   defaultInitialBackoff,
+		maxBackoff:       defaultMaxBackoff,
+		retention:        defaultRetention,
+		cleanupInterval:  defaultCleanupInterval,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) error {
+	pollTicker := time.NewTicker(r.pollInterval)
+	defer pollTicker.Stop()
+	cleanupTicker := time.NewTicker(r.cleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-pollTicker.C:
+			if err := r.relayDue(ctx); err != nil {
+				log.Logger().WithError(err).Error("failed to relay due outbox events")
+			}
+		case <-cleanupTicker.C:
+			deleted, err := r.outboxRepository.DeleteDelivered(time.Now().Add(-r.retention))
+			if err != nil {
+				log.Logger().WithError(err).Error("failed to delete delivered outbox events")
+			} else if deleted > 0 {
+				log.Logger().Infof("deleted %d delivered outbox events", deleted)
+			}
+		}
+	}
+}
+
+// relayDue claims and delivers due events one after the other until no event is due, so that the sinks receive the
+// events in the order they were written as long as no sink fails.
+func (r *Relay) relayDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		event, err := r.outboxRepository.ClaimDueEvent(time.Now(), r.lease)
+		if errors.Is(err, repository.ErrOutboxEventNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := r.relay(ctx, event); err != nil {
+			return errors.Wrapf(err, "failed to record delivery of outbox event %s", event.ID.Hex())
+		}
+	}
+
+	return nil
+}
+
+func (r *Relay) relay(ctx context.Context, event *repository.OutboxEvent) error {
+	deliveredTo := slices.Clone(event.DeliveredTo)
+	var failures []string
+	for _, sink := range r.sinks {
+		if slices.Contains(deliveredTo, sink.Name()) {
+			continue
+		}
+
+		if err := sink.Deliver(ctx, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sink.Name(), err))
+			continue
+		}
+		deliveredTo = append(deliveredTo, sink.Name())
+	}
+
+	return r.outboxRepository.UpdateEvent(event.ID, func(event *repository.OutboxEvent) *repository.OutboxEvent {
+		now := time.Now()
+		event.DeliveredTo = deliveredTo
+		if len(failures) == 0 {
+			event.Status = repository.OutboxEventStatusDelivered
+			event.DeliveredAt = now
+			event.LastError = ""
+		} else {
+			event.Failures++
+			event.LastError = strings.Join(failures, "; ")
+			event.NextAttemptAt = now.Add(r.backoff(event.Failures))
+		}
+		return event
+	})
+}
+
+// backoff doubles the wait time after every failure, starting with initialBackoff.
+func (r *Relay) backoff(failures int) time.Duration {
+	backoff := r.initialBackoff
+	for i := 1; i < failures && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, r.maxBackoff)
+}
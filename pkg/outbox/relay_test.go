@@ -0,0 +1,247 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+// recordingSink records the events it receives and fails the first failures deliveries.
+type recordingSink struct {
+	name     string
+	failures int
+	mutex    sync.Mutex
+	events   []*repository.OutboxEvent
+}
+
+func (r *recordingSink) Name() string {
+	return r.name
+}
+
+func (r *recordingSink) Deliver(ctx context.Context, event *repository.OutboxEvent) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.events = append(r.events, event)
+	if len(r.events) <= r.failures {
+		return errors.New("sink is not available")
+	}
+
+	return nil
+}
+
+func (r *recordingSink) received() []*repository.OutboxEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*repository.OutboxEvent{}, r.events...)
+}
+
+func newTestRelay(outboxRepository repository.OutboxRepository, sinks ...Sink) *Relay {
+	relay := NewRelay(outboxRepository, sinks...)
+	relay.initialBackoff = 10 * time.Millisecond
+	return relay
+}
+
+func TestRelay_deliversWritesInOrder(t *testing.T) {
+	outboxRepository := repository.NewMockOutboxRepository(false)
+	approvedFact := &repository.Fact{ID: primitive.NewObjectID(), Fact: "Whales sing.", Approved: true}
+	unapprovedFact := &repository.Fact{ID: primitive.NewObjectID(), Fact: "Cats purr."}
+	factsRepository := repository.NewMockFactsRepositoryWithOutbox(map[primitive.ObjectID]*repository.Fact{
+		approvedFact.ID:   approvedFact,
+		unapprovedFact.ID: unapprovedFact,
+	}, outboxRepository, false)
+
+	writes := []func() error{
+		func() error {
+			return factsRepository.Create(&repository.Fact{ID: primitive.NewObjectID(), Fact: "Owls hoot."})
+		},
+		func() error {
+			return factsRepository.Update(approvedFact.ID, func(fact *repository.Fact) (*repository.Fact, error) {
+				fact.Source = "whales.example.com"
+				return fact, nil
+			})
+		},
+		func() error {
+			return factsRepository.Update(unapprovedFact.ID, func(fact *repository.Fact) (*repository.Fact, error) {
+				fact.Approved = true
+				return fact, nil
+			})
+		},
+		func() error {
+			return factsRepository.Update(approvedFact.ID, func(fact *repository.Fact) (*repository.Fact, error) {
+				fact.Approved = false
+				return fact, nil
+			})
+		},
+		// updates that change neither the approval nor the public content have no events
+		func() error {
+			return factsRepository.Update(unapprovedFact.ID, func(fact *repository.Fact) (*repository.Fact, error) {
+				fact.Approved = true
+				fact.Flagged = true
+				return fact, nil
+			})
+		},
+		func() error { return factsRepository.Delete(unapprovedFact.ID) },
+		// writes of facts that don't exist have no events
+		func() error { return factsRepository.Delete(primitive.NewObjectID()) },
+	}
+	for _, write := range writes {
+		if err := write(); err != nil {
+			t.Fatalf("write unexpected error = %v", err)
+		}
+	}
+
+	sink := &recordingSink{name: "recording"}
+	if err := newTestRelay(outboxRepository, sink).relayDue(context.Background()); err != nil {
+		t.Fatalf("relayDue() unexpected error = %v", err)
+	}
+
+	wantTypes := []events.Type{events.TypeFactCreated, events.TypeFactUpdated, events.TypeFactApproved, events.TypeFactUnapproved, events.TypeFactDeleted}
+	received := sink.received()
+	if len(received) != len(wantTypes) {
+		t.Fatalf("received %d events, want %d", len(received), len(wantTypes))
+	}
+	for i, event := range received {
+		if event.Type != string(wantTypes[i]) {
+			t.Errorf("event %d has type %s, want %s", i, event.Type, wantTypes[i])
+		}
+	}
+	if received[4].AggregateID != unapprovedFact.ID.Hex() || received[4].Data != "" {
+		t.Errorf("deleted event = %+v, want the ID of the fact without data", received[4])
+	}
+
+	var fact repository.Fact
+	if err := json.Unmarshal([]byte(received[1].Data), &fact); err != nil || fact.Source != "whales.example.com" || fact.Revision != 1 {
+		t.Errorf("updated event has data %s, want the updated fact", received[1].Data)
+	}
+
+	if _, err := outboxRepository.ClaimDueEvent(time.Now(), time.Minute); !errors.Is(err, repository.ErrOutboxEventNotFound) {
+		t.Errorf("ClaimDueEvent() error = %v, want all events to be delivered", err)
+	}
+}
+
+func TestRelay_retriesFailedSinks(t *testing.T) {
+	outboxRepository := repository.NewMockOutboxRepository(false)
+	factsRepository := repository.NewMockFactsRepositoryWithOutbox(map[primitive.ObjectID]*repository.Fact{}, outboxRepository, false)
+	if err := factsRepository.Create(&repository.Fact{ID: primitive.NewObjectID()}); err != nil {
+		t.Fatalf("Create() unexpected error = %v", err)
+	}
+
+	reliableSink := &recordingSink{name: "reliable"}
+	flakySink := &recordingSink{name: "flaky", failures: 2}
+	relay := newTestRelay(outboxRepository, reliableSink, flakySink)
+
+	for i := 0; i < 3; i++ {
+		if err := relay.relayDue(context.Background()); err != nil {
+			t.Fatalf("relayDue() unexpected error = %v", err)
+		}
+		if i < 2 && len(flakySink.received()) != i+1 {
+			t.Fatalf("flaky sink received %d events after %d relays, want the event to wait for the backoff", len(flakySink.received()), i+1)
+		}
+		time.Sleep(relay.backoff(i+1) + 5*time.Millisecond)
+	}
+
+	if len(reliableSink.received()) != 1 {
+		t.Errorf("reliable sink received %d events, want the event once", len(reliableSink.received()))
+	}
+	flakyEvents := flakySink.received()
+	if len(flakyEvents) != 3 || flakyEvents[0].ID != flakyEvents[2].ID {
+		t.Errorf("flaky sink received %d events, want the same event three times", len(flakyEvents))
+	}
+	if _, err := outboxRepository.ClaimDueEvent(time.Now(), time.Minute); !errors.Is(err, repository.ErrOutboxEventNotFound) {
+		t.Errorf("ClaimDueEvent() error = %v, want the event to be delivered", err)
+	}
+	if deleted, err := outboxRepository.DeleteDelivered(time.Now()); err != nil || deleted != 1 {
+		t.Errorf("DeleteDelivered() = %d, %v, want the delivered event to be deleted", deleted, err)
+	}
+}
+
+func TestRelay_Run(t *testing.T) {
+	outboxRepository := repository.NewMockOutboxRepository(false)
+	factsRepository := repository.NewMockFactsRepositoryWithOutbox(map[primitive.ObjectID]*repository.Fact{}, outboxRepository, false)
+	fact := &repository.Fact{ID: primitive.NewObjectID(), Fact: "Whales sing."}
+	if err := factsRepository.Create(fact); err != nil {
+		t.Fatalf("Create() unexpected error = %v", err)
+	}
+
+	var idempotencyKeys []string
+	var messages []Message
+	var mutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var message Message
+		_ = json.Unmarshal(body, &message)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		idempotencyKeys = append(idempotencyKeys, r.Header.Get(HeaderIdempotencyKey))
+		messages = append(messages, message)
+		// the first attempt fails, the retry has the same idempotency key
+		if len(messages) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	bus := events.NewBus()
+	subscription := bus.Subscribe(0)
+	defer subscription.Close()
+
+	relay := newTestRelay(outboxRepository, NewLogSink(), NewBusSink(bus), NewHTTPSink(server.URL))
+	relay.pollInterval = 5 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- relay.Run(ctx)
+	}()
+
+	select {
+	case event := <-subscription.Events:
+		if event.Type != events.TypeFactCreated || event.FactID != fact.ID.Hex() {
+			t.Errorf("bus received %+v, want the created event", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bus received no event")
+	}
+
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(5 * time.Millisecond) {
+		mutex.Lock()
+		received := len(messages)
+		mutex.Unlock()
+		if received == 2 {
+			break
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() unexpected error = %v", err)
+	}
+
+	select {
+	case event, ok := <-subscription.Events:
+		if ok {
+			t.Errorf("bus received %+v, want the event only once", event)
+		}
+	default:
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(messages) != 2 || idempotencyKeys[0] == "" || idempotencyKeys[0] != idempotencyKeys[1] || idempotencyKeys[0] != messages[0].ID {
+		t.Fatalf("http sink received %v with idempotency keys %v, want the event twice with the same key", messages, idempotencyKeys)
+	}
+	if messages[1].Type != string(events.TypeFactCreated) || messages[1].AggregateID != fact.ID.Hex() || len(messages[1].Data) == 0 {
+		t.Errorf("http sink received %+v, want the created fact", messages[1])
+	}
+}
@@ -0,0 +1,133 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/sirupsen/logrus"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	defaultHTTPTimeout = 10 * time.Second
+)
+
+// Message is the JSON body the HTTP sink sends for an event.
+type Message struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregateId"`
+	Time        time.Time       `json:"time"`
+	Data        json.RawMessage `json:"data,omitempty"`
+}
+
+func eventData(event *repository.OutboxEvent) json.RawMessage {
+	if event.Data == "" {
+		return nil
+	}
+
+	return json.RawMessage(event.Data)
+}
+
+// LogSink logs the events.
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (l *LogSink) Name() string {
+	return "log"
+}
+
+func (l *LogSink) Deliver(ctx context.Context, event *repository.OutboxEvent) error {
+	log.Logger().WithFields(logrus.Fields{
+		"id":          event.ID.Hex(),
+		"type":        event.Type,
+		"aggregateId": event.AggregateID,
+	}).Info("outbox event")
+	return nil
+}
+
+// BusSink publishes the events to the event bus of the process that runs the relay, the event IDs of the bus are
+// assigned when the event is published. The ID of the outbox event is the key of the published event, so that the bus
+// doesn't publish an event that is delivered again, and subscribers like webhooks can deduplicate it.
+type BusSink struct {
+	eventBus *events.Bus
+}
+
+func NewBusSink(eventBus *events.Bus) *BusSink {
+	return &BusSink{eventBus}
+}
+
+func (b *BusSink) Name() string {
+	return "bus"
+}
+
+func (b *BusSink) Deliver(ctx context.Context, event *repository.OutboxEvent) error {
+	var data interface{}
+	if rawData := eventData(event); rawData != nil {
+		data = rawData
+	}
+	b.eventBus.PublishOnce(event.ID.Hex(), events.Type(event.Type), event.AggregateID, data)
+	return nil
+}
+
+// HTTPSink posts the events as Message to a URL with the ID of the event in the Idempotency-Key header, every response
+// with a status other than 2xx is a failure.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{url, &http.Client{Timeout: defaultHTTPTimeout}}
+}
+
+func (h *HTTPSink) Name() string {
+	return "http:" + h.url
+}
+
+func (h *HTTPSink) Deliver(ctx context.Context, event *repository.OutboxEvent) error {
+	body, err := json.Marshal(Message{
+		ID:          event.ID.Hex(),
+		Type:        event.Type,
+		AggregateID: event.AggregateID,
+		Time:        event.CreatedAt.UTC(),
+		Data:        eventData(event),
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderIdempotencyKey, event.ID.Hex())
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// the body is drained, so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MediaTypeMergePatch is the media type of JSON Merge Patches, see RFC 7396.
+	MediaTypeMergePatch = "application/merge-patch+json"
+	// MediaTypeJSONPatch is the media type of JSON Patches, see RFC 6902.
+	MediaTypeJSONPatch = "application/json-patch+json"
+)
+
+var (
+	// ErrUnsupportedMediaType is a patch that is neither a JSON Merge Patch nor a JSON Patch.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrInvalidPatch is a patch that is malformed, e.g. an operation without path or with a path that is no JSON Pointer.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPathNotFound is a path of an operation that doesn't exist in the document.
+	ErrPathNotFound = errors.New("path not found")
+	// ErrTestFailed is a test operation whose value is not the value of the document.
+	ErrTestFailed = errors.New("test failed")
+)
+
+// OperationError is the error of an operation of a JSON Patch, no operation of the patch is applied then.
+type OperationError struct {
+	Index int
+	Op    string
+	Path  string
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %d (%s %s): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// Patch changes a JSON document.
+type Patch interface {
+	Apply(document []byte) ([]byte, error)
+}
+
+// Parse parses the patch of the media type, which is MediaTypeMergePatch or MediaTypeJSONPatch.
+func Parse(mediaType string, body []byte) (Patch, error) {
+	switch mediaType {
+	case MediaTypeMergePatch:
+		return parseMergePatch(body)
+	case MediaTypeJSONPatch:
+		return parseJSONPatch(body)
+	}
+
+	return nil, errors.Wrapf(ErrUnsupportedMediaType, "media type %q is neither %s nor %s", mediaType, MediaTypeMergePatch, MediaTypeJSONPatch)
+}
+
+// MergePatch replaces the members of the document with the members of the patch, members with the value null are
+// removed. The documents of the apis are objects, so only objects are valid merge patches.
+type MergePatch struct {
+	patch map[string]any
+}
+
+func parseMergePatch(body []byte) (*MergePatch, error) {
+	var patch map[string]any
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return nil, errors.Wrap(ErrInvalidPatch, "merge patch must be a JSON object")
+	}
+
+	return &MergePatch{patch: patch}, nil
+}
+
+func (m *MergePatch) Apply(document []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal document")
+	}
+
+	return json.Marshal(mergePatch(target, m.patch))
+}
+
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+
+	return targetObject
+}
+
+// Operation is an operation of a JSON Patch, Op is add, remove, replace, move, copy or test.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+// JSONPatch applies its operations in order, if an operation fails the document stays unchanged.
+type JSONPatch []Operation
+
+func parseJSONPatch(body []byte) (JSONPatch, error) {
+	var patch JSONPatch
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return nil, errors.Wrap(ErrInvalidPatch, "JSON patch must be an array of operations")
+	}
+
+	return patch, nil
+}
+
+func (p JSONPatch) Apply(document []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal document")
+	}
+
+	for i, operation := range p {
+		var err error
+		if target, err = operation.apply(target); err != nil {
+			return nil, &OperationError{Index: i, Op: operation.Op, Path: operation.Path, Err: err}
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func (o Operation) apply(document any) (any, error) {
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case "add":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "remove":
+		return remove(document, path)
+	case "replace":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if document, err = remove(document, path); err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "move":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(from) < len(path) && slices.Equal(from, path[:len(from)]) {
+			return nil, errors.Wrap(ErrInvalidPatch, "a value can't be moved into one of its children")
+		}
+		value, err := get(document, from)
+		if err != nil {
+			return nil, err
+		}
+		if document, err = remove(document, from); err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "copy":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, path, deepCopy(value))
+	case "test":
+		want, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, want) {
+			return nil, errors.Wrapf(ErrTestFailed, "value is %s", mustMarshal(value))
+		}
+		return document, nil
+	}
+
+	return nil, errors.Wrapf(ErrInvalidPatch, "unknown op %q", o.Op)
+}
+
+func (o Operation) value() (any, error) {
+	if o.Value == nil {
+		return nil, errors.Wrapf(ErrInvalidPatch, "op %s needs a value", o.Op)
+	}
+
+	var value any
+	if err := json.Unmarshal(o.Value, &value); err != nil {
+		return nil, errors.Wrap(ErrInvalidPatch, "value is not valid JSON")
+	}
+
+	return value, nil
+}
+
+// parsePointer parses the JSON Pointer (RFC 6901) to its unescaped reference tokens, the empty pointer is the document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Wrapf(ErrInvalidPatch, "path %q is not a JSON pointer", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses the token of an array with the length, the index after the last item is valid if forAdd is set.
+func arrayIndex(token string, length int, forAdd bool) (int, error) {
+	if token == "-" && forAdd {
+		return length, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.Wrapf(ErrInvalidPatch, "%q is not an array index", token)
+	}
+	if index > length || (index == length && !forAdd) {
+		return 0, errors.Wrapf(ErrPathNotFound, "index %d is out of range", index)
+	}
+
+	return index, nil
+}
+
+func get(document any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := document.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, errors.Wrapf(ErrPathNotFound, "member %q doesn't exist", token)
+			}
+			document = value
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			document = node[index]
+		default:
+			return nil, errors.Wrapf(ErrPathNotFound, "%q is neither an object nor an array", token)
+		}
+	}
+
+	return document, nil
+}
+
+// change changes the parent of the last token of the path with the change function and returns the changed document,
+// arrays are replaced by the changed arrays as they can grow.
+func change(document any, path []string, changeFunc func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return changeFunc(document, path[0])
+	}
+
+	switch node := document.(type) {
+	case map[string]any:
+		child, ok := node[path[0]]
+		if !ok {
+			return nil, errors.Wrapf(ErrPathNotFound, "member %q doesn't exist", path[0])
+		}
+		changed, err := change(child, path[1:], changeFunc)
+		if err != nil {
+			return nil, err
+		}
+		node[path[0]] = changed
+		return node, nil
+	case []any:
+		index, err := arrayIndex(path[0], len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		changed, err := change(node[index], path[1:], changeFunc)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = changed
+		return node, nil
+	}
+
+	return nil, errors.Wrapf(ErrPathNotFound, "%q is neither an object nor an array", path[0])
+}
+
+func add(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return change(document, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(node, index, value), nil
+		}
+
+		return nil, errors.Wrapf(ErrPathNotFound, "parent of %q is neither an object nor an array", token)
+	})
+}
+
+func remove(document any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.Wrap(ErrInvalidPatch, "the document can't be removed")
+	}
+
+	return change(document, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			if _, ok := node[token]; !ok {
+				return nil, errors.Wrapf(ErrPathNotFound, "member %q doesn't exist", token)
+			}
+			delete(node, token)
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(node, index, index+1), nil
+		}
+
+		return nil, errors.Wrapf(ErrPathNotFound, "parent of %q is neither an object nor an array", token)
+	})
+}
+
+func deepCopy(value any) any {
+	var copied any
+	_ = json.Unmarshal(mustMarshal(value), &copied)
+	return copied
+}
+
+// mustMarshal marshals values that were unmarshalled from JSON, which can't fail.
+func mustMarshal(value any) []byte {
+	marshalled, _ := json.Marshal(value)
+	return marshalled
+}
@@ -0,0 +1,147 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testDocument = `{"fact":"Octopuses have three hearts.","source":"https://factanimal.com/","tags":["ocean","mollusc"],"a/b":{"~c":1}}`
+
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("Apply() = %s, want JSON: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid want %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("Apply() = %s, want %s", got, want)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	patch, err := Parse(MediaTypeMergePatch, []byte(`{"fact":"Octopuses have blue blood.","source":null,"a/b":{"d":2}}`))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error = %v", err)
+	}
+
+	patched, err := patch.Apply([]byte(testDocument))
+	if err != nil {
+		t.Fatalf("Apply() unexpected error = %v", err)
+	}
+	assertJSON(t, patched, `{"fact":"Octopuses have blue blood.","tags":["ocean","mollusc"],"a/b":{"~c":1,"d":2}}`)
+
+	for _, body := range []string{`["fact"]`, `null`, `{"fact":`} {
+		if _, err := Parse(MediaTypeMergePatch, []byte(body)); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("Parse(%s) error = %v, want ErrInvalidPatch", body, err)
+		}
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "add, replace and remove",
+			patch: `[{"op":"add","path":"/animal","value":"octopus"},{"op":"replace","path":"/fact","value":"Octopuses have blue blood."},{"op":"remove","path":"/tags/1"}]`,
+			want:  `{"fact":"Octopuses have blue blood.","source":"https://factanimal.com/","animal":"octopus","tags":["ocean"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "add to arrays",
+			patch: `[{"op":"add","path":"/tags/0","value":"cephalopod"},{"op":"add","path":"/tags/-","value":"smart"}]`,
+			want:  `{"fact":"Octopuses have three hearts.","source":"https://factanimal.com/","tags":["cephalopod","ocean","mollusc","smart"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:  "escaped pointers, move and copy",
+			patch: `[{"op":"move","from":"/a~1b/~0c","path":"/count"},{"op":"copy","from":"/tags","path":"/labels"}]`,
+			want:  `{"fact":"Octopuses have three hearts.","source":"https://factanimal.com/","tags":["ocean","mollusc"],"labels":["ocean","mollusc"],"a/b":{},"count":1}`,
+		},
+		{
+			name:  "successful test",
+			patch: `[{"op":"test","path":"/tags/0","value":"ocean"},{"op":"remove","path":"/source"}]`,
+			want:  `{"fact":"Octopuses have three hearts.","tags":["ocean","mollusc"],"a/b":{"~c":1}}`,
+		},
+		{
+			name:    "failed test",
+			patch:   `[{"op":"remove","path":"/source"},{"op":"test","path":"/fact","value":"Octopuses have blue blood."}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:    "missing member",
+			patch:   `[{"op":"replace","path":"/animal","value":"octopus"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "index out of range",
+			patch:   `[{"op":"remove","path":"/tags/2"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "path without slash",
+			patch:   `[{"op":"remove","path":"source"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "add without value",
+			patch:   `[{"op":"add","path":"/animal"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "unknown op",
+			patch:   `[{"op":"increment","path":"/tags"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := Parse(MediaTypeJSONPatch, []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Parse() unexpected error = %v", err)
+			}
+
+			patched, err := patch.Apply([]byte(testDocument))
+			if tt.wantErr != nil {
+				var operationErr *OperationError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &operationErr) {
+					t.Errorf("Apply() error = %v, want operation error %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() unexpected error = %v", err)
+			}
+			assertJSON(t, patched, tt.want)
+		})
+	}
+}
+
+func TestJSONPatch_NullValue(t *testing.T) {
+	patch, err := Parse(MediaTypeJSONPatch, []byte(`[{"op":"replace","path":"/source","value":null}]`))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error = %v", err)
+	}
+
+	patched, err := patch.Apply([]byte(testDocument))
+	if err != nil {
+		t.Fatalf("Apply() unexpected error = %v", err)
+	}
+	assertJSON(t, patched, `{"fact":"Octopuses have three hearts.","source":null,"tags":["ocean","mollusc"],"a/b":{"~c":1}}`)
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse("application/json", []byte(`{}`)); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("Parse() error = %v, want ErrUnsupportedMediaType", err)
+	}
+	if _, err := Parse(MediaTypeJSONPatch, []byte(`{"op":"remove","path":"/fact"}`)); !errors.Is(err, ErrInvalidPatch) {
+		t.Errorf("Parse() error = %v, want ErrInvalidPatch for a single operation", err)
+	}
+}
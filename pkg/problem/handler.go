@@ -0,0 +1,115 @@
+package problem
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	logger "github.com/cafo13/animal-facts/pkg/log"
+	"github.com/cafo13/animal-facts/pkg/validation"
+)
+
+// UnexpectedDetail is the detail of unexpected errors, their cause is only logged as it can contain internals like
+// database errors.
+const UnexpectedDetail = "an unexpected error occurred, please report the correlation ID if the problem persists"
+
+const validationDetail = "the request is not valid, see the errors of its fields"
+
+// RenderFunc writes the problem details in another format than JSON, e.g. in the negotiated format of the request. It
+// returns false if it didn't write the problem details, they are written as JSON then.
+type RenderFunc func(c echo.Context, problem *Problem) (bool, error)
+
+// HTTPErrorHandler is the echo error handler responding with problem details. The details of an Error and of client
+// errors of echo, e.g. of routes that don't exist, are shown to the client. Other errors are logged with the correlation
+// ID of the request and the client gets a generic message.
+func HTTPErrorHandler(err error, c echo.Context) {
+	handleError(err, c, nil)
+}
+
+// NewHTTPErrorHandler returns the HTTPErrorHandler writing the problem details with render if it writes them.
+func NewHTTPErrorHandler(render RenderFunc) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		handleError(err, c, render)
+	}
+}
+
+func handleError(err error, c echo.Context, render RenderFunc) {
+	if c.Response().Committed {
+		return
+	}
+
+	problem, retryAfter, expected := toProblem(err)
+	problem.Instance = c.Request().URL.Path
+	problem.CorrelationID = CorrelationID(c)
+	if !expected {
+		logger.FromContext(c.Request().Context()).
+			WithError(err).
+			WithField("correlation_id", problem.CorrelationID).
+			Errorf("failed to handle %s %s", c.Request().Method, c.Request().URL.Path)
+	}
+
+	header := c.Response().Header()
+	if retryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+
+	var rendered bool
+	var writeErr error
+	if render != nil && c.Request().Method != http.MethodHead {
+		rendered, writeErr = render(c, problem)
+	}
+	if !rendered {
+		header.Set(echo.HeaderContentType, MediaType)
+		if c.Request().Method == http.MethodHead {
+			writeErr = c.NoContent(problem.Status)
+		} else {
+			writeErr = c.JSON(problem.Status, problem)
+		}
+	}
+	if writeErr != nil {
+		logger.FromContext(c.Request().Context()).WithError(writeErr).Error("failed to write problem details")
+	}
+}
+
+// StatusOf returns the status HTTPErrorHandler responds to the error with, e.g. to count the requests by their status
+// before the error is handled.
+func StatusOf(err error) int {
+	problem, _, _ := toProblem(err)
+	return problem.Status
+}
+
+// toProblem maps the error to problem details, expected is false for unexpected errors which need to be logged.
+func toProblem(err error) (problem *Problem, retryAfter time.Duration, expected bool) {
+	var problemErr *Error
+	if errors.As(err, &problemErr) {
+		return newProblem(problemErr.Status, problemErr.Code, problemErr.Detail), problemErr.RetryAfter, true
+	}
+
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		problem := newProblem(http.StatusUnprocessableEntity, CodeValidationFailed, validationDetail)
+		problem.Errors = validationErrs
+		return problem, 0, true
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code < http.StatusInternalServerError {
+		detail, _ := httpErr.Message.(string)
+		return newProblem(httpErr.Code, codeOfStatus(httpErr.Code), detail), 0, true
+	}
+
+	return newProblem(http.StatusInternalServerError, CodeInternal, UnexpectedDetail), 0, false
+}
+
+// CorrelationID returns the ID of the request in the logs, the X-Request-ID header of the response or the request.
+func CorrelationID(c echo.Context) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
@@ -0,0 +1,161 @@
+package problem
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cafo13/animal-facts/pkg/validation"
+)
+
+const (
+	// MediaType is the media type of problem details, see RFC 7807.
+	MediaType = "application/problem+json"
+
+	// typePrefix prefixes the codes to the URIs of the problem types, the codes are documented in the README
+	typePrefix = "urn:animal-facts:problem:"
+)
+
+// Code identifies the kind of problem for clients, codes don't change once they are released.
+type Code string
+
+const (
+	CodeBadRequest       Code = "bad_request"
+	CodeValidationFailed Code = "validation_failed"
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeMethodNotAllowed Code = "method_not_allowed"
+	CodeNotAcceptable    Code = "not_acceptable"
+	CodeConflict         Code = "conflict"
+	CodeUnsupportedMedia Code = "unsupported_media_type"
+	CodeRateLimited      Code = "rate_limited"
+	CodeInternal         Code = "internal_error"
+	CodeNotImplemented   Code = "not_implemented"
+	CodeUnavailable      Code = "service_unavailable"
+)
+
+// Problem are the problem details of an error response, see RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is the machine readable code of the problem, the last segment of the type.
+	Code Code `json:"code"`
+	// CorrelationID is the ID of the request in the logs, it is the X-Request-ID header of the response.
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Errors are the field errors of requests that failed the validation.
+	Errors validation.Errors `json:"errors,omitempty"`
+}
+
+// Error is an error of a request that is caused by the client or the state of a resource, its detail is shown to the
+// client. Other errors are unexpected, they are logged and the client gets a generic message.
+type Error struct {
+	Status int
+	Code   Code
+	Detail string
+	// RetryAfter is the time after which a rate limited request can be retried, it is set as Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+}
+
+func New(status int, code Code, detail string) *Error {
+	return &Error{Status: status, Code: code, Detail: detail}
+}
+
+// BadRequest is a malformed request, e.g. an invalid query parameter.
+func BadRequest(detail string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, detail)
+}
+
+// Validation is a well-formed request with invalid content, e.g. a fact without source.
+func Validation(detail string) *Error {
+	return New(http.StatusUnprocessableEntity, CodeValidationFailed, detail)
+}
+
+func Unauthorized(detail string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, detail)
+}
+
+func Forbidden(detail string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, detail)
+}
+
+func NotFound(detail string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, detail)
+}
+
+func NotAcceptable(detail string) *Error {
+	return New(http.StatusNotAcceptable, CodeNotAcceptable, detail)
+}
+
+// Conflict is a request that conflicts with the state of the resource, e.g. rotating a revoked api key.
+func Conflict(detail string) *Error {
+	return New(http.StatusConflict, CodeConflict, detail)
+}
+
+// UnsupportedMediaType is a request body of a media type the route doesn't accept, e.g. a patch that is plain JSON.
+func UnsupportedMediaType(detail string) *Error {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMedia, detail)
+}
+
+// RateLimited is a request over a rate limit or quota, it can be retried after retryAfter.
+func RateLimited(detail string, retryAfter time.Duration) *Error {
+	err := New(http.StatusTooManyRequests, CodeRateLimited, detail)
+	err.RetryAfter = retryAfter
+	return err
+}
+
+func NotImplemented(detail string) *Error {
+	return New(http.StatusNotImplemented, CodeNotImplemented, detail)
+}
+
+// codeOfStatus is the code of errors with a status but without code, e.g. of echo.
+func codeOfStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusNotAcceptable:
+		return CodeNotAcceptable
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMedia
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+func newProblem(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   typePrefix + string(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
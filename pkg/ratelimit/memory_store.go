@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+// memorySweepInterval is how often expired counters are deleted from a memory store
+const memorySweepInterval = time.Minute
+
+// MemoryStore keeps the counters in memory, so every replica has its own counters.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	counters  map[string]*repository.RateLimitCounter
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: map[string]*repository.RateLimitCounter{}, lastSweep: time.Now()}
+}
+
+func (m *MemoryStore) ReadCounter(key string) (*repository.RateLimitCounter, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	counter, exists := m.counters[key]
+	if !exists {
+		return &repository.RateLimitCounter{Key: key}, nil
+	}
+
+	counterCopy := *counter
+	return &counterCopy, nil
+}
+
+func (m *MemoryStore) UpdateCounter(key string, updateFunc func(counter *repository.RateLimitCounter)) (*repository.RateLimitCounter, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastSweep) >= memorySweepInterval {
+		for counterKey, counter := range m.counters {
+			if counter.ExpiresAt.Before(now) {
+				delete(m.counters, counterKey)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	counter, exists := m.counters[key]
+	if !exists {
+		counter = &repository.RateLimitCounter{Key: key}
+		m.counters[key] = counter
+	}
+	updateFunc(counter)
+	counter.Version++
+
+	counterCopy := *counter
+	return &counterCopy, nil
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neko-neko/echo-logrus/v2/log"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+)
+
+// the headers of the IETF draft "RateLimit header fields for HTTP"
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// KeyFunc returns the key the request is limited by.
+type KeyFunc func(c echo.Context) string
+
+// KeyByIP limits every client IP address on its own.
+func KeyByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// KeyByRoute limits all requests of a route together.
+func KeyByRoute(c echo.Context) string {
+	return fmt.Sprintf("route:%s %s", c.Request().Method, c.Path())
+}
+
+// CombineKeys limits every combination of the keys on its own, e.g. every IP address per route.
+func CombineKeys(keyFuncs ...KeyFunc) KeyFunc {
+	return func(c echo.Context) string {
+		keys := make([]string, 0, len(keyFuncs))
+		for _, keyFunc := range keyFuncs {
+			keys = append(keys, keyFunc(c))
+		}
+
+		return strings.Join(keys, "|")
+	}
+}
+
+// Middleware limits the requests by the key of the keyFunc, requests over the limit get 429 Too Many Requests. If the
+// store fails, requests are allowed, so that the API stays available.
+func (l *Limiter) Middleware(keyFunc KeyFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			result, err := l.Allow(keyFunc(c))
+			if err != nil {
+				log.Logger().WithError(err).Warn("failed to check rate limit, allowing request")
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+			header.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+			header.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", l.limit.Requests, ceilSeconds(l.limit.Period)))
+			if result.Allowed {
+				return next(c)
+			}
+
+			detail := fmt.Sprintf("rate limit exceeded, retry in %d seconds", ceilSeconds(result.RetryAfter))
+			return problem.RateLimited(detail, result.RetryAfter)
+		}
+	}
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
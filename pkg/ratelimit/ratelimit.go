@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+type Algorithm string
+
+const (
+	// AlgorithmTokenBucket allows bursts of up to Requests requests, the tokens are refilled evenly over the Period.
+	AlgorithmTokenBucket Algorithm = "token-bucket"
+	// AlgorithmSlidingWindow allows Requests requests in any Period, the requests of the previous window are weighted
+	// by their overlap with the sliding window.
+	AlgorithmSlidingWindow Algorithm = "sliding-window"
+)
+
+// ParseAlgorithm parses the name of an algorithm, e.g. token-bucket.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch algorithm := Algorithm(name); algorithm {
+	case AlgorithmTokenBucket, AlgorithmSlidingWindow:
+		return algorithm, nil
+	}
+
+	return "", fmt.Errorf("rate limit algorithm '%s' is not valid, valid algorithms are %s and %s", name, AlgorithmTokenBucket, AlgorithmSlidingWindow)
+}
+
+// Limit allows Requests requests per Period.
+type Limit struct {
+	Algorithm Algorithm
+	Requests  int
+	Period    time.Duration
+}
+
+// Store keeps the counters of the limited keys, it is implemented by NewMemoryStore for a single replica and by
+// repository.NewMongoDBRateLimitRepository for counters shared between replicas.
+type Store interface {
+	ReadCounter(key string) (*repository.RateLimitCounter, error)
+	UpdateCounter(key string, updateFunc func(counter *repository.RateLimitCounter)) (*repository.RateLimitCounter, error)
+}
+
+// Result is the decision about a request.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the limit is fully available again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, it is 0 if the request is allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter limits the requests of keys, e.g. of IP addresses.
+type Limiter struct {
+	name  string
+	store Store
+	limit Limit
+	now   func() time.Time
+}
+
+// NewLimiter creates a limiter, the name separates the counters of limiters that share a store.
+func NewLimiter(name string, store Store, limit Limit) *Limiter {
+	return &Limiter{name: name, store: store, limit: limit, now: time.Now}
+}
+
+// Allow takes a request of the key from its limit.
+func (l *Limiter) Allow(key string) (Result, error) {
+	now := l.now()
+	var result Result
+	_, err := l.store.UpdateCounter(l.name+":"+key, func(counter *repository.RateLimitCounter) {
+		result = l.decide(counter, now)
+	})
+	if err != nil {
+		return Result{}, errors.Wrapf(err, "failed to take request of '%s' from rate limit", key)
+	}
+
+	return result, nil
+}
+
+// Check returns the decision about a request of the key without taking the request from its limit.
+func (l *Limiter) Check(key string) (Result, error) {
+	counter, err := l.store.ReadCounter(l.name + ":" + key)
+	if err != nil {
+		return Result{}, errors.Wrapf(err, "failed to check rate limit of '%s'", key)
+	}
+
+	// the counter is a copy, so deciding about the request does not change the stored counter
+	return l.decide(counter, l.now()), nil
+}
+
+func (l *Limiter) decide(counter *repository.RateLimitCounter, now time.Time) Result {
+	switch l.limit.Algorithm {
+	case AlgorithmSlidingWindow:
+		return slidingWindow(l.limit, counter, now)
+	default:
+		return tokenBucket(l.limit, counter, now)
+	}
+}
+
+func tokenBucket(limit Limit, counter *repository.RateLimitCounter, now time.Time) Result {
+	requests := float64(limit.Requests)
+	tokensPerSecond := requests / limit.Period.Seconds()
+
+	tokens := requests
+	if !counter.Time.IsZero() {
+		tokens = math.Min(requests, counter.Value+now.Sub(counter.Time).Seconds()*tokensPerSecond)
+	}
+
+	result := Result{Limit: limit.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - tokens) / tokensPerSecond)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = secondsToDuration((requests - tokens) / tokensPerSecond)
+
+	counter.Value = tokens
+	counter.Time = now
+	counter.ExpiresAt = now.Add(result.Reset)
+	return result
+}
+
+func slidingWindow(limit Limit, counter *repository.RateLimitCounter, now time.Time) Result {
+	requests := float64(limit.Requests)
+	windowStart := now.Truncate(limit.Period)
+	if !counter.Time.Equal(windowStart) {
+		if counter.Time.Equal(windowStart.Add(-limit.Period)) {
+			counter.Previous = counter.Value
+		} else {
+			counter.Previous = 0
+		}
+		counter.Value = 0
+		counter.Time = windowStart
+	}
+
+	elapsed := now.Sub(windowStart)
+	previousWeight := 1 - elapsed.Seconds()/limit.Period.Seconds()
+	count := counter.Previous*previousWeight + counter.Value
+
+	result := Result{Limit: limit.Requests}
+	if count+1 <= requests {
+		counter.Value++
+		count++
+		result.Allowed = true
+	} else if counter.Value+1 <= requests {
+		// the weight of the previous window has to drop until the request fits
+		result.RetryAfter = time.Duration((1-(requests-1-counter.Value)/counter.Previous)*float64(limit.Period)) - elapsed
+	} else {
+		// the current window is full, so the request fits once it is the previous window and its weight dropped enough
+		result.RetryAfter = limit.Period - elapsed + time.Duration((1-(requests-1)/counter.Value)*float64(limit.Period))
+	}
+	result.Remaining = max(0, int(requests-count))
+
+	windowEnd := windowStart.Add(limit.Period)
+	if counter.Value > 0 {
+		// the requests of the current window count until the end of the next window
+		windowEnd = windowEnd.Add(limit.Period)
+	}
+	result.Reset = windowEnd.Sub(now)
+
+	counter.ExpiresAt = windowStart.Add(2 * limit.Period)
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func newTestLimiter(limit Limit) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)}
+	limiter := NewLimiter("test", NewMemoryStore(), limit)
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func allow(t *testing.T, limiter *Limiter, key string) Result {
+	t.Helper()
+
+	result, err := limiter.Allow(key)
+	if err != nil {
+		t.Fatalf("Allow() unexpected error = %v", err)
+	}
+	return result
+}
+
+func TestLimiter_TokenBucket(t *testing.T) {
+	limiter, clock := newTestLimiter(Limit{Algorithm: AlgorithmTokenBucket, Requests: 3, Period: 3 * time.Second})
+
+	for i := 2; i >= 0; i-- {
+		if result := allow(t, limiter, "a"); !result.Allowed || result.Remaining != i {
+			t.Fatalf("Allow() = %+v, want burst of 3 requests with %d remaining", result, i)
+		}
+	}
+	result := allow(t, limiter, "a")
+	if result.Allowed || result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+		t.Errorf("Allow() = %+v, want denied request with retry after a second and reset after 3 seconds", result)
+	}
+	if result := allow(t, limiter, "b"); !result.Allowed {
+		t.Errorf("Allow() = %+v, want other keys to have their own limit", result)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	if result := allow(t, limiter, "a"); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Allow() = %+v, want one refilled token after a second", result)
+	}
+}
+
+func TestLimiter_SlidingWindow(t *testing.T) {
+	limiter, clock := newTestLimiter(Limit{Algorithm: AlgorithmSlidingWindow, Requests: 4, Period: time.Minute})
+
+	for i := 0; i < 4; i++ {
+		if result := allow(t, limiter, "a"); !result.Allowed {
+			t.Fatalf("Allow() = %+v, want 4 requests in the window", result)
+		}
+	}
+	result := allow(t, limiter, "a")
+	if result.Allowed || result.Remaining != 0 || result.RetryAfter != 75*time.Second {
+		t.Errorf("Allow() = %+v, want denied request until a quarter of the next window passed", result)
+	}
+
+	// half of the next window passed, so half of the requests of the previous window count
+	clock.now = clock.now.Add(90 * time.Second)
+	for i := 0; i < 2; i++ {
+		if result := allow(t, limiter, "a"); !result.Allowed {
+			t.Fatalf("Allow() = %+v, want 2 requests in the half window", result)
+		}
+	}
+	result = allow(t, limiter, "a")
+	if result.Allowed || result.RetryAfter != 15*time.Second {
+		t.Errorf("Allow() = %+v, want denied request until the weight of the previous window dropped", result)
+	}
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	if result := allow(t, limiter, "a"); !result.Allowed || result.Remaining != 3 {
+		t.Errorf("Allow() = %+v, want full limit after two windows", result)
+	}
+}
+
+func TestLimiter_Check(t *testing.T) {
+	limiter, _ := newTestLimiter(Limit{Algorithm: AlgorithmTokenBucket, Requests: 1, Period: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if result, err := limiter.Check("a"); err != nil || !result.Allowed {
+			t.Fatalf("Check() = %+v, %v, want allowed request without taking it", result, err)
+		}
+	}
+	allow(t, limiter, "a")
+	if result, err := limiter.Check("a"); err != nil || result.Allowed || result.RetryAfter != time.Minute {
+		t.Errorf("Check() = %+v, %v, want denied request after the limit was taken", result, err)
+	}
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	limiter, _ := newTestLimiter(Limit{Algorithm: AlgorithmTokenBucket, Requests: 1, Period: time.Minute})
+	handler := limiter.Middleware(CombineKeys(KeyByRoute, KeyByIP))(func(c echo.Context) error {
+		return c.String(http.StatusOK, "fact")
+	})
+
+	serve := func(ip string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.RemoteAddr = ip + ":1234"
+		recorder := httptest.NewRecorder()
+		c := echo.New().NewContext(request, recorder)
+		if err := handler(c); err != nil {
+			problem.HTTPErrorHandler(err, c)
+		}
+		return recorder
+	}
+
+	recorder := serve("192.0.2.1")
+	if recorder.Code != http.StatusOK || recorder.Header().Get(HeaderRateLimitLimit) != "1" ||
+		recorder.Header().Get(HeaderRateLimitRemaining) != "0" || recorder.Header().Get(HeaderRateLimitReset) != "60" ||
+		recorder.Header().Get(HeaderRateLimitPolicy) != "1;w=60" {
+		t.Errorf("Middleware() = %d %v, want allowed request with rate limit headers", recorder.Code, recorder.Header())
+	}
+
+	recorder = serve("192.0.2.1")
+	if recorder.Code != http.StatusTooManyRequests || recorder.Header().Get(echo.HeaderRetryAfter) != "60" ||
+		recorder.Header().Get(HeaderRateLimitRemaining) != "0" ||
+		!strings.Contains(recorder.Body.String(), `"detail":"rate limit exceeded, retry in 60 seconds"`) {
+		t.Errorf("Middleware() = %d %v %s, want 429 with Retry-After", recorder.Code, recorder.Header(), recorder.Body)
+	}
+
+	if recorder = serve("192.0.2.2"); recorder.Code != http.StatusOK {
+		t.Errorf("Middleware() = %d, want other IP addresses to have their own limit", recorder.Code)
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	if algorithm, err := ParseAlgorithm("sliding-window"); err != nil || algorithm != AlgorithmSlidingWindow {
+		t.Errorf("ParseAlgorithm() = %v, %v, want sliding window", algorithm, err)
+	}
+	if _, err := ParseAlgorithm("leaky-bucket"); err == nil {
+		t.Errorf("ParseAlgorithm() want error for unknown algorithm")
+	}
+}
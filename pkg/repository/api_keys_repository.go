@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// APIKey identifies a partner using the public API. Only the hash of the key is stored, the key itself is only returned
+// when it is issued or rotated.
+type APIKey struct {
+	ID primitive.ObjectID `bson:"_id" json:"id"`
+	// Prefix is the start of the key, it identifies the key without revealing it.
+	Prefix      string   `bson:"prefix" json:"prefix"`
+	Hash        string   `bson:"hash" json:"-"`
+	Owner       string   `bson:"owner" json:"owner"`
+	Description string   `bson:"description" json:"description"`
+	Scopes      []string `bson:"scopes" json:"scopes"`
+	// DailyQuota is the number of requests per UTC day, 0 for no quota.
+	DailyQuota int       `bson:"daily_quota" json:"dailyQuota"`
+	Revoked    bool      `bson:"revoked" json:"revoked"`
+	RevokedAt  time.Time `bson:"revoked_at" json:"revokedAt"`
+	RotatedAt  time.Time `bson:"rotated_at" json:"rotatedAt"`
+	CreatedAt  time.Time `bson:"created_at" json:"createdAt"`
+	CreatedBy  string    `bson:"created_by" json:"createdBy"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updatedAt"`
+	UpdatedBy  string    `bson:"updated_by" json:"updatedBy"`
+}
+
+// APIKeyUsage is the number of requests of a key on a UTC day, the day has the format 2006-01-02.
+type APIKeyUsage struct {
+	ID       string             `bson:"_id" json:"-"`
+	KeyID    primitive.ObjectID `bson:"key_id" json:"keyId"`
+	Day      string             `bson:"day" json:"day"`
+	Requests int                `bson:"requests" json:"requests"`
+}
+
+func apiKeyUsageID(keyID primitive.ObjectID, day string) string {
+	return keyID.Hex() + ":" + day
+}
+
+type APIKeysRepository interface {
+	CreateKey(key *APIKey) error
+	ReadKey(id primitive.ObjectID) (*APIKey, error)
+	// ReadKeyByHash returns the key with the hash, revoked keys are returned as well.
+	ReadKeyByHash(hash string) (*APIKey, error)
+	ReadKeys(filterFunc func(key *APIKey) bool) ([]*APIKey, error)
+	UpdateKey(id primitive.ObjectID, updateFunc func(key *APIKey) *APIKey) error
+
+	// IncrementUsage counts a request of the key on the day and returns the number of requests of the key on the day.
+	IncrementUsage(keyID primitive.ObjectID, day string) (int, error)
+	// ReadUsage returns the usage of the key from the first to the last day, both included, with the oldest day first.
+	// Days without requests are missing.
+	ReadUsage(keyID primitive.ObjectID, firstDay string, lastDay string) ([]*APIKeyUsage, error)
+}
+
+type MongoDBAPIKeysRepository struct {
+	connection *MongoDBConnection
+}
+
+// NewMongoDBAPIKeysRepository creates the indexes of the keys and their usage if they don't exist yet: the hashes of
+// the keys are unique and read with every request with a key, the usage has one document per key and day.
+func NewMongoDBAPIKeysRepository(connection *MongoDBConnection) (APIKeysRepository, error) {
+	repository := &MongoDBAPIKeysRepository{connection}
+	_, err := repository.keysCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create hash index of api keys")
+	}
+	_, err = repository.usageCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "key_id", Value: 1}, {Key: "day", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create key and day index of api key usage")
+	}
+
+	return repository, nil
+}
+
+func (m *MongoDBAPIKeysRepository) keysCollection() *mongo.Collection {
+	return m.connection.collection("api_keys")
+}
+
+func (m *MongoDBAPIKeysRepository) usageCollection() *mongo.Collection {
+	return m.connection.collection("api_key_usage")
+}
+
+func (m *MongoDBAPIKeysRepository) CreateKey(key *APIKey) error {
+	_, err := m.keysCollection().InsertOne(context.TODO(), key)
+	return err
+}
+
+func (m *MongoDBAPIKeysRepository) readKey(filter bson.M) (*APIKey, error) {
+	var result APIKey
+	err := m.keysCollection().FindOne(context.TODO(), filter).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAPIKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (m *MongoDBAPIKeysRepository) ReadKey(id primitive.ObjectID) (*APIKey, error) {
+	return m.readKey(bson.M{"_id": id})
+}
+
+func (m *MongoDBAPIKeysRepository) ReadKeyByHash(hash string) (*APIKey, error) {
+	return m.readKey(bson.M{"hash": hash})
+}
+
+func (m *MongoDBAPIKeysRepository) ReadKeys(filterFunc func(key *APIKey) bool) ([]*APIKey, error) {
+	cursor, err := m.keysCollection().Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []APIKey
+	if err = cursor.All(context.TODO(), &keys); err != nil {
+		return nil, err
+	}
+
+	result := []*APIKey{}
+	for i := range keys {
+		if filterFunc(&keys[i]) {
+			result = append(result, &keys[i])
+		}
+	}
+
+	return result, nil
+}
+
+func (m *MongoDBAPIKeysRepository) UpdateKey(id primitive.ObjectID, updateFunc func(key *APIKey) *APIKey) error {
+	key, err := m.ReadKey(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.keysCollection().UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": updateFunc(key)})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update api key with ID '%v'", id)
+	}
+
+	return nil
+}
+
+func (m *MongoDBAPIKeysRepository) IncrementUsage(keyID primitive.ObjectID, day string) (int, error) {
+	filter := bson.M{"_id": apiKeyUsageID(keyID, day)}
+	update := bson.M{
+		"$inc":         bson.M{"requests": 1},
+		"$setOnInsert": bson.M{"key_id": keyID, "day": day},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result APIKeyUsage
+	err := m.usageCollection().FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to increment usage of api key with ID '%v'", keyID)
+	}
+
+	return result.Requests, nil
+}
+
+func (m *MongoDBAPIKeysRepository) ReadUsage(keyID primitive.ObjectID, firstDay string, lastDay string) ([]*APIKeyUsage, error) {
+	filter := bson.M{"key_id": keyID, "day": bson.M{"$gte": firstDay, "$lte": lastDay}}
+	cursor, err := m.usageCollection().Find(context.TODO(), filter, options.Find().SetSort(bson.M{"day": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*APIKeyUsage{}
+	if err = cursor.All(context.TODO(), &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MockAPIKeysRepository is safe for concurrent use, as keys are used by concurrent requests.
+type MockAPIKeysRepository struct {
+	mutex                 sync.Mutex
+	keys                  map[primitive.ObjectID]*APIKey
+	usage                 map[string]*APIKeyUsage
+	errorAllFunctionCalls bool
+}
+
+func NewMockAPIKeysRepository(keys map[primitive.ObjectID]*APIKey, errorAllFunctionCalls bool) APIKeysRepository {
+	return &MockAPIKeysRepository{
+		keys:                  keys,
+		usage:                 map[string]*APIKeyUsage{},
+		errorAllFunctionCalls: errorAllFunctionCalls,
+	}
+}
+
+func (m *MockAPIKeysRepository) CreateKey(key *APIKey) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at creating api key")
+	}
+
+	keyCopy := *key
+	m.keys[key.ID] = &keyCopy
+	return nil
+}
+
+func (m *MockAPIKeysRepository) ReadKey(id primitive.ObjectID) (*APIKey, error) {
+	return m.readKey(func(key *APIKey) bool {
+		return key.ID == id
+	})
+}
+
+func (m *MockAPIKeysRepository) ReadKeyByHash(hash string) (*APIKey, error) {
+	return m.readKey(func(key *APIKey) bool {
+		return key.Hash == hash
+	})
+}
+
+func (m *MockAPIKeysRepository) readKey(matches func(key *APIKey) bool) (*APIKey, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting api key")
+	}
+
+	for _, key := range m.keys {
+		if matches(key) {
+			keyCopy := *key
+			keyCopy.Scopes = slices.Clone(key.Scopes)
+			return &keyCopy, nil
+		}
+	}
+
+	return nil, ErrAPIKeyNotFound
+}
+
+func (m *MockAPIKeysRepository) ReadKeys(filterFunc func(key *APIKey) bool) ([]*APIKey, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting api keys")
+	}
+
+	result := []*APIKey{}
+	for _, key := range m.keys {
+		keyCopy := *key
+		keyCopy.Scopes = slices.Clone(key.Scopes)
+		if filterFunc(&keyCopy) {
+			result = append(result, &keyCopy)
+		}
+	}
+	slices.SortFunc(result, func(a, b *APIKey) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (m *MockAPIKeysRepository) UpdateKey(id primitive.ObjectID, updateFunc func(key *APIKey) *APIKey) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at updating api key")
+	}
+
+	key, exists := m.keys[id]
+	if !exists {
+		return ErrAPIKeyNotFound
+	}
+
+	keyToUpdate := *key
+	keyToUpdate.Scopes = slices.Clone(key.Scopes)
+	m.keys[id] = updateFunc(&keyToUpdate)
+	return nil
+}
+
+func (m *MockAPIKeysRepository) IncrementUsage(keyID primitive.ObjectID, day string) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return 0, errors.New("error at incrementing api key usage")
+	}
+
+	id := apiKeyUsageID(keyID, day)
+	usage, exists := m.usage[id]
+	if !exists {
+		usage = &APIKeyUsage{ID: id, KeyID: keyID, Day: day}
+		m.usage[id] = usage
+	}
+	usage.Requests++
+
+	return usage.Requests, nil
+}
+
+func (m *MockAPIKeysRepository) ReadUsage(keyID primitive.ObjectID, firstDay string, lastDay string) ([]*APIKeyUsage, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting api key usage")
+	}
+
+	result := []*APIKeyUsage{}
+	for _, usage := range m.usage {
+		if usage.KeyID == keyID && usage.Day >= firstDay && usage.Day <= lastDay {
+			usageCopy := *usage
+			result = append(result, &usageCopy)
+		}
+	}
+	slices.SortFunc(result, func(a, b *APIKeyUsage) int {
+		return strings.Compare(a.Day, b.Day)
+	})
+
+	return result, nil
+}
@@ -1,17 +1,29 @@
 package repository
 
 import (
+	"bytes"
 	"context"
-	"os"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
-	"github.com/neko-neko/echo-logrus/v2/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+)
+
+const (
+	// factRemovalsCollectionName is the collection of the time approved facts were last removed, in one document
+	factRemovalsCollectionName = "fact_removals"
+	approvedFactsRemovalID     = "approved"
+
+	// DefaultLanguage is the language of facts created without one. Facts stored before facts had a language have
+	// no language field and are in the default language.
+	DefaultLanguage = "en"
 )
 
 var (
@@ -19,72 +31,220 @@ var (
 )
 
 type Fact struct {
-	ID        primitive.ObjectID `bson:"_id" json:"id"`
-	Fact      string             `bson:"fact" json:"fact"`
-	Source    string             `bson:"source" json:"source"`
-	Approved  bool               `bson:"approved" json:"approved"`
-	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
-	CreatedBy string             `bson:"created_by" json:"createdBy"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updatedAt"`
-	UpdatedBy string             `bson:"updated_by" json:"updatedBy"`
+	ID       primitive.ObjectID `bson:"_id" json:"id"`
+	Fact     string             `bson:"fact" json:"fact"`
+	Source   string             `bson:"source" json:"source"`
+	Animal   string             `bson:"animal" json:"animal"`
+	Tags     []string           `bson:"tags" json:"tags"`
+	Language string             `bson:"language" json:"language"`
+	Approved bool               `bson:"approved" json:"approved"`
+	// ApprovedAt is the time the fact was approved the last time, it is zero for facts that are not approved.
+	ApprovedAt time.Time `bson:"approved_at" json:"approvedAt"`
+	Flagged    bool      `bson:"flagged" json:"flagged"`
+	// Revision is incremented on every update of the fact.
+	Revision  int       `bson:"revision" json:"revision"`
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	CreatedBy string    `bson:"created_by" json:"createdBy"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+	UpdatedBy string    `bson:"updated_by" json:"updatedBy"`
+}
+
+// LanguageOrDefault returns the language of the fact, DefaultLanguage for facts stored without a language.
+func (f *Fact) LanguageOrDefault() string {
+	if f.Language == "" {
+		return DefaultLanguage
+	}
+
+	return f.Language
+}
+
+// languageCondition matches the facts in the language, facts without a language field match the default language.
+func languageCondition(language string) bson.M {
+	if language == DefaultLanguage {
+		return bson.M{"language": bson.M{"$in": bson.A{DefaultLanguage, "", nil}}}
+	}
+
+	return bson.M{"language": language}
+}
+
+// FactsPageQuery selects a page of approved facts ordered by creation time, filters with empty values are ignored.
+type FactsPageQuery struct {
+	Animal         string
+	Tag            string
+	Language       string
+	CreatedAfter   *time.Time
+	SortDescending bool
+	// After is the position of the last fact of the previous page, the page starts right after it.
+	After *FactsPagePosition
+	Limit int
+}
+
+// RecentlyApprovedQuery selects the most recently approved facts, filters with empty values are ignored.
+type RecentlyApprovedQuery struct {
+	Animal string
+	Tag    string
+	Limit  int
+}
+
+func (q RecentlyApprovedQuery) matches(fact *Fact) bool {
+	return fact.Approved &&
+		(q.Animal == "" || fact.Animal == q.Animal) &&
+		(q.Tag == "" || slices.Contains(fact.Tags, q.Tag))
+}
+
+// RelatedFactsQuery selects the approved facts about one of the animals or with one of the tags.
+type RelatedFactsQuery struct {
+	Animals []string
+	Tags    []string
+}
+
+func (q RelatedFactsQuery) matches(fact *Fact) bool {
+	if !fact.Approved {
+		return false
+	}
+	if fact.Animal != "" && slices.Contains(q.Animals, fact.Animal) {
+		return true
+	}
+	return slices.ContainsFunc(fact.Tags, func(tag string) bool {
+		return slices.Contains(q.Tags, tag)
+	})
+}
+
+type FactsPagePosition struct {
+	CreatedAt time.Time
+	ID        primitive.ObjectID
+}
+
+func (q FactsPageQuery) matches(fact *Fact) bool {
+	if !fact.Approved ||
+		(q.Animal != "" && fact.Animal != q.Animal) ||
+		(q.Tag != "" && !slices.Contains(fact.Tags, q.Tag)) ||
+		(q.Language != "" && fact.LanguageOrDefault() != q.Language) ||
+		(q.CreatedAfter != nil && !fact.CreatedAt.After(*q.CreatedAfter)) {
+		return false
+	}
+
+	if q.After != nil {
+		comparison := comparePagePosition(fact, q.After)
+		if (!q.SortDescending && comparison <= 0) || (q.SortDescending && comparison >= 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func comparePagePosition(fact *Fact, position *FactsPagePosition) int {
+	if c := fact.CreatedAt.Compare(position.CreatedAt); c != 0 {
+		return c
+	}
+
+	return bytes.Compare(fact.ID[:], position.ID[:])
 }
 
 type FactsRepository interface {
 	Create(fact *Fact) error
 	ReadOne(id primitive.ObjectID) (*Fact, error)
+	// ReadMany returns the approved facts with the given IDs in the order of the IDs, IDs of facts that don't exist or
+	// aren't approved are skipped.
+	ReadMany(ids []primitive.ObjectID) ([]*Fact, error)
 	ReadManyIDs(filterFunc func(fact *Fact) bool) ([]primitive.ObjectID, error)
 	ReadAll() ([]*Fact, error)
-	Update(id primitive.ObjectID, updateFunc func(fact *Fact) *Fact) error
+	ReadPage(query FactsPageQuery) ([]*Fact, error)
+	// ReadRecentlyApproved returns approved facts ordered by their approval time, the most recently approved first.
+	ReadRecentlyApproved(query RecentlyApprovedQuery) ([]*Fact, error)
+	// ReadRelated returns the approved facts about one of the animals or with one of the tags of the query.
+	ReadRelated(query RelatedFactsQuery) ([]*Fact, error)
+	// ReadLastRemoval returns the time an approved fact was last unapproved, deleted or changed, as it can have left
+	// a filtered list of approved facts with that. It is zero if no approved fact was removed yet.
+	ReadLastRemoval() (time.Time, error)
+	// Update reads the fact, changes it with the update function and writes it in one transaction. If the update
+	// function returns an error, the fact is not changed and the error is returned as it is.
+	Update(id primitive.ObjectID, updateFunc func(fact *Fact) (*Fact, error)) error
 	Delete(id primitive.ObjectID) error
 	Count() (int, error)
+	// Ping checks that the database of the repository is reachable, it is the health check of the repository.
+	Ping(ctx context.Context) error
+	// WithContext returns the repository running its operations with the context, e.g. to trace them as part of a
+	// request. The repository itself keeps its context.
+	WithContext(ctx context.Context) FactsRepository
 	Close() error
 }
 
+// MongoDBFactsRepository appends an event to the outbox in the same transaction as every write, see OutboxRepository.
 type MongoDBFactsRepository struct {
-	mongoDbClient *mongo.Client
-	databaseName  string
+	connection *MongoDBConnection
+	ctx        context.Context
 }
 
 func NewMongoDBFactsRepository(mongoDbUri string) (FactsRepository, error) {
-	opts := options.Client().ApplyURI(mongoDbUri).SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1))
-	client, err := mongo.Connect(context.TODO(), opts)
+	connection, err := NewMongoDBConnection(mongoDbUri, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	databaseName := "animal-facts"
-
-	databaseNameFromEnv, exists := os.LookupEnv("MONGODB_DATABASE_NAME")
-	if exists {
-		databaseName = databaseNameFromEnv
-	}
+	return NewMongoDBFactsRepositoryFromConnection(connection), nil
+}
 
-	log.Logger().Info("using database: " + databaseName)
-	if err := client.Database(databaseName).RunCommand(context.TODO(), bson.D{{"ping", 1}}).Err(); err != nil {
-		return nil, errors.Wrap(err, "failed to ping mongo db")
-	}
-	log.Logger().Info("connected to mongo db")
+func NewMongoDBFactsRepositoryFromConnection(connection *MongoDBConnection) FactsRepository {
+	return &MongoDBFactsRepository{connection, context.TODO()}
+}
 
-	return &MongoDBFactsRepository{client, databaseName}, nil
+func (m *MongoDBFactsRepository) WithContext(ctx context.Context) FactsRepository {
+	return &MongoDBFactsRepository{m.connection, ctx}
 }
 
 func (m *MongoDBFactsRepository) factsCollection() *mongo.Collection {
-	return m.mongoDbClient.Database(m.databaseName).Collection("facts")
+	return m.connection.collection("facts")
 }
 
-func (m *MongoDBFactsRepository) Create(fact *Fact) error {
-	_, err := m.factsCollection().InsertOne(context.TODO(), fact)
+func (m *MongoDBFactsRepository) appendOutboxEvent(ctx mongo.SessionContext, eventType events.Type, id primitive.ObjectID, fact *Fact) error {
+	var data interface{}
+	if fact != nil {
+		data = fact
+	}
+	event, err := newOutboxEvent(eventType, id, data)
 	if err != nil {
 		return err
 	}
 
+	_, err = m.connection.collection(outboxCollectionName).InsertOne(ctx, event)
+	if err != nil {
+		return errors.Wrapf(err, "failed to append %s event of fact with ID '%v' to outbox", eventType, id)
+	}
+
+	return nil
+}
+
+// recordRemoval moves the time of the last removal of an approved fact forward, see ReadLastRemoval.
+func (m *MongoDBFactsRepository) recordRemoval(ctx mongo.SessionContext, removedAt time.Time) error {
+	_, err := m.connection.collection(factRemovalsCollectionName).UpdateOne(ctx,
+		bson.M{"_id": approvedFactsRemovalID},
+		bson.M{"$max": bson.M{"removed_at": removedAt}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to record removal of approved fact")
+	}
+
 	return nil
 }
 
+func (m *MongoDBFactsRepository) Create(fact *Fact) error {
+	return m.connection.withTransaction(m.ctx, func(ctx mongo.SessionContext) error {
+		_, err := m.factsCollection().InsertOne(ctx, fact)
+		if err != nil {
+			return err
+		}
+
+		return m.appendOutboxEvent(ctx, events.TypeFactCreated, fact.ID, fact)
+	})
+}
+
 func (m *MongoDBFactsRepository) ReadOne(id primitive.ObjectID) (*Fact, error) {
 	filter := bson.D{{"_id", id}, {"approved", true}}
 	var result Fact
-	err := m.factsCollection().FindOne(context.TODO(), filter).Decode(&result)
+	err := m.factsCollection().FindOne(m.ctx, filter).Decode(&result)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, ErrNotFound
 	} else if err != nil {
@@ -94,15 +254,42 @@ func (m *MongoDBFactsRepository) ReadOne(id primitive.ObjectID) (*Fact, error) {
 	return &result, nil
 }
 
+func (m *MongoDBFactsRepository) ReadMany(ids []primitive.ObjectID) ([]*Fact, error) {
+	filter := bson.M{"_id": bson.M{"$in": ids}, "approved": true}
+	cursor, err := m.factsCollection().Find(m.ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var facts []Fact
+	if err = cursor.All(m.ctx, &facts); err != nil {
+		return nil, err
+	}
+
+	factsByID := make(map[primitive.ObjectID]*Fact, len(facts))
+	for i := range facts {
+		factsByID[facts[i].ID] = &facts[i]
+	}
+
+	result := []*Fact{}
+	for _, id := range ids {
+		if fact, exists := factsByID[id]; exists {
+			result = append(result, fact)
+		}
+	}
+
+	return result, nil
+}
+
 func (m *MongoDBFactsRepository) ReadManyIDs(filterFunc func(fact *Fact) bool) ([]primitive.ObjectID, error) {
 	filter := bson.D{{"approved", true}}
 	var facts []Fact
-	cursor, err := m.factsCollection().Find(context.TODO(), filter)
+	cursor, err := m.factsCollection().Find(m.ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = cursor.All(context.TODO(), &facts); err != nil {
+	if err = cursor.All(m.ctx, &facts); err != nil {
 		return nil, err
 	}
 
@@ -116,47 +303,180 @@ func (m *MongoDBFactsRepository) ReadManyIDs(filterFunc func(fact *Fact) bool) (
 	return result, nil
 }
 
-func (m *MongoDBFactsRepository) Update(id primitive.ObjectID, updateFunc func(fact *Fact) *Fact) error {
-	filter := bson.D{{"_id", id}}
-	var readResult Fact
-	err := m.factsCollection().FindOne(context.TODO(), filter).Decode(&readResult)
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		return ErrNotFound
-	} else if err != nil {
-		return errors.Wrapf(err, "failed to get fact with ID '%v' before updating", id)
+func (m *MongoDBFactsRepository) ReadPage(query FactsPageQuery) ([]*Fact, error) {
+	conditions := bson.A{bson.M{"approved": true}}
+	if query.Animal != "" {
+		conditions = append(conditions, bson.M{"animal": query.Animal})
+	}
+	if query.Tag != "" {
+		conditions = append(conditions, bson.M{"tags": query.Tag})
+	}
+	if query.Language != "" {
+		conditions = append(conditions, languageCondition(query.Language))
+	}
+	if query.CreatedAfter != nil {
+		conditions = append(conditions, bson.M{"created_at": bson.M{"$gt": *query.CreatedAfter}})
 	}
 
-	updatedFact := updateFunc(&readResult)
-	update := bson.D{{"$set", updatedFact}}
-	_, err = m.factsCollection().UpdateOne(context.TODO(), filter, update)
+	sortDirection, afterOperator := 1, "$gt"
+	if query.SortDescending {
+		sortDirection, afterOperator = -1, "$lt"
+	}
+	if query.After != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{afterOperator: query.After.CreatedAt}},
+			bson.M{"created_at": query.After.CreatedAt, "_id": bson.M{afterOperator: query.After.ID}},
+		}})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: sortDirection}, {Key: "_id", Value: sortDirection}}).
+		SetLimit(int64(query.Limit))
+	cursor, err := m.factsCollection().Find(m.ctx, bson.M{"$and": conditions}, opts)
 	if err != nil {
-		return errors.Wrapf(err, "failed to update fact with ID '%v'", id)
+		return nil, err
 	}
 
-	return nil
+	result := []*Fact{}
+	if err = cursor.All(m.ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
-func (m *MongoDBFactsRepository) Delete(id primitive.ObjectID) error {
-	filter := bson.D{{"_id", id}}
-	_, err := m.factsCollection().DeleteOne(context.TODO(), filter)
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		return ErrNotFound
-	} else if err != nil {
-		return errors.Wrapf(err, "failed to delete fact with ID '%v'", id)
+func (m *MongoDBFactsRepository) ReadRecentlyApproved(query RecentlyApprovedQuery) ([]*Fact, error) {
+	filter := bson.M{"approved": true}
+	if query.Animal != "" {
+		filter["animal"] = query.Animal
+	}
+	if query.Tag != "" {
+		filter["tags"] = query.Tag
 	}
 
-	return nil
+	opts := options.Find().
+		SetSort(bson.D{{Key: "approved_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(query.Limit))
+	cursor, err := m.factsCollection().Find(m.ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Fact{}
+	if err = cursor.All(m.ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (m *MongoDBFactsRepository) ReadRelated(query RelatedFactsQuery) ([]*Fact, error) {
+	var conditions bson.A
+	animals := slices.DeleteFunc(slices.Clone(query.Animals), func(animal string) bool { return animal == "" })
+	if len(animals) > 0 {
+		conditions = append(conditions, bson.M{"animal": bson.M{"$in": animals}})
+	}
+	if len(query.Tags) > 0 {
+		conditions = append(conditions, bson.M{"tags": bson.M{"$in": query.Tags}})
+	}
+	if len(conditions) == 0 {
+		return []*Fact{}, nil
+	}
+
+	cursor, err := m.factsCollection().Find(m.ctx, bson.M{"approved": true, "$or": conditions})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*Fact{}
+	if err = cursor.All(m.ctx, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (m *MongoDBFactsRepository) ReadLastRemoval() (time.Time, error) {
+	var removal struct {
+		RemovedAt time.Time `bson:"removed_at"`
+	}
+	err := m.connection.collection(factRemovalsCollectionName).FindOne(m.ctx, bson.M{"_id": approvedFactsRemovalID}).Decode(&removal)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, errors.Wrap(err, "failed to read last removal of approved facts")
+	}
+
+	return removal.RemovedAt, nil
+}
+
+// Update runs in a transaction, updateFunc is called again if the transaction is retried after a conflicting write.
+func (m *MongoDBFactsRepository) Update(id primitive.ObjectID, updateFunc func(fact *Fact) (*Fact, error)) error {
+	return m.connection.withTransaction(m.ctx, func(ctx mongo.SessionContext) error {
+		filter := bson.D{{"_id", id}}
+		var readResult Fact
+		err := m.factsCollection().FindOne(ctx, filter).Decode(&readResult)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrNotFound
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to get fact with ID '%v' before updating", id)
+		}
+
+		// updateFunc can change the read fact in place, so the fact before the update is a copy
+		fact := readResult
+		fact.Tags = slices.Clone(readResult.Tags)
+		updatedFact, err := updateFunc(&readResult)
+		if err != nil {
+			return err
+		}
+		updatedFact.Revision++
+		update := bson.D{{"$set", updatedFact}}
+		_, err = m.factsCollection().UpdateOne(ctx, filter, update)
+		if err != nil {
+			return errors.Wrapf(err, "failed to update fact with ID '%v'", id)
+		}
+
+		eventType, changed := factUpdateEventType(&fact, updatedFact)
+		if !changed {
+			return nil
+		}
+		if fact.Approved && eventType != events.TypeFactApproved {
+			if err := m.recordRemoval(ctx, time.Now().UTC()); err != nil {
+				return err
+			}
+		}
+		return m.appendOutboxEvent(ctx, eventType, id, updatedFact)
+	})
+}
+
+func (m *MongoDBFactsRepository) Delete(id primitive.ObjectID) error {
+	return m.connection.withTransaction(m.ctx, func(ctx mongo.SessionContext) error {
+		filter := bson.D{{"_id", id}}
+		result, err := m.factsCollection().DeleteOne(ctx, filter)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrNotFound
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to delete fact with ID '%v'", id)
+		}
+		if result.DeletedCount == 0 {
+			return nil
+		}
+
+		// the deleted fact is not read first, so every deletion counts as removal of an approved fact
+		if err := m.recordRemoval(ctx, time.Now().UTC()); err != nil {
+			return err
+		}
+		return m.appendOutboxEvent(ctx, events.TypeFactDeleted, id, nil)
+	})
 }
 
 func (m *MongoDBFactsRepository) Count() (int, error) {
 	filter := bson.D{{"approved", true}}
 	var facts []Fact
-	cursor, err := m.factsCollection().Find(context.TODO(), filter)
+	cursor, err := m.factsCollection().Find(m.ctx, filter)
 	if err != nil {
 		return 0, err
 	}
 
-	if err = cursor.All(context.TODO(), &facts); err != nil {
+	if err = cursor.All(m.ctx, &facts); err != nil {
 		return 0, err
 	}
 
@@ -165,12 +485,12 @@ func (m *MongoDBFactsRepository) Count() (int, error) {
 
 func (m *MongoDBFactsRepository) ReadAll() ([]*Fact, error) {
 	var facts []Fact
-	cursor, err := m.factsCollection().Find(context.TODO(), bson.D{{}})
+	cursor, err := m.factsCollection().Find(m.ctx, bson.D{{}})
 	if err != nil {
 		return nil, err
 	}
 
-	if err = cursor.All(context.TODO(), &facts); err != nil {
+	if err = cursor.All(m.ctx, &facts); err != nil {
 		return nil, err
 	}
 
@@ -182,22 +502,57 @@ func (m *MongoDBFactsRepository) ReadAll() ([]*Fact, error) {
 	return result, nil
 }
 
-func (m *MongoDBFactsRepository) Close() error {
-	if err := m.mongoDbClient.Disconnect(context.TODO()); err != nil {
-		log.Logger().WithError(err).Fatal("failed to disconnect from mongo db")
-		return err
-	}
+func (m *MongoDBFactsRepository) Ping(ctx context.Context) error {
+	return m.connection.Ping(ctx)
+}
 
-	return nil
+func (m *MongoDBFactsRepository) Close() error {
+	return m.connection.Close()
 }
 
 type MockFactsRepository struct {
 	facts                 map[primitive.ObjectID]*Fact
+	outboxRepository      OutboxRepository
+	lastRemoval           time.Time
 	errorAllFunctionCalls bool
 }
 
 func NewMockFactsRepository(facts map[primitive.ObjectID]*Fact, errorAllFunctionCalls bool) FactsRepository {
-	return &MockFactsRepository{facts, errorAllFunctionCalls}
+	return &MockFactsRepository{facts: facts, errorAllFunctionCalls: errorAllFunctionCalls}
+}
+
+// NewMockFactsRepositoryWithOutbox returns a mock that appends the events of its writes to the outbox repository.
+func NewMockFactsRepositoryWithOutbox(facts map[primitive.ObjectID]*Fact, outboxRepository OutboxRepository, errorAllFunctionCalls bool) FactsRepository {
+	return &MockFactsRepository{facts: facts, outboxRepository: outboxRepository, errorAllFunctionCalls: errorAllFunctionCalls}
+}
+
+func (m *MockFactsRepository) appendOutboxEvent(eventType events.Type, id primitive.ObjectID, fact *Fact) error {
+	if m.outboxRepository == nil {
+		return nil
+	}
+
+	var data interface{}
+	if fact != nil {
+		data = fact
+	}
+	event, err := newOutboxEvent(eventType, id, data)
+	if err != nil {
+		return err
+	}
+
+	return m.outboxRepository.Append(event)
+}
+
+func (m *MockFactsRepository) Ping(ctx context.Context) error {
+	if m.errorAllFunctionCalls {
+		return errors.New("error at pinging database")
+	}
+
+	return nil
+}
+
+func (m *MockFactsRepository) WithContext(ctx context.Context) FactsRepository {
+	return m
 }
 
 func (m *MockFactsRepository) Create(fact *Fact) error {
@@ -205,7 +560,7 @@ func (m *MockFactsRepository) Create(fact *Fact) error {
 		return errors.New("error at creating fact")
 	}
 
-	return nil
+	return m.appendOutboxEvent(events.TypeFactCreated, fact.ID, fact)
 }
 
 func (m *MockFactsRepository) ReadOne(id primitive.ObjectID) (*Fact, error) {
@@ -217,7 +572,22 @@ func (m *MockFactsRepository) ReadOne(id primitive.ObjectID) (*Fact, error) {
 		return fact, nil
 	}
 
-	return nil, errors.New("fact not found")
+	return nil, ErrNotFound
+}
+
+func (m *MockFactsRepository) ReadMany(ids []primitive.ObjectID) ([]*Fact, error) {
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting facts")
+	}
+
+	result := []*Fact{}
+	for _, id := range ids {
+		if fact, exists := m.facts[id]; exists {
+			result = append(result, fact)
+		}
+	}
+
+	return result, nil
 }
 
 func (m *MockFactsRepository) ReadManyIDs(filterFunc func(fact *Fact) bool) ([]primitive.ObjectID, error) {
@@ -235,11 +605,107 @@ func (m *MockFactsRepository) ReadManyIDs(filterFunc func(fact *Fact) bool) ([]p
 	return matchingFactIDs, nil
 }
 
-func (m *MockFactsRepository) Update(id primitive.ObjectID, updateFunc func(fact *Fact) *Fact) error {
+func (m *MockFactsRepository) ReadPage(query FactsPageQuery) ([]*Fact, error) {
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting page of facts")
+	}
+
+	result := []*Fact{}
+	for _, fact := range m.facts {
+		if query.matches(fact) {
+			result = append(result, fact)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *Fact) int {
+		comparison := comparePagePosition(a, &FactsPagePosition{CreatedAt: b.CreatedAt, ID: b.ID})
+		if query.SortDescending {
+			return -comparison
+		}
+		return comparison
+	})
+
+	if len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+
+	return result, nil
+}
+
+func (m *MockFactsRepository) ReadRecentlyApproved(query RecentlyApprovedQuery) ([]*Fact, error) {
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting recently approved facts")
+	}
+
+	result := []*Fact{}
+	for _, fact := range m.facts {
+		if query.matches(fact) {
+			result = append(result, fact)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *Fact) int {
+		if c := b.ApprovedAt.Compare(a.ApprovedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(b.ID[:], a.ID[:])
+	})
+
+	if len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+
+	return result, nil
+}
+
+func (m *MockFactsRepository) ReadRelated(query RelatedFactsQuery) ([]*Fact, error) {
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting related facts")
+	}
+
+	result := []*Fact{}
+	for _, fact := range m.facts {
+		if query.matches(fact) {
+			result = append(result, fact)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *MockFactsRepository) ReadLastRemoval() (time.Time, error) {
+	if m.errorAllFunctionCalls {
+		return time.Time{}, errors.New("error at getting last removal of approved facts")
+	}
+
+	return m.lastRemoval, nil
+}
+
+func (m *MockFactsRepository) Update(id primitive.ObjectID, updateFunc func(fact *Fact) (*Fact, error)) error {
 	if m.errorAllFunctionCalls {
 		return errors.New("error at updating fact")
 	}
 
+	if fact, exists := m.facts[id]; exists {
+		factToUpdate := *fact
+		factToUpdate.Tags = slices.Clone(fact.Tags)
+		updatedFact, err := updateFunc(&factToUpdate)
+		if err != nil {
+			return err
+		}
+		updatedFact.Revision++
+		m.facts[id] = updatedFact
+
+		eventType, changed := factUpdateEventType(fact, updatedFact)
+		if !changed {
+			return nil
+		}
+		if fact.Approved && eventType != events.TypeFactApproved {
+			m.lastRemoval = time.Now().UTC()
+		}
+		return m.appendOutboxEvent(eventType, id, updatedFact)
+	}
+
 	return nil
 }
 
@@ -248,6 +714,11 @@ func (m *MockFactsRepository) Delete(id primitive.ObjectID) error {
 		return errors.New("error at deleting fact")
 	}
 
+	if _, exists := m.facts[id]; exists {
+		m.lastRemoval = time.Now().UTC()
+		return m.appendOutboxEvent(events.TypeFactDeleted, id, nil)
+	}
+
 	return nil
 }
 
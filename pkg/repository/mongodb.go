@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"os"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// MongoDBConnection is a connection to the mongo db database that can be shared between the mongo db repositories.
+type MongoDBConnection struct {
+	mongoDbClient *mongo.Client
+	databaseName  string
+}
+
+// NewMongoDBConnection connects to the database, the events of the connection pools are sent to the poolMonitor and
+// the commands to the commandMonitor unless they are nil.
+func NewMongoDBConnection(mongoDbUri string, poolMonitor *event.PoolMonitor, commandMonitor *event.CommandMonitor) (*MongoDBConnection, error) {
+	opts := options.Client().ApplyURI(mongoDbUri).SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1))
+	if poolMonitor != nil {
+		opts.SetPoolMonitor(poolMonitor)
+	}
+	if commandMonitor != nil {
+		opts.SetMonitor(commandMonitor)
+	}
+	client, err := mongo.Connect(context.TODO(), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	databaseName := "animal-facts"
+
+	databaseNameFromEnv, exists := os.LookupEnv("MONGODB_DATABASE_NAME")
+	if exists {
+		databaseName = databaseNameFromEnv
+	}
+
+	log.Logger().Info("using database: " + databaseName)
+	if err := client.Database(databaseName).RunCommand(context.TODO(), bson.D{{"ping", 1}}).Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to ping mongo db")
+	}
+	log.Logger().Info("connected to mongo db")
+
+	return &MongoDBConnection{client, databaseName}, nil
+}
+
+func (m *MongoDBConnection) collection(name string) *mongo.Collection {
+	return m.mongoDbClient.Database(m.databaseName).Collection(name)
+}
+
+// withTransaction runs fn in a transaction that is retried on transient errors, so fn can be called more than once.
+// Transactions need a replica set, a single node replica set is enough.
+func (m *MongoDBConnection) withTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) error) error {
+	session, err := m.mongoDbClient.StartSession()
+	if err != nil {
+		return errors.Wrap(err, "failed to start mongo db session")
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// Ping checks that the primary of the database is reachable.
+func (m *MongoDBConnection) Ping(ctx context.Context) error {
+	if err := m.mongoDbClient.Ping(ctx, readpref.Primary()); err != nil {
+		return errors.Wrap(err, "failed to ping mongo db")
+	}
+
+	return nil
+}
+
+func (m *MongoDBConnection) Close() error {
+	if err := m.mongoDbClient.Disconnect(context.TODO()); err != nil {
+		log.Logger().WithError(err).Fatal("failed to disconnect from mongo db")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+)
+
+const outboxCollectionName = "outbox"
+
+var (
+	ErrOutboxEventNotFound = errors.New("outbox event not found")
+)
+
+type OutboxEventStatus string
+
+const (
+	OutboxEventStatusPending   OutboxEventStatus = "pending"
+	OutboxEventStatusDelivered OutboxEventStatus = "delivered"
+)
+
+// OutboxEvent is an event of a write that is stored together with the write, so that it is delivered even if the
+// process dies right after the write. The ID is the idempotency key of the event, it is the same for every attempt to
+// deliver it.
+type OutboxEvent struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	AggregateID string             `bson:"aggregate_id" json:"aggregateId"`
+	// Data is the JSON encoded state after the write, it is empty for deletions.
+	Data   string            `bson:"data" json:"data"`
+	Status OutboxEventStatus `bson:"status" json:"status"`
+	// DeliveredTo are the names of the sinks the event was delivered to, so that a retry after a failed sink doesn't
+	// deliver the event to the other sinks again.
+	DeliveredTo   []string  `bson:"delivered_to" json:"deliveredTo"`
+	Failures      int       `bson:"failures" json:"failures"`
+	LastError     string    `bson:"last_error,omitempty" json:"lastError,omitempty"`
+	NextAttemptAt time.Time `bson:"next_attempt_at" json:"nextAttemptAt"`
+	CreatedAt     time.Time `bson:"created_at" json:"createdAt"`
+	DeliveredAt   time.Time `bson:"delivered_at" json:"deliveredAt"`
+}
+
+func newOutboxEvent(eventType events.Type, aggregateID primitive.ObjectID, data interface{}) (*OutboxEvent, error) {
+	var encodedData string
+	if data != nil {
+		dataJson, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal outbox event data")
+		}
+		encodedData = string(dataJson)
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            primitive.NewObjectID(),
+		Type:          string(eventType),
+		AggregateID:   aggregateID.Hex(),
+		Data:          encodedData,
+		Status:        OutboxEventStatusPending,
+		DeliveredTo:   []string{},
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// factUpdateEventType returns the event type of an update, updates that change the approval are approvals or
+// unapprovals. Updates that change neither the approval nor the public content of the fact, e.g. only whether it is
+// flagged, have no event, so that subscribers are not notified about changes they can't see.
+func factUpdateEventType(fact *Fact, updatedFact *Fact) (events.Type, bool) {
+	switch {
+	case !fact.Approved && updatedFact.Approved:
+		return events.TypeFactApproved, true
+	case fact.Approved && !updatedFact.Approved:
+		return events.TypeFactUnapproved, true
+	case fact.Fact != updatedFact.Fact || fact.Source != updatedFact.Source || fact.Animal != updatedFact.Animal ||
+		fact.Language != updatedFact.Language || !slices.Equal(fact.Tags, updatedFact.Tags):
+		return events.TypeFactUpdated, true
+	default:
+		return "", false
+	}
+}
+
+type OutboxRepository interface {
+	// Append adds an event outside of a write, the writes of the mongo db repositories append their events in the same
+	// transaction instead.
+	Append(event *OutboxEvent) error
+	// ClaimDueEvent returns the oldest pending event whose next attempt is due and moves its next attempt by lease, so
+	// that no other relay delivers it at the same time. It returns ErrOutboxEventNotFound if no event is due.
+	ClaimDueEvent(now time.Time, lease time.Duration) (*OutboxEvent, error)
+	UpdateEvent(id primitive.ObjectID, updateFunc func(event *OutboxEvent) *OutboxEvent) error
+	// DeleteDelivered deletes the events that were delivered before the given time and returns their number.
+	DeleteDelivered(before time.Time) (int, error)
+}
+
+type MongoDBOutboxRepository struct {
+	connection *MongoDBConnection
+}
+
+// NewMongoDBOutboxRepository creates the index of the due events if it doesn't exist yet, the relay polls them in the
+// order of the index.
+func NewMongoDBOutboxRepository(connection *MongoDBConnection) (OutboxRepository, error) {
+	repository := &MongoDBOutboxRepository{connection}
+	_, err := repository.outboxCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create due index of outbox events")
+	}
+
+	return repository, nil
+}
+
+func (m *MongoDBOutboxRepository) outboxCollection() *mongo.Collection {
+	return m.connection.collection(outboxCollectionName)
+}
+
+func (m *MongoDBOutboxRepository) Append(event *OutboxEvent) error {
+	_, err := m.outboxCollection().InsertOne(context.TODO(), event)
+	return err
+}
+
+func (m *MongoDBOutboxRepository) ClaimDueEvent(now time.Time, lease time.Duration) (*OutboxEvent, error) {
+	filter := bson.M{"status": OutboxEventStatusPending, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"_id": 1}).SetReturnDocument(options.Before)
+
+	var result OutboxEvent
+	err := m.outboxCollection().FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrOutboxEventNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to claim due outbox event")
+	}
+
+	return &result, nil
+}
+
+func (m *MongoDBOutboxRepository) UpdateEvent(id primitive.ObjectID, updateFunc func(event *OutboxEvent) *OutboxEvent) error {
+	var event OutboxEvent
+	err := m.outboxCollection().FindOne(context.TODO(), bson.M{"_id": id}).Decode(&event)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrOutboxEventNotFound
+	} else if err != nil {
+		return err
+	}
+
+	_, err = m.outboxCollection().UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": updateFunc(&event)})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update outbox event with ID '%v'", id)
+	}
+
+	return nil
+}
+
+func (m *MongoDBOutboxRepository) DeleteDelivered(before time.Time) (int, error) {
+	filter := bson.M{"status": OutboxEventStatusDelivered, "delivered_at": bson.M{"$lt": before}}
+	result, err := m.outboxCollection().DeleteMany(context.TODO(), filter)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete delivered outbox events")
+	}
+
+	return int(result.DeletedCount), nil
+}
+
+// MockOutboxRepository is safe for concurrent use, as events are delivered by a background worker.
+type MockOutboxRepository struct {
+	mutex                 sync.Mutex
+	events                map[primitive.ObjectID]*OutboxEvent
+	errorAllFunctionCalls bool
+}
+
+func NewMockOutboxRepository(errorAllFunctionCalls bool) OutboxRepository {
+	return &MockOutboxRepository{
+		events:                map[primitive.ObjectID]*OutboxEvent{},
+		errorAllFunctionCalls: errorAllFunctionCalls,
+	}
+}
+
+func (m *MockOutboxRepository) Append(event *OutboxEvent) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at appending outbox event")
+	}
+
+	eventCopy := *event
+	m.events[event.ID] = &eventCopy
+	return nil
+}
+
+func (m *MockOutboxRepository) ClaimDueEvent(now time.Time, lease time.Duration) (*OutboxEvent, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at claiming outbox event")
+	}
+
+	var due *OutboxEvent
+	for _, event := range m.events {
+		if event.Status == OutboxEventStatusPending && !event.NextAttemptAt.After(now) &&
+			(due == nil || bytes.Compare(event.ID[:], due.ID[:]) < 0) {
+			due = event
+		}
+	}
+	if due == nil {
+		return nil, ErrOutboxEventNotFound
+	}
+
+	claimed := *due
+	claimed.DeliveredTo = slices.Clone(due.DeliveredTo)
+	due.NextAttemptAt = now.Add(lease)
+	return &claimed, nil
+}
+
+func (m *MockOutboxRepository) UpdateEvent(id primitive.ObjectID, updateFunc func(event *OutboxEvent) *OutboxEvent) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at updating outbox event")
+	}
+
+	event, exists := m.events[id]
+	if !exists {
+		return ErrOutboxEventNotFound
+	}
+
+	eventToUpdate := *event
+	eventToUpdate.DeliveredTo = slices.Clone(event.DeliveredTo)
+	m.events[id] = updateFunc(&eventToUpdate)
+	return nil
+}
+
+func (m *MockOutboxRepository) DeleteDelivered(before time.Time) (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return 0, errors.New("error at deleting delivered outbox events")
+	}
+
+	deleted := 0
+	for id, event := range m.events {
+		if event.Status == OutboxEventStatusDelivered && event.DeliveredAt.Before(before) {
+			delete(m.events, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
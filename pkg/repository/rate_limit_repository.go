@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	rateLimitCollectionName = "rate_limits"
+	// rateLimitUpdateAttempts is how often a counter update is retried if the counter is changed concurrently
+	rateLimitUpdateAttempts = 10
+)
+
+var (
+	ErrRateLimitConflict = errors.New("rate limit counter was changed concurrently too often")
+)
+
+// RateLimitCounter is the state of a rate limited key, the meaning of the values depends on the algorithm of the limit.
+type RateLimitCounter struct {
+	Key string `bson:"_id"`
+	// Value is the number of tokens left of a token bucket, or the number of requests in the current window of a
+	// sliding window.
+	Value float64 `bson:"value"`
+	// Previous is the number of requests in the previous window of a sliding window.
+	Previous float64 `bson:"previous"`
+	// Time is the last refill of a token bucket, or the start of the current window of a sliding window.
+	Time time.Time `bson:"time"`
+	// ExpiresAt is the time from which on the counter is the same as a new one, so it can be deleted.
+	ExpiresAt time.Time `bson:"expires_at"`
+	// Version is increased with every update, it is 0 for new counters.
+	Version int64 `bson:"version"`
+}
+
+type RateLimitRepository interface {
+	// ReadCounter returns the counter of the key, the counter of an unknown key is the zero counter.
+	ReadCounter(key string) (*RateLimitCounter, error)
+	// UpdateCounter applies updateFunc atomically to the counter of the key and returns the updated counter, the
+	// counter of an unknown key is the zero counter. updateFunc is called again if the counter was changed concurrently.
+	UpdateCounter(key string, updateFunc func(counter *RateLimitCounter)) (*RateLimitCounter, error)
+}
+
+// MongoDBRateLimitRepository shares the counters between all replicas of an API. Expired counters are deleted by a TTL
+// index on expires_at.
+type MongoDBRateLimitRepository struct {
+	connection *MongoDBConnection
+}
+
+// NewMongoDBRateLimitRepository creates the TTL index on expires_at if it doesn't exist yet, so that the counters of
+// keys that are not seen anymore don't pile up.
+func NewMongoDBRateLimitRepository(connection *MongoDBConnection) (RateLimitRepository, error) {
+	repository := &MongoDBRateLimitRepository{connection}
+	_, err := repository.rateLimitCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ttl index of rate limit counters")
+	}
+
+	return repository, nil
+}
+
+func (m *MongoDBRateLimitRepository) rateLimitCollection() *mongo.Collection {
+	return m.connection.collection(rateLimitCollectionName)
+}
+
+func (m *MongoDBRateLimitRepository) ReadCounter(key string) (*RateLimitCounter, error) {
+	counter := RateLimitCounter{Key: key}
+	err := m.rateLimitCollection().FindOne(context.TODO(), bson.M{"_id": key}).Decode(&counter)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errors.Wrapf(err, "failed to read rate limit counter '%s'", key)
+	}
+
+	return &counter, nil
+}
+
+func (m *MongoDBRateLimitRepository) UpdateCounter(key string, updateFunc func(counter *RateLimitCounter)) (*RateLimitCounter, error) {
+	for attempt := 0; attempt < rateLimitUpdateAttempts; attempt++ {
+		counter := RateLimitCounter{Key: key}
+		err := m.rateLimitCollection().FindOne(context.TODO(), bson.M{"_id": key}).Decode(&counter)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrapf(err, "failed to read rate limit counter '%s'", key)
+		}
+
+		version := counter.Version
+		updateFunc(&counter)
+		counter.Key = key
+		counter.Version = version + 1
+
+		// the version makes the update optimistic, it fails if another replica updated the counter in the meantime
+		if version == 0 {
+			_, err = m.rateLimitCollection().InsertOne(context.TODO(), counter)
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+		} else {
+			var result *mongo.UpdateResult
+			result, err = m.rateLimitCollection().ReplaceOne(context.TODO(), bson.M{"_id": key, "version": version}, counter)
+			if err == nil && result.MatchedCount == 0 {
+				continue
+			}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to update rate limit counter '%s'", key)
+		}
+
+		return &counter, nil
+	}
+
+	return nil, ErrRateLimitConflict
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrReportNotFound = errors.New("report not found")
+)
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+type ReportReason string
+
+const (
+	ReportReasonIncorrect     ReportReason = "incorrect"
+	ReportReasonOutdated      ReportReason = "outdated"
+	ReportReasonMissingSource ReportReason = "missing-source"
+	ReportReasonOffensive     ReportReason = "offensive"
+	ReportReasonOther         ReportReason = "other"
+)
+
+var ReportReasons = []ReportReason{
+	ReportReasonIncorrect,
+	ReportReasonOutdated,
+	ReportReasonMissingSource,
+	ReportReasonOffensive,
+	ReportReasonOther,
+}
+
+type Report struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	FactID    primitive.ObjectID `bson:"fact_id" json:"factId"`
+	Reason    ReportReason       `bson:"reason" json:"reason"`
+	Text      string             `bson:"text" json:"text"`
+	Status    ReportStatus       `bson:"status" json:"status"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updatedAt"`
+	UpdatedBy string             `bson:"updated_by" json:"updatedBy"`
+}
+
+type ReportsRepository interface {
+	Create(report *Report) error
+	ReadOne(id primitive.ObjectID) (*Report, error)
+	ReadMany(filterFunc func(report *Report) bool) ([]*Report, error)
+	CountOpen(factID primitive.ObjectID) (int, error)
+	Update(id primitive.ObjectID, updateFunc func(report *Report) *Report) error
+}
+
+type MongoDBReportsRepository struct {
+	connection *MongoDBConnection
+}
+
+func NewMongoDBReportsRepository(connection *MongoDBConnection) ReportsRepository {
+	return &MongoDBReportsRepository{connection}
+}
+
+func (m *MongoDBReportsRepository) reportsCollection() *mongo.Collection {
+	return m.connection.collection("reports")
+}
+
+func (m *MongoDBReportsRepository) Create(report *Report) error {
+	_, err := m.reportsCollection().InsertOne(context.TODO(), report)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *MongoDBReportsRepository) ReadOne(id primitive.ObjectID) (*Report, error) {
+	filter := bson.M{"_id": id}
+	var result Report
+	err := m.reportsCollection().FindOne(context.TODO(), filter).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrReportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (m *MongoDBReportsRepository) ReadMany(filterFunc func(report *Report) bool) ([]*Report, error) {
+	cursor, err := m.reportsCollection().Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []Report
+	if err = cursor.All(context.TODO(), &reports); err != nil {
+		return nil, err
+	}
+
+	result := []*Report{}
+	for i := range reports {
+		if filterFunc(&reports[i]) {
+			result = append(result, &reports[i])
+		}
+	}
+
+	return result, nil
+}
+
+func (m *MongoDBReportsRepository) CountOpen(factID primitive.ObjectID) (int, error) {
+	filter := bson.M{"fact_id": factID, "status": ReportStatusOpen}
+	count, err := m.reportsCollection().CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to count open reports for fact with ID '%v'", factID)
+	}
+
+	return int(count), nil
+}
+
+func (m *MongoDBReportsRepository) Update(id primitive.ObjectID, updateFunc func(report *Report) *Report) error {
+	filter := bson.M{"_id": id}
+	var readResult Report
+	err := m.reportsCollection().FindOne(context.TODO(), filter).Decode(&readResult)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrReportNotFound
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to get report with ID '%v' before updating", id)
+	}
+
+	updatedReport := updateFunc(&readResult)
+	_, err = m.reportsCollection().UpdateOne(context.TODO(), filter, bson.M{"$set": updatedReport})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update report with ID '%v'", id)
+	}
+
+	return nil
+}
+
+type MockReportsRepository struct {
+	reports               map[primitive.ObjectID]*Report
+	errorAllFunctionCalls bool
+}
+
+func NewMockReportsRepository(reports map[primitive.ObjectID]*Report, errorAllFunctionCalls bool) ReportsRepository {
+	return &MockReportsRepository{reports, errorAllFunctionCalls}
+}
+
+func (m *MockReportsRepository) Create(report *Report) error {
+	if m.errorAllFunctionCalls {
+		return errors.New("error at creating report")
+	}
+
+	m.reports[report.ID] = report
+	return nil
+}
+
+func (m *MockReportsRepository) ReadOne(id primitive.ObjectID) (*Report, error) {
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting report")
+	}
+
+	if report, exists := m.reports[id]; exists {
+		return report, nil
+	}
+
+	return nil, ErrReportNotFound
+}
+
+func (m *MockReportsRepository) ReadMany(filterFunc func(report *Report) bool) ([]*Report, error) {
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting reports")
+	}
+
+	result := []*Report{}
+	for _, report := range m.reports {
+		if filterFunc(report) {
+			result = append(result, report)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *MockReportsRepository) CountOpen(factID primitive.ObjectID) (int, error) {
+	if m.errorAllFunctionCalls {
+		return 0, errors.New("error at counting open reports")
+	}
+
+	count := 0
+	for _, report := range m.reports {
+		if report.FactID == factID && report.Status == ReportStatusOpen {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (m *MockReportsRepository) Update(id primitive.ObjectID, updateFunc func(report *Report) *Report) error {
+	if m.errorAllFunctionCalls {
+		return errors.New("error at updating report")
+	}
+
+	report, exists := m.reports[id]
+	if !exists {
+		return ErrReportNotFound
+	}
+
+	reportToUpdate := *report
+	m.reports[id] = updateFunc(&reportToUpdate)
+	return nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ServeEventsRetention is the time serve events are kept, the serve counts rolled up from them are kept forever.
+const ServeEventsRetention = 90 * 24 * time.Hour
+
+type Granularity string
+
+const (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+)
+
+// Truncate returns the start of the period of the granularity the given time is in.
+func (g Granularity) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	if g == GranularityDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	return t.Truncate(time.Hour)
+}
+
+// Duration returns the length of one period of the granularity.
+func (g Granularity) Duration() time.Duration {
+	if g == GranularityDay {
+		return 24 * time.Hour
+	}
+
+	return time.Hour
+}
+
+// ServeEvent is recorded every time a fact is delivered by the public API.
+type ServeEvent struct {
+	FactID     primitive.ObjectID `bson:"fact_id" json:"factId"`
+	Endpoint   string             `bson:"endpoint" json:"endpoint"`
+	ClientType string             `bson:"client_type" json:"clientType"`
+	Timestamp  time.Time          `bson:"timestamp" json:"timestamp"`
+}
+
+// ServeCount is the number of serves of a fact in one period (hour or day) starting at PeriodStart.
+type ServeCount struct {
+	FactID      primitive.ObjectID `bson:"fact_id" json:"factId"`
+	Granularity Granularity        `bson:"granularity" json:"granularity"`
+	PeriodStart time.Time          `bson:"period_start" json:"periodStart"`
+	Count       int                `bson:"count" json:"count"`
+}
+
+// ServeCountsFilter selects serve counts of a granularity in the time range [From, To), optionally only of a single fact.
+type ServeCountsFilter struct {
+	FactID      *primitive.ObjectID
+	Granularity Granularity
+	From        time.Time
+	To          time.Time
+}
+
+func (f ServeCountsFilter) matches(serveCount *ServeCount) bool {
+	if f.FactID != nil && *f.FactID != serveCount.FactID {
+		return false
+	}
+
+	return serveCount.Granularity == f.Granularity &&
+		!serveCount.PeriodStart.Before(f.From) &&
+		serveCount.PeriodStart.Before(f.To)
+}
+
+type ServeStatsRepository interface {
+	// RecordServes stores the events and rolls them up into the hourly and daily serve counts.
+	RecordServes(events []*ServeEvent) error
+	ReadCounts(filter ServeCountsFilter) ([]*ServeCount, error)
+}
+
+type MongoDBServeStatsRepository struct {
+	connection *MongoDBConnection
+}
+
+// NewMongoDBServeStatsRepository creates the indexes of the serve stats if they don't exist yet: a TTL index deletes
+// the serve events after ServeEventsRetention and the serve counts have one document per fact, granularity and period.
+func NewMongoDBServeStatsRepository(connection *MongoDBConnection) (ServeStatsRepository, error) {
+	repository := &MongoDBServeStatsRepository{connection}
+	_, err := repository.serveEventsCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "timestamp", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(ServeEventsRetention.Seconds())),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ttl index of serve events")
+	}
+	_, err = repository.serveCountsCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "fact_id", Value: 1}, {Key: "granularity", Value: 1}, {Key: "period_start", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create period index of serve counts")
+	}
+
+	return repository, nil
+}
+
+func (m *MongoDBServeStatsRepository) serveEventsCollection() *mongo.Collection {
+	return m.connection.collection("serve_events")
+}
+
+func (m *MongoDBServeStatsRepository) serveCountsCollection() *mongo.Collection {
+	return m.connection.collection("serve_counts")
+}
+
+func (m *MongoDBServeStatsRepository) RecordServes(events []*ServeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		documents = append(documents, event)
+	}
+
+	_, err := m.serveEventsCollection().InsertMany(context.TODO(), documents)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert serve events")
+	}
+
+	var writes []mongo.WriteModel
+	for _, serveCount := range rollUp(events) {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"fact_id":      serveCount.FactID,
+				"granularity":  serveCount.Granularity,
+				"period_start": serveCount.PeriodStart,
+			}).
+			SetUpdate(bson.M{"$inc": bson.M{"count": serveCount.Count}}).
+			SetUpsert(true))
+	}
+
+	_, err = m.serveCountsCollection().BulkWrite(context.TODO(), writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return errors.Wrap(err, "failed to update serve counts")
+	}
+
+	return nil
+}
+
+func (m *MongoDBServeStatsRepository) ReadCounts(filter ServeCountsFilter) ([]*ServeCount, error) {
+	query := bson.M{
+		"granularity":  filter.Granularity,
+		"period_start": bson.M{"$gte": filter.From, "$lt": filter.To},
+	}
+	if filter.FactID != nil {
+		query["fact_id"] = *filter.FactID
+	}
+
+	cursor, err := m.serveCountsCollection().Find(context.TODO(), query, options.Find().SetSort(bson.M{"period_start": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*ServeCount{}
+	if err = cursor.All(context.TODO(), &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// rollUp sums up the events per fact into hourly and daily serve counts.
+func rollUp(events []*ServeEvent) []*ServeCount {
+	type key struct {
+		factID      primitive.ObjectID
+		granularity Granularity
+		periodStart time.Time
+	}
+
+	var keys []key
+	counts := map[key]int{}
+	for _, event := range events {
+		for _, granularity := range []Granularity{GranularityHour, GranularityDay} {
+			k := key{event.FactID, granularity, granularity.Truncate(event.Timestamp)}
+			if _, exists := counts[k]; !exists {
+				keys = append(keys, k)
+			}
+			counts[k]++
+		}
+	}
+
+	result := make([]*ServeCount, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, &ServeCount{
+			FactID:      k.factID,
+			Granularity: k.granularity,
+			PeriodStart: k.periodStart,
+			Count:       counts[k],
+		})
+	}
+
+	return result
+}
+
+type MockServeStatsRepository struct {
+	serveCounts           []*ServeCount
+	errorAllFunctionCalls bool
+}
+
+func NewMockServeStatsRepository(serveCounts []*ServeCount, errorAllFunctionCalls bool) ServeStatsRepository {
+	return &MockServeStatsRepository{serveCounts, errorAllFunctionCalls}
+}
+
+func (m *MockServeStatsRepository) RecordServes(events []*ServeEvent) error {
+	if m.errorAllFunctionCalls {
+		return errors.New("error at recording serves")
+	}
+
+	for _, rolledUp := range rollUp(events) {
+		found := false
+		for _, serveCount := range m.serveCounts {
+			if serveCount.FactID == rolledUp.FactID && serveCount.Granularity == rolledUp.Granularity && serveCount.PeriodStart.Equal(rolledUp.PeriodStart) {
+				serveCount.Count += rolledUp.Count
+				found = true
+				break
+			}
+		}
+		if !found {
+			m.serveCounts = append(m.serveCounts, rolledUp)
+		}
+	}
+
+	return nil
+}
+
+func (m *MockServeStatsRepository) ReadCounts(filter ServeCountsFilter) ([]*ServeCount, error) {
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting serve counts")
+	}
+
+	result := []*ServeCount{}
+	for _, serveCount := range m.serveCounts {
+		if filter.matches(serveCount) {
+			result = append(result, serveCount)
+		}
+	}
+
+	return result, nil
+}
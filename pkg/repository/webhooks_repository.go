@@ -0,0 +1,473 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+// WebhookSubscription sends the events of the event types to the URL. The secret signs the deliveries, it is never
+// returned by the API after the subscription was created.
+type WebhookSubscription struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	URL        string             `bson:"url" json:"url"`
+	EventTypes []string           `bson:"event_types" json:"eventTypes"`
+	Secret     string             `bson:"secret" json:"-"`
+	Active     bool               `bson:"active" json:"active"`
+	CreatedAt  time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updatedAt"`
+	UpdatedBy  string             `bson:"updated_by" json:"updatedBy"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryStatusDead is the status of deliveries that failed too often, they are only sent again if they
+	// are redelivered manually.
+	WebhookDeliveryStatusDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookAttempt is one try to send a delivery, StatusCode is 0 if no response was received. Duration is in
+// nanoseconds.
+type WebhookAttempt struct {
+	At         time.Time     `bson:"at" json:"at"`
+	StatusCode int           `bson:"status_code" json:"statusCode"`
+	Error      string        `bson:"error,omitempty" json:"error,omitempty"`
+	Duration   time.Duration `bson:"duration" json:"duration" swaggertype:"integer"`
+}
+
+// WebhookDelivery is an event that is sent to a subscription. Its ID is sent with every attempt, so that receivers
+// can ignore deliveries they already processed. Failures counts the failed attempts since the delivery was created or
+// redelivered, all attempts are kept in Attempts.
+type WebhookDelivery struct {
+	ID             primitive.ObjectID    `bson:"_id" json:"id"`
+	SubscriptionID primitive.ObjectID    `bson:"subscription_id" json:"subscriptionId"`
+	EventType      string                `bson:"event_type" json:"eventType"`
+	Payload        string                `bson:"payload" json:"payload"`
+	Status         WebhookDeliveryStatus `bson:"status" json:"status"`
+	Attempts       []WebhookAttempt      `bson:"attempts" json:"attempts"`
+	Failures       int                   `bson:"failures" json:"failures"`
+	NextAttemptAt  time.Time             `bson:"next_attempt_at" json:"nextAttemptAt"`
+	CreatedAt      time.Time             `bson:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time             `bson:"updated_at" json:"updatedAt"`
+}
+
+type WebhooksRepository interface {
+	CreateSubscription(subscription *WebhookSubscription) error
+	ReadSubscription(id primitive.ObjectID) (*WebhookSubscription, error)
+	ReadSubscriptions(filterFunc func(subscription *WebhookSubscription) bool) ([]*WebhookSubscription, error)
+	UpdateSubscription(id primitive.ObjectID, updateFunc func(subscription *WebhookSubscription) *WebhookSubscription) error
+	DeleteSubscription(id primitive.ObjectID) error
+
+	// CreateDeliveries skips deliveries with the ID of an existing delivery, so that deliveries with IDs derived from
+	// their event are only created once.
+	CreateDeliveries(deliveries []*WebhookDelivery) error
+	ReadDelivery(id primitive.ObjectID) (*WebhookDelivery, error)
+	// ReadDeliveries returns the deliveries of the subscription with the latest first, all statuses if status is empty.
+	ReadDeliveries(subscriptionID primitive.ObjectID, status WebhookDeliveryStatus, limit int) ([]*WebhookDelivery, error)
+	// ClaimDueDelivery returns a pending delivery whose next attempt is due and moves its next attempt by lease, so that
+	// no other worker sends it at the same time. It returns ErrWebhookDeliveryNotFound if no delivery is due.
+	ClaimDueDelivery(now time.Time, lease time.Duration) (*WebhookDelivery, error)
+	UpdateDelivery(id primitive.ObjectID, updateFunc func(delivery *WebhookDelivery) *WebhookDelivery) error
+}
+
+type MongoDBWebhooksRepository struct {
+	connection *MongoDBConnection
+}
+
+// NewMongoDBWebhooksRepository creates the index of the due deliveries if it doesn't exist yet, the dispatcher polls
+// them in the order of the index.
+func NewMongoDBWebhooksRepository(connection *MongoDBConnection) (WebhooksRepository, error) {
+	repository := &MongoDBWebhooksRepository{connection}
+	_, err := repository.deliveriesCollection().Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create due index of webhook deliveries")
+	}
+
+	return repository, nil
+}
+
+func (m *MongoDBWebhooksRepository) subscriptionsCollection() *mongo.Collection {
+	return m.connection.collection("webhook_subscriptions")
+}
+
+func (m *MongoDBWebhooksRepository) deliveriesCollection() *mongo.Collection {
+	return m.connection.collection("webhook_deliveries")
+}
+
+func (m *MongoDBWebhooksRepository) CreateSubscription(subscription *WebhookSubscription) error {
+	_, err := m.subscriptionsCollection().InsertOne(context.TODO(), subscription)
+	return err
+}
+
+func (m *MongoDBWebhooksRepository) ReadSubscription(id primitive.ObjectID) (*WebhookSubscription, error) {
+	var result WebhookSubscription
+	err := m.subscriptionsCollection().FindOne(context.TODO(), bson.M{"_id": id}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWebhookSubscriptionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (m *MongoDBWebhooksRepository) ReadSubscriptions(filterFunc func(subscription *WebhookSubscription) bool) ([]*WebhookSubscription, error) {
+	cursor, err := m.subscriptionsCollection().Find(context.TODO(), bson.M{}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var subscriptions []WebhookSubscription
+	if err = cursor.All(context.TODO(), &subscriptions); err != nil {
+		return nil, err
+	}
+
+	result := []*WebhookSubscription{}
+	for i := range subscriptions {
+		if filterFunc(&subscriptions[i]) {
+			result = append(result, &subscriptions[i])
+		}
+	}
+
+	return result, nil
+}
+
+func (m *MongoDBWebhooksRepository) UpdateSubscription(id primitive.ObjectID, updateFunc func(subscription *WebhookSubscription) *WebhookSubscription) error {
+	subscription, err := m.ReadSubscription(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.subscriptionsCollection().UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": updateFunc(subscription)})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update webhook subscription with ID '%v'", id)
+	}
+
+	return nil
+}
+
+func (m *MongoDBWebhooksRepository) DeleteSubscription(id primitive.ObjectID) error {
+	result, err := m.subscriptionsCollection().DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete webhook subscription with ID '%v'", id)
+	}
+	if result.DeletedCount == 0 {
+		return ErrWebhookSubscriptionNotFound
+	}
+
+	_, err = m.deliveriesCollection().DeleteMany(context.TODO(), bson.M{"subscription_id": id})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete deliveries of webhook subscription with ID '%v'", id)
+	}
+
+	return nil
+}
+
+func (m *MongoDBWebhooksRepository) CreateDeliveries(deliveries []*WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		documents = append(documents, delivery)
+	}
+
+	// the inserts are unordered, so that the deliveries after an existing one are inserted as well
+	_, err := m.deliveriesCollection().InsertMany(context.TODO(), documents, options.InsertMany().SetOrdered(false))
+	var bulkWriteErr mongo.BulkWriteException
+	if errors.As(err, &bulkWriteErr) && bulkWriteErr.WriteConcernError == nil &&
+		!slices.ContainsFunc(bulkWriteErr.WriteErrors, func(writeErr mongo.BulkWriteError) bool { return !mongo.IsDuplicateKeyError(writeErr) }) {
+		return nil
+	}
+	return err
+}
+
+func (m *MongoDBWebhooksRepository) ReadDelivery(id primitive.ObjectID) (*WebhookDelivery, error) {
+	var result WebhookDelivery
+	err := m.deliveriesCollection().FindOne(context.TODO(), bson.M{"_id": id}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWebhookDeliveryNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (m *MongoDBWebhooksRepository) ReadDeliveries(subscriptionID primitive.ObjectID, status WebhookDeliveryStatus, limit int) ([]*WebhookDelivery, error) {
+	filter := bson.M{"subscription_id": subscriptionID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := m.deliveriesCollection().Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*WebhookDelivery{}
+	if err = cursor.All(context.TODO(), &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (m *MongoDBWebhooksRepository) ClaimDueDelivery(now time.Time, lease time.Duration) (*WebhookDelivery, error) {
+	filter := bson.M{"status": WebhookDeliveryStatusPending, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).SetReturnDocument(options.Before)
+
+	var result WebhookDelivery
+	err := m.deliveriesCollection().FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWebhookDeliveryNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to claim due webhook delivery")
+	}
+
+	return &result, nil
+}
+
+func (m *MongoDBWebhooksRepository) UpdateDelivery(id primitive.ObjectID, updateFunc func(delivery *WebhookDelivery) *WebhookDelivery) error {
+	delivery, err := m.ReadDelivery(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.deliveriesCollection().UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": updateFunc(delivery)})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update webhook delivery with ID '%v'", id)
+	}
+
+	return nil
+}
+
+// MockWebhooksRepository is safe for concurrent use, as deliveries are sent by background workers.
+type MockWebhooksRepository struct {
+	mutex                 sync.Mutex
+	subscriptions         map[primitive.ObjectID]*WebhookSubscription
+	deliveries            map[primitive.ObjectID]*WebhookDelivery
+	errorAllFunctionCalls bool
+}
+
+func NewMockWebhooksRepository(subscriptions map[primitive.ObjectID]*WebhookSubscription, errorAllFunctionCalls bool) WebhooksRepository {
+	return &MockWebhooksRepository{
+		subscriptions:         subscriptions,
+		deliveries:            map[primitive.ObjectID]*WebhookDelivery{},
+		errorAllFunctionCalls: errorAllFunctionCalls,
+	}
+}
+
+func (m *MockWebhooksRepository) CreateSubscription(subscription *WebhookSubscription) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at creating webhook subscription")
+	}
+
+	m.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+func (m *MockWebhooksRepository) ReadSubscription(id primitive.ObjectID) (*WebhookSubscription, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting webhook subscription")
+	}
+
+	if subscription, exists := m.subscriptions[id]; exists {
+		subscriptionCopy := *subscription
+		return &subscriptionCopy, nil
+	}
+
+	return nil, ErrWebhookSubscriptionNotFound
+}
+
+func (m *MockWebhooksRepository) ReadSubscriptions(filterFunc func(subscription *WebhookSubscription) bool) ([]*WebhookSubscription, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting webhook subscriptions")
+	}
+
+	result := []*WebhookSubscription{}
+	for _, subscription := range m.subscriptions {
+		subscriptionCopy := *subscription
+		if filterFunc(&subscriptionCopy) {
+			result = append(result, &subscriptionCopy)
+		}
+	}
+	slices.SortFunc(result, func(a, b *WebhookSubscription) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (m *MockWebhooksRepository) UpdateSubscription(id primitive.ObjectID, updateFunc func(subscription *WebhookSubscription) *WebhookSubscription) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at updating webhook subscription")
+	}
+
+	subscription, exists := m.subscriptions[id]
+	if !exists {
+		return ErrWebhookSubscriptionNotFound
+	}
+
+	subscriptionToUpdate := *subscription
+	m.subscriptions[id] = updateFunc(&subscriptionToUpdate)
+	return nil
+}
+
+func (m *MockWebhooksRepository) DeleteSubscription(id primitive.ObjectID) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at deleting webhook subscription")
+	}
+
+	if _, exists := m.subscriptions[id]; !exists {
+		return ErrWebhookSubscriptionNotFound
+	}
+
+	delete(m.subscriptions, id)
+	for deliveryID, delivery := range m.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(m.deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+func (m *MockWebhooksRepository) CreateDeliveries(deliveries []*WebhookDelivery) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at creating webhook deliveries")
+	}
+
+	for _, delivery := range deliveries {
+		if _, exists := m.deliveries[delivery.ID]; exists {
+			continue
+		}
+		deliveryCopy := *delivery
+		m.deliveries[delivery.ID] = &deliveryCopy
+	}
+
+	return nil
+}
+
+func (m *MockWebhooksRepository) ReadDelivery(id primitive.ObjectID) (*WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting webhook delivery")
+	}
+
+	if delivery, exists := m.deliveries[id]; exists {
+		deliveryCopy := *delivery
+		return &deliveryCopy, nil
+	}
+
+	return nil, ErrWebhookDeliveryNotFound
+}
+
+func (m *MockWebhooksRepository) ReadDeliveries(subscriptionID primitive.ObjectID, status WebhookDeliveryStatus, limit int) ([]*WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at getting webhook deliveries")
+	}
+
+	result := []*WebhookDelivery{}
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionID == subscriptionID && (status == "" || delivery.Status == status) {
+			deliveryCopy := *delivery
+			result = append(result, &deliveryCopy)
+		}
+	}
+	slices.SortFunc(result, func(a, b *WebhookDelivery) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(b.ID[:], a.ID[:])
+	})
+
+	return result[:min(len(result), limit)], nil
+}
+
+func (m *MockWebhooksRepository) ClaimDueDelivery(now time.Time, lease time.Duration) (*WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return nil, errors.New("error at claiming webhook delivery")
+	}
+
+	var due *WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == WebhookDeliveryStatusPending && !delivery.NextAttemptAt.After(now) &&
+			(due == nil || delivery.NextAttemptAt.Before(due.NextAttemptAt)) {
+			due = delivery
+		}
+	}
+	if due == nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	claimed := *due
+	due.NextAttemptAt = now.Add(lease)
+	return &claimed, nil
+}
+
+func (m *MockWebhooksRepository) UpdateDelivery(id primitive.ObjectID, updateFunc func(delivery *WebhookDelivery) *WebhookDelivery) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.errorAllFunctionCalls {
+		return errors.New("error at updating webhook delivery")
+	}
+
+	delivery, exists := m.deliveries[id]
+	if !exists {
+		return ErrWebhookDeliveryNotFound
+	}
+
+	deliveryToUpdate := *delivery
+	deliveryToUpdate.Attempts = slices.Clone(delivery.Attempts)
+	m.deliveries[id] = updateFunc(&deliveryToUpdate)
+	return nil
+}
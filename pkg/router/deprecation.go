@@ -0,0 +1,53 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+)
+
+// Deprecation announces that a route or a version of the api is deprecated. Its responses get the Deprecation header
+// (RFC 9745) with the time of the deprecation and, if set, the Sunset header (RFC 8594) with the time the route is
+// removed and a Link header to the documentation of the deprecation.
+type Deprecation struct {
+	Since time.Time
+	// Sunset is zero if the removal is not planned yet
+	Sunset time.Time
+	// Link is the URL of the documentation of the deprecation, e.g. how to migrate to the next version
+	Link string
+}
+
+// Middleware sets the headers of the deprecation before the route runs, so that errors have them as well.
+func (d *Deprecation) Middleware() echo.MiddlewareFunc {
+	deprecation := fmt.Sprintf("@%d", d.Since.Unix())
+	var sunset string
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	var link string
+	if d.Link != "" {
+		link = fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, d.Link)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set(HeaderDeprecation, deprecation)
+			if sunset != "" {
+				header.Set(HeaderSunset, sunset)
+			}
+			if link != "" {
+				header.Add("Link", link)
+			}
+
+			return next(c)
+		}
+	}
+}
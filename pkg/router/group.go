@@ -0,0 +1,123 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neko-neko/echo-logrus/v2/log"
+)
+
+// Authorizer returns the middlewares that check that the client of a request has the scope.
+type Authorizer func(scope string) []echo.MiddlewareFunc
+
+// Group registers routes with a common prefix and common middlewares, like the routes of a version of the api. Nested
+// groups inherit the prefix, the middlewares, the authorizer and the deprecation of their parent.
+type Group struct {
+	router      *Router
+	prefix      string
+	middlewares []echo.MiddlewareFunc
+	authorizer  Authorizer
+	deprecation *Deprecation
+}
+
+// Group returns the nested group with the prefix, its middlewares run after the middlewares of this group.
+func (g *Group) Group(prefix string, middlewares ...echo.MiddlewareFunc) *Group {
+	return &Group{
+		router:      g.router,
+		prefix:      joinPaths(g.prefix, prefix),
+		middlewares: append(append([]echo.MiddlewareFunc{}, g.middlewares...), middlewares...),
+		authorizer:  g.authorizer,
+		deprecation: g.deprecation,
+	}
+}
+
+// Authorize sets the authorizer of the routes of the group that have a scope, routes with a scope can't be registered
+// in groups without authorizer.
+func (g *Group) Authorize(authorizer Authorizer) *Group {
+	g.authorizer = authorizer
+	return g
+}
+
+// Deprecate marks all routes of the group as deprecated.
+func (g *Group) Deprecate(deprecation Deprecation) *Group {
+	g.deprecation = &deprecation
+	return g
+}
+
+// RegisterRoute registers the route with the path relative to the prefix of the group. The deprecation headers are
+// set first, then the middlewares of the group, the authorizer and the route run.
+func (g *Group) RegisterRoute(route Route) error {
+	path := joinPaths(g.prefix, route.Path)
+
+	var middlewares []echo.MiddlewareFunc
+	deprecation := g.deprecation
+	if route.Deprecation != nil {
+		deprecation = route.Deprecation
+	}
+	if deprecation != nil {
+		middlewares = append(middlewares, deprecation.Middleware())
+	}
+	middlewares = append(middlewares, g.middlewares...)
+	if route.Scope != "" {
+		if g.authorizer == nil {
+			return fmt.Errorf("route %s %s needs the scope %s, but its group has no authorizer", route.Method, path, route.Scope)
+		}
+		middlewares = append(middlewares, g.authorizer(route.Scope)...)
+	}
+	middlewares = append(middlewares, route.Middlewares...)
+
+	switch route.Method {
+	case http.MethodGet:
+		g.router.echoRouter.GET(path, route.HandlerFunc, middlewares...)
+	case http.MethodPost:
+		g.router.echoRouter.POST(path, route.HandlerFunc, middlewares...)
+	case http.MethodPut:
+		g.router.echoRouter.PUT(path, route.HandlerFunc, middlewares...)
+	case http.MethodDelete:
+		g.router.echoRouter.DELETE(path, route.HandlerFunc, middlewares...)
+	case http.MethodPatch:
+		g.router.echoRouter.PATCH(path, route.HandlerFunc, middlewares...)
+	case http.MethodHead:
+		g.router.echoRouter.HEAD(path, route.HandlerFunc, middlewares...)
+	case http.MethodOptions:
+		g.router.echoRouter.OPTIONS(path, route.HandlerFunc, middlewares...)
+	default:
+		return fmt.Errorf("invalid http Method %s", route.Method)
+	}
+
+	g.router.routes = append(g.router.routes, newRouteInfo(route.Method, path, route.Scope, middlewares, deprecation))
+	log.Logger().Infof("registered route %s %s", route.Method, path)
+	return nil
+}
+
+// Mount sets up the routes of the apis and registers them in the group.
+func (g *Group) Mount(apis ...Api) error {
+	for _, api := range apis {
+		api.SetupRoutes()
+		for _, route := range api.GetRoutes() {
+			if err := g.RegisterRoute(route); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// joinPaths joins the prefix and the path with exactly one slash between them and a leading slash.
+func joinPaths(prefix string, path string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if joined := prefix + path; joined != "" {
+		if !strings.HasPrefix(joined, "/") {
+			return "/" + joined
+		}
+		return joined
+	}
+
+	return "/"
+}
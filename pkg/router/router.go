@@ -3,12 +3,14 @@ package router
 import (
 	"context"
 	"fmt"
-	"net/http"
 
 	"github.com/labstack/echo/v4"
 	echoMiddleware "github.com/labstack/echo/v4/middleware"
-	logrusMiddleware "github.com/neko-neko/echo-logrus/v2"
 	"github.com/neko-neko/echo-logrus/v2/log"
+
+	logger "github.com/cafo13/animal-facts/pkg/log"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/tracing"
 )
 
 type Route struct {
@@ -16,44 +18,85 @@ type Route struct {
 	Path        string
 	HandlerFunc func(c echo.Context) error
 	Middlewares []echo.MiddlewareFunc
+	// Scope is the scope clients need for the route, the middlewares checking it are added by the Authorizer of the
+	// group the route is registered in.
+	Scope string
+	// Deprecation marks the route as deprecated, it overrides the deprecation of the group of the route.
+	Deprecation *Deprecation
+}
+
+// Api is a set of routes that are mounted together, like the routes of a resource.
+type Api interface {
+	SetupRoutes()
+	GetRoutes() []Route
 }
 
 type Router struct {
 	echoRouter *echo.Echo
+	// root is the group of the routes without prefix
+	root   *Group
+	routes []RouteInfo
 }
 
-func NewRouter() *Router {
+// NewRouter returns the router of the service, it traces every request as span of the service and logs it with the IDs
+// of the trace and the span. Errors of handlers are responded as problem details, see problem.HTTPErrorHandler.
+func NewRouter(service string) *Router {
 	echoRouter := echo.New()
 	echoRouter.Logger = log.Logger()
-	echoRouter.Use(logrusMiddleware.Logger())
+	echoRouter.HTTPErrorHandler = problem.HTTPErrorHandler
+	echoRouter.Use(echoMiddleware.RequestID())
+	echoRouter.Use(tracing.Middleware(service))
+	echoRouter.Use(logger.RequestLogger())
 	echoRouter.Use(echoMiddleware.CORS())
 	echoRouter.Use(echoMiddleware.Recover())
-	return &Router{echoRouter}
+
+	router := &Router{echoRouter: echoRouter}
+	router.root = &Group{router: router}
+	return router
+}
+
+// RenderProblems responds the errors of handlers as problem details written by render, e.g. in the negotiated format.
+func (r *Router) RenderProblems(render problem.RenderFunc) *Router {
+	r.echoRouter.HTTPErrorHandler = problem.NewHTTPErrorHandler(render)
+	return r
+}
+
+// Use registers middlewares that run for all routes, after the route of the request is found.
+func (r *Router) Use(middlewares ...echo.MiddlewareFunc) {
+	r.echoRouter.Use(middlewares...)
 }
 
-func (r Router) RegisterRoute(route Route) error {
-	switch route.Method {
-	case http.MethodGet:
-		r.echoRouter.GET(route.Path, route.HandlerFunc, route.Middlewares...)
-		return nil
-	case http.MethodPost:
-		r.echoRouter.POST(route.Path, route.HandlerFunc, route.Middlewares...)
-		return nil
-	case http.MethodPut:
-		r.echoRouter.PUT(route.Path, route.HandlerFunc, route.Middlewares...)
-		return nil
-	case http.MethodDelete:
-		r.echoRouter.DELETE(route.Path, route.HandlerFunc, route.Middlewares...)
-		return nil
-	}
+// Authorize sets the authorizer of the routes without prefix, groups created afterwards inherit it.
+func (r *Router) Authorize(authorizer Authorizer) *Router {
+	r.root.Authorize(authorizer)
+	return r
+}
+
+// Group returns the group of routes with the prefix and the middlewares.
+func (r *Router) Group(prefix string, middlewares ...echo.MiddlewareFunc) *Group {
+	return r.root.Group(prefix, middlewares...)
+}
+
+// Version returns the group of the routes of the version of the api, which are mounted at /api/<version>, so that
+// versions are served side by side.
+func (r *Router) Version(version string, middlewares ...echo.MiddlewareFunc) *Group {
+	return r.root.Group("/api/"+version, middlewares...)
+}
+
+// RegisterRoute registers the route without prefix.
+func (r *Router) RegisterRoute(route Route) error {
+	return r.root.RegisterRoute(route)
+}
 
-	return fmt.Errorf("invalid http Method %s", route.Method)
+// Mount registers the routes of the apis without prefix.
+func (r *Router) Mount(apis ...Api) error {
+	return r.root.Mount(apis...)
 }
 
-func (r Router) Run(port int) error {
+func (r *Router) Run(port int) error {
 	return r.echoRouter.Start(fmt.Sprintf(":%v", port))
 }
 
-func (r Router) Shutdown(ctx context.Context) error {
+func (r *Router) Shutdown(ctx context.Context) error {
 	return r.echoRouter.Shutdown(ctx)
 }
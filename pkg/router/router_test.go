@@ -0,0 +1,213 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type testApi struct {
+	routes []Route
+}
+
+func (a *testApi) SetupRoutes() {}
+
+func (a *testApi) GetRoutes() []Route {
+	return a.routes
+}
+
+// tag returns a middleware that appends the name to the X-Chain header, to check the order of the middlewares.
+func tag(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Add("X-Chain", name)
+			return next(c)
+		}
+	}
+}
+
+func requireHeader(required string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("X-Scope") != required {
+				return echo.NewHTTPError(http.StatusForbidden)
+			}
+			return next(c)
+		}
+	}
+}
+
+func ok(c echo.Context) error {
+	return c.String(http.StatusOK, c.Path())
+}
+
+func TestRouter_Versions(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	r := NewRouter("test")
+	r.Authorize(func(scope string) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{tag("authorizer"), requireHeader(scope)}
+	})
+	err := r.Version("v1", tag("v1")).
+		Deprecate(Deprecation{Since: since, Sunset: sunset, Link: "https://example.com/migration"}).
+		Mount(&testApi{routes: []Route{
+			{Method: http.MethodGet, Path: "/facts", HandlerFunc: ok, Middlewares: []echo.MiddlewareFunc{tag("route")}},
+			{Method: http.MethodPost, Path: "facts", HandlerFunc: ok, Scope: "create:fact"},
+		}})
+	if err != nil {
+		t.Fatalf("Mount() v1 unexpected error = %v", err)
+	}
+	err = r.Version("v2", tag("v2")).Mount(&testApi{routes: []Route{
+		{Method: http.MethodGet, Path: "/facts", HandlerFunc: ok, Middlewares: []echo.MiddlewareFunc{tag("route")}},
+	}})
+	if err != nil {
+		t.Fatalf("Mount() v2 unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		scope           string
+		wantStatus      int
+		wantChain       []string
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{
+			name:            "deprecated version",
+			method:          http.MethodGet,
+			path:            "/api/v1/facts",
+			wantStatus:      http.StatusOK,
+			wantChain:       []string{"v1", "route"},
+			wantDeprecation: "@1767225600",
+			wantSunset:      "Thu, 31 Dec 2026 00:00:00 GMT",
+		},
+		{
+			name:       "next version",
+			method:     http.MethodGet,
+			path:       "/api/v2/facts",
+			wantStatus: http.StatusOK,
+			wantChain:  []string{"v2", "route"},
+		},
+		{
+			name:            "route with scope",
+			method:          http.MethodPost,
+			path:            "/api/v1/facts",
+			scope:           "create:fact",
+			wantStatus:      http.StatusOK,
+			wantChain:       []string{"v1", "authorizer"},
+			wantDeprecation: "@1767225600",
+			wantSunset:      "Thu, 31 Dec 2026 00:00:00 GMT",
+		},
+		{
+			name:            "route without the scope",
+			method:          http.MethodPost,
+			path:            "/api/v1/facts",
+			wantStatus:      http.StatusForbidden,
+			wantChain:       []string{"v1", "authorizer"},
+			wantDeprecation: "@1767225600",
+			wantSunset:      "Thu, 31 Dec 2026 00:00:00 GMT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			request.Header.Set("X-Scope", tt.scope)
+			recorder := httptest.NewRecorder()
+			r.echoRouter.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if chain := recorder.Header().Values("X-Chain"); !reflect.DeepEqual(chain, tt.wantChain) {
+				t.Errorf("middlewares = %v, want %v", chain, tt.wantChain)
+			}
+			if deprecation := recorder.Header().Get(HeaderDeprecation); deprecation != tt.wantDeprecation {
+				t.Errorf("Deprecation = %q, want %q", deprecation, tt.wantDeprecation)
+			}
+			if sunset := recorder.Header().Get(HeaderSunset); sunset != tt.wantSunset {
+				t.Errorf("Sunset = %q, want %q", sunset, tt.wantSunset)
+			}
+			if link := recorder.Header().Get("Link"); tt.wantDeprecation != "" && !strings.Contains(link, `rel="deprecation"`) {
+				t.Errorf("Link = %q, want the deprecation link", link)
+			}
+		})
+	}
+}
+
+func TestRouter_RouteDeprecation(t *testing.T) {
+	r := NewRouter("test")
+	err := r.Mount(&testApi{routes: []Route{
+		{Method: http.MethodGet, Path: "/old", HandlerFunc: ok, Deprecation: &Deprecation{Since: time.Unix(1700000000, 0)}},
+		{Method: http.MethodGet, Path: "/new", HandlerFunc: ok},
+	}})
+	if err != nil {
+		t.Fatalf("Mount() unexpected error = %v", err)
+	}
+
+	for path, want := range map[string]string{"/old": "@1700000000", "/new": ""} {
+		recorder := httptest.NewRecorder()
+		r.echoRouter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if deprecation := recorder.Header().Get(HeaderDeprecation); deprecation != want {
+			t.Errorf("%s Deprecation = %q, want %q", path, deprecation, want)
+		}
+		if sunset := recorder.Header().Get(HeaderSunset); sunset != "" {
+			t.Errorf("%s Sunset = %q, want none", path, sunset)
+		}
+	}
+}
+
+func TestRouter_RegisterRouteErrors(t *testing.T) {
+	r := NewRouter("test")
+	if err := r.Version("v1").RegisterRoute(Route{Method: http.MethodGet, Path: "/facts", HandlerFunc: ok, Scope: "get:fact"}); err == nil {
+		t.Errorf("RegisterRoute() with scope but without authorizer, want error")
+	}
+	if err := r.RegisterRoute(Route{Method: "CONNECT", Path: "/facts", HandlerFunc: ok}); err == nil {
+		t.Errorf("RegisterRoute() with invalid method, want error")
+	}
+	if routes := r.Routes(); len(routes) != 0 {
+		t.Errorf("Routes() = %v, want no routes", routes)
+	}
+}
+
+func TestRouter_Routes(t *testing.T) {
+	r := NewRouter("test")
+	r.Authorize(func(scope string) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{requireHeader(scope)}
+	})
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	err := r.Version("v1", tag("v1")).Deprecate(Deprecation{Sunset: sunset}).Mount(&testApi{routes: []Route{
+		{Method: http.MethodPost, Path: "/facts", HandlerFunc: ok, Scope: "create:fact"},
+		{Method: http.MethodGet, Path: "/facts", HandlerFunc: ok},
+	}})
+	if err != nil {
+		t.Fatalf("Mount() unexpected error = %v", err)
+	}
+	if err := r.RegisterRoute(Route{Method: http.MethodGet, Path: "/livez", HandlerFunc: ok}); err != nil {
+		t.Fatalf("RegisterRoute() unexpected error = %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	r.RoutesHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+	var got []RouteInfo
+	if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+		t.Fatalf("routes = %s, want JSON: %v", recorder.Body.String(), err)
+	}
+
+	want := []RouteInfo{
+		{Method: http.MethodGet, Path: "/api/v1/facts", Middlewares: []string{"router.Deprecation.Middleware", "router.tag"}, Deprecated: true, Sunset: &sunset},
+		{Method: http.MethodPost, Path: "/api/v1/facts", Middlewares: []string{"router.Deprecation.Middleware", "router.tag", "router.requireHeader"}, Scope: "create:fact", Deprecated: true, Sunset: &sunset},
+		{Method: http.MethodGet, Path: "/livez", Middlewares: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %s, want %+v", recorder.Body.String(), want)
+	}
+}
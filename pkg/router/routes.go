@@ -0,0 +1,80 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RouteInfo describes a registered route for the route listing.
+type RouteInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Middlewares are the names of the middlewares of the route in the order they run, without the global middlewares
+	// of the router
+	Middlewares []string   `json:"middlewares"`
+	Scope       string     `json:"scope,omitempty"`
+	Deprecated  bool       `json:"deprecated"`
+	Sunset      *time.Time `json:"sunset,omitempty"`
+}
+
+// closureSuffix matches the suffix of the names of closures and method values, e.g. .func1.2, .1 of inlined closures or
+// -fm
+var closureSuffix = regexp.MustCompile(`(\.func\d+|\.\d+)+$|-fm$`)
+
+func newRouteInfo(method string, path string, scope string, middlewares []echo.MiddlewareFunc, deprecation *Deprecation) RouteInfo {
+	info := RouteInfo{
+		Method:      method,
+		Path:        path,
+		Middlewares: make([]string, 0, len(middlewares)),
+		Scope:       scope,
+		Deprecated:  deprecation != nil,
+	}
+	for _, middleware := range middlewares {
+		info.Middlewares = append(info.Middlewares, middlewareName(middleware))
+	}
+	if deprecation != nil && !deprecation.Sunset.IsZero() {
+		sunset := deprecation.Sunset.UTC()
+		info.Sunset = &sunset
+	}
+
+	return info
+}
+
+// middlewareName returns the name of the function that returned the middleware, like middleware.VerifyScope.
+func middlewareName(middleware echo.MiddlewareFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+
+	return closureSuffix.ReplaceAllString(name, "")
+}
+
+// Routes returns the registered routes sorted by path and method.
+func (r *Router) Routes() []RouteInfo {
+	routes := make([]RouteInfo, len(r.routes))
+	copy(routes, r.routes)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// RoutesHandler responds with the registered routes as JSON, it is meant for debugging and must not be exposed publicly.
+func (r *Router) RoutesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		_ = json.NewEncoder(w).Encode(r.Routes())
+	})
+}
@@ -2,19 +2,39 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"github.com/cafo13/animal-facts/pkg/router"
 	"github.com/pkg/errors"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 )
 
+// Worker is a background task that runs alongside the router until the context of the service is done.
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
 type Service struct {
-	router *router.Router
+	router      *router.Router
+	workers     []Worker
+	drainPeriod time.Duration
+
+	mutex          sync.Mutex
+	stoppedWorkers []string
 }
 
-func NewService(router *router.Router) *Service {
-	return &Service{router}
+func NewService(router *router.Router, workers ...Worker) *Service {
+	return &Service{router: router, workers: workers}
+}
+
+// SetDrainPeriod sets the time the router keeps serving requests after the service starts shutting down, so that load
+// balancers notice that the service is not ready anymore and stop sending requests before the router stops.
+func (s *Service) SetDrainPeriod(drainPeriod time.Duration) {
+	s.drainPeriod = drainPeriod
 }
 
 func (s *Service) Run(ctx context.Context, port int) error {
@@ -30,10 +50,40 @@ func (s *Service) Run(ctx context.Context, port int) error {
 		return nil
 	})
 
+	for _, worker := range s.workers {
+		errgrp.Go(func() error {
+			err := worker.Run(ctx)
+			if ctx.Err() == nil {
+				s.workerStopped(worker)
+			}
+			return err
+		})
+	}
+
 	errgrp.Go(func() error {
 		<-ctx.Done()
+		time.Sleep(s.drainPeriod)
 		return s.router.Shutdown(context.Background())
 	})
 
 	return errgrp.Wait()
 }
+
+func (s *Service) workerStopped(worker Worker) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stoppedWorkers = append(s.stoppedWorkers, fmt.Sprintf("%T", worker))
+}
+
+// CheckWorkers fails if workers stopped before the service started shutting down, it is a health check.
+func (s *Service) CheckWorkers(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.stoppedWorkers) > 0 {
+		return fmt.Errorf("workers stopped: %s", strings.Join(s.stoppedWorkers, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package shuffle
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	tokenVersion   = 1
+	payloadLength  = 1 + 8 + 8 + 1
+	signatureBytes = 16
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid shuffle token")
+)
+
+// Token is the position of a client in a seeded permutation of all facts.
+//
+// Instead of an index into a list, which would shift whenever facts are added or removed, every fact gets a sort key
+// derived from the seed and its ID. The token stores the key of the last served fact, so the next fact is the one with
+// the next higher key. Facts added during a cycle are served in that cycle if their key is still ahead of the cursor,
+// otherwise in the next one, and removed facts are simply skipped. Once all keys are passed, a new cycle with a new
+// seed starts.
+type Token struct {
+	Seed    uint64
+	Cursor  uint64
+	Started bool
+}
+
+// NewToken returns a token at the start of a permutation with a random seed.
+func NewToken() (Token, error) {
+	var seed [8]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return Token{}, errors.Wrap(err, "failed to generate shuffle seed")
+	}
+
+	return Token{Seed: binary.BigEndian.Uint64(seed[:])}, nil
+}
+
+// Next returns the next ID of the permutation and the token pointing to it. ids must not be empty.
+func (t Token) Next(ids []primitive.ObjectID) (primitive.ObjectID, Token) {
+	if id, key, found := t.next(ids); found {
+		return id, Token{Seed: t.Seed, Cursor: key, Started: true}
+	}
+
+	nextCycle := Token{Seed: mix(t.Seed)}
+	id, key, _ := nextCycle.next(ids)
+	return id, Token{Seed: nextCycle.Seed, Cursor: key, Started: true}
+}
+
+func (t Token) next(ids []primitive.ObjectID) (primitive.ObjectID, uint64, bool) {
+	var nextID primitive.ObjectID
+	var nextKey uint64
+	found := false
+	for _, id := range ids {
+		key := sortKey(t.Seed, id)
+		if t.Started && key <= t.Cursor {
+			continue
+		}
+		if !found || key < nextKey || (key == nextKey && bytes.Compare(id[:], nextID[:]) < 0) {
+			nextID, nextKey, found = id, key, true
+		}
+	}
+
+	return nextID, nextKey, found
+}
+
+func sortKey(seed uint64, id primitive.ObjectID) uint64 {
+	var seedBytes [8]byte
+	binary.BigEndian.PutUint64(seedBytes[:], seed)
+	hash := sha256.New()
+	hash.Write(seedBytes[:])
+	hash.Write(id[:])
+	return binary.BigEndian.Uint64(hash.Sum(nil))
+}
+
+// mix derives the seed of the next cycle, so that every cycle has a different order.
+func mix(seed uint64) uint64 {
+	seed += 0x9e3779b97f4a7c15
+	seed = (seed ^ (seed >> 30)) * 0xbf58476d1ce4e5b9
+	seed = (seed ^ (seed >> 27)) * 0x94d049bb133111eb
+	return seed ^ (seed >> 31)
+}
+
+// Codec encodes tokens to opaque strings signed with HMAC-SHA256, so that clients can't forge positions.
+type Codec struct {
+	secret []byte
+}
+
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret}
+}
+
+func (c *Codec) Encode(token Token) string {
+	payload := make([]byte, payloadLength)
+	payload[0] = tokenVersion
+	binary.BigEndian.PutUint64(payload[1:9], token.Seed)
+	binary.BigEndian.PutUint64(payload[9:17], token.Cursor)
+	if token.Started {
+		payload[17] = 1
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+func (c *Codec) Decode(encodedToken string) (Token, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(encodedToken, ".")
+	if !found {
+		return Token{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != payloadLength || payload[0] != tokenVersion {
+		return Token{}, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return Token{}, ErrInvalidToken
+	}
+
+	return Token{
+		Seed:    binary.BigEndian.Uint64(payload[1:9]),
+		Cursor:  binary.BigEndian.Uint64(payload[9:17]),
+		Started: payload[17] == 1,
+	}, nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)[:signatureBytes]
+}
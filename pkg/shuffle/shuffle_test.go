@@ -0,0 +1,98 @@
+package shuffle_test
+
+import (
+	"testing"
+
+	"github.com/cafo13/animal-facts/pkg/shuffle"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newIDs(count int) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, count)
+	for i := range ids {
+		ids[i] = primitive.NewObjectID()
+	}
+	return ids
+}
+
+func TestToken_Next(t *testing.T) {
+	t.Run("walks all ids without repeats before cycling", func(t *testing.T) {
+		ids := newIDs(20)
+		token := shuffle.Token{Seed: 42}
+
+		for cycle := 0; cycle < 3; cycle++ {
+			seen := map[primitive.ObjectID]bool{}
+			for i := 0; i < len(ids); i++ {
+				var id primitive.ObjectID
+				id, token = token.Next(ids)
+				if seen[id] {
+					t.Errorf("Next() returned %v twice in cycle %d", id, cycle)
+					return
+				}
+				seen[id] = true
+			}
+		}
+	})
+
+	t.Run("stays without repeats when ids are added and removed", func(t *testing.T) {
+		ids := newIDs(10)
+		token := shuffle.Token{Seed: 7}
+
+		seen := map[primitive.ObjectID]bool{}
+		var id primitive.ObjectID
+		for i := 0; i < 5; i++ {
+			id, token = token.Next(ids)
+			seen[id] = true
+		}
+
+		ids = append(ids[1:], newIDs(5)...)
+		for {
+			startedSeed := token.Seed
+			id, token = token.Next(ids)
+			if token.Seed != startedSeed {
+				break
+			}
+			if seen[id] {
+				t.Errorf("Next() returned %v twice in the same cycle", id)
+				return
+			}
+			seen[id] = true
+		}
+	})
+
+	t.Run("different seeds give different orders", func(t *testing.T) {
+		ids := newIDs(20)
+		first, _ := shuffle.Token{Seed: 1}.Next(ids)
+		differs := false
+		for seed := uint64(2); seed < 10; seed++ {
+			if id, _ := (shuffle.Token{Seed: seed}).Next(ids); id != first {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Errorf("Next() returned the same first id for all seeds")
+		}
+	})
+}
+
+func TestCodec(t *testing.T) {
+	codec := shuffle.NewCodec([]byte("secret"))
+	token := shuffle.Token{Seed: 123456789, Cursor: 987654321, Started: true}
+
+	decoded, err := codec.Decode(codec.Encode(token))
+	if err != nil {
+		t.Errorf("Decode() unexpected error = %v", err)
+		return
+	}
+	if decoded != token {
+		t.Errorf("Decode() got = %v, want %v", decoded, token)
+	}
+
+	if _, err := shuffle.NewCodec([]byte("other secret")).Decode(codec.Encode(token)); err == nil {
+		t.Errorf("Decode() expected error for token signed with other secret")
+	}
+
+	if _, err := codec.Decode("not-a-token"); err == nil {
+		t.Errorf("Decode() expected error for malformed token")
+	}
+}
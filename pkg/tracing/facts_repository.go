@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+// TracingFactsRepository starts a span for every operation of the facts repository it decorates, as child of the span
+// of its context. The decorated repository runs the operation with the context of the span, so that e.g. the mongo db
+// commands of the operation are children of the span.
+type TracingFactsRepository struct {
+	next repository.FactsRepository
+	ctx  context.Context
+}
+
+func NewTracingFactsRepository(next repository.FactsRepository) repository.FactsRepository {
+	return &TracingFactsRepository{next: next, ctx: context.Background()}
+}
+
+func (t *TracingFactsRepository) start(operation string, attributes ...attribute.KeyValue) (repository.FactsRepository, trace.Span) {
+	attributes = append(attributes, attribute.String("repository.operation", operation))
+	ctx, span := Start(t.ctx, "FactsRepository."+operation, attributes...)
+	return t.next.WithContext(ctx), span
+}
+
+func (t *TracingFactsRepository) WithContext(ctx context.Context) repository.FactsRepository {
+	return &TracingFactsRepository{next: t.next, ctx: ctx}
+}
+
+func (t *TracingFactsRepository) Create(fact *repository.Fact) error {
+	next, span := t.start("Create", attribute.String("fact.id", fact.ID.Hex()))
+	err := next.Create(fact)
+	End(span, err)
+	return err
+}
+
+func (t *TracingFactsRepository) ReadOne(id primitive.ObjectID) (*repository.Fact, error) {
+	next, span := t.start("ReadOne", attribute.String("fact.id", id.Hex()))
+	result, err := next.ReadOne(id)
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) ReadMany(ids []primitive.ObjectID) ([]*repository.Fact, error) {
+	next, span := t.start("ReadMany", attribute.Int("facts.requested", len(ids)))
+	result, err := next.ReadMany(ids)
+	span.SetAttributes(attribute.Int("facts.count", len(result)))
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) ReadManyIDs(filterFunc func(fact *repository.Fact) bool) ([]primitive.ObjectID, error) {
+	next, span := t.start("ReadManyIDs")
+	result, err := next.ReadManyIDs(filterFunc)
+	span.SetAttributes(attribute.Int("facts.count", len(result)))
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) ReadAll() ([]*repository.Fact, error) {
+	next, span := t.start("ReadAll")
+	result, err := next.ReadAll()
+	span.SetAttributes(attribute.Int("facts.count", len(result)))
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) ReadPage(query repository.FactsPageQuery) ([]*repository.Fact, error) {
+	next, span := t.start("ReadPage", attribute.Int("facts.limit", query.Limit))
+	result, err := next.ReadPage(query)
+	span.SetAttributes(attribute.Int("facts.count", len(result)))
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) ReadRecentlyApproved(query repository.RecentlyApprovedQuery) ([]*repository.Fact, error) {
+	next, span := t.start("ReadRecentlyApproved", attribute.Int("facts.limit", query.Limit))
+	result, err := next.ReadRecentlyApproved(query)
+	span.SetAttributes(attribute.Int("facts.count", len(result)))
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) ReadRelated(query repository.RelatedFactsQuery) ([]*repository.Fact, error) {
+	next, span := t.start("ReadRelated", attribute.Int("facts.animals", len(query.Animals)), attribute.Int("facts.tags", len(query.Tags)))
+	result, err := next.ReadRelated(query)
+	span.SetAttributes(attribute.Int("facts.count", len(result)))
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) ReadLastRemoval() (time.Time, error) {
+	next, span := t.start("ReadLastRemoval")
+	result, err := next.ReadLastRemoval()
+	End(span, err)
+	return result, err
+}
+
+func (t *TracingFactsRepository) Update(id primitive.ObjectID, updateFunc func(fact *repository.Fact) (*repository.Fact, error)) error {
+	next, span := t.start("Update", attribute.String("fact.id", id.Hex()))
+	err := next.Update(id, updateFunc)
+	End(span, err)
+	return err
+}
+
+func (t *TracingFactsRepository) Delete(id primitive.ObjectID) error {
+	next, span := t.start("Delete", attribute.String("fact.id", id.Hex()))
+	err := next.Delete(id)
+	End(span, err)
+	return err
+}
+
+func (t *TracingFactsRepository) Count() (int, error) {
+	next, span := t.start("Count")
+	result, err := next.Count()
+	End(span, err)
+	return result, err
+}
+
+// Ping traces the ping as part of the context of the ping, not of the context of the repository.
+func (t *TracingFactsRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "FactsRepository.Ping", attribute.String("repository.operation", "Ping"))
+	err := t.next.Ping(ctx)
+	End(span, err)
+	return err
+}
+
+func (t *TracingFactsRepository) Close() error {
+	return t.next.Close()
+}
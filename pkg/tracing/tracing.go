@@ -0,0 +1,138 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	instrumentationName = "github.com/cafo13/animal-facts"
+
+	// shutdownTimeout is the time the provider has to export the remaining spans when the service stops.
+	shutdownTimeout = 5 * time.Second
+)
+
+type Exporter string
+
+const (
+	// ExporterNone doesn't export spans, the trace context of requests is still propagated, e.g. into the logs.
+	ExporterNone Exporter = "none"
+	// ExporterOTLP exports spans with OTLP over gRPC, it is configured with the OTEL_EXPORTER_OTLP_* environment
+	// variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT.
+	ExporterOTLP Exporter = "otlp"
+	// ExporterStdout writes spans to stdout, e.g. for local development.
+	ExporterStdout Exporter = "stdout"
+)
+
+// ParseExporter parses the name of an exporter, e.g. otlp.
+func ParseExporter(name string) (Exporter, error) {
+	switch exporter := Exporter(name); exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+		return exporter, nil
+	}
+
+	return "", fmt.Errorf("tracing exporter '%s' is not valid, valid exporters are %s, %s and %s", name, ExporterNone, ExporterOTLP, ExporterStdout)
+}
+
+// Provider is the global tracer provider of the service, it is a worker that exports the remaining spans when the
+// service stops.
+type Provider struct {
+	tracerProvider *sdktrace.TracerProvider
+}
+
+// NewProvider sets the global tracer provider exporting the spans of the service with the exporter, and the W3C trace
+// context and baggage as propagators of incoming and outgoing requests.
+func NewProvider(ctx context.Context, exporter Exporter, service string, version string) (*Provider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case ExporterNone:
+		return &Provider{}, nil
+	case ExporterOTLP:
+		spanExporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("tracing exporter '%s' is not valid", exporter)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s tracing exporter", exporter)
+	}
+
+	serviceResource, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tracing resource")
+	}
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(serviceResource))
+	otel.SetTracerProvider(tracerProvider)
+	log.Logger().Infof("exporting traces with %s exporter", exporter)
+
+	return &Provider{tracerProvider}, nil
+}
+
+func (p *Provider) Run(ctx context.Context) error {
+	<-ctx.Done()
+	if p.tracerProvider == nil {
+		return nil
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := p.tracerProvider.Shutdown(shutdownCtx); err != nil {
+		return errors.Wrap(err, "failed to shut down tracer provider")
+	}
+
+	return nil
+}
+
+// Middleware starts a span for every request, named by the route of the request. The span continues the trace of the
+// W3C trace context of the request and is in the context of the request for the handlers.
+func Middleware(service string) echo.MiddlewareFunc {
+	return otelecho.Middleware(service)
+}
+
+// NewCommandMonitor traces the commands sent to mongo db, the spans are children of the span of the context of the
+// command.
+func NewCommandMonitor() *event.CommandMonitor {
+	return otelmongo.NewMonitor()
+}
+
+// Start starts a span as child of the span of the context, the span has to be ended.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records the error in the span, unless it is nil or a fact that is not found, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	if _, err := NewProvider(context.Background(), ExporterNone, "test-api", "1.2.3"); err != nil {
+		t.Fatalf("NewProvider() unexpected error = %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+
+	return recorder
+}
+
+func TestParseExporter(t *testing.T) {
+	for _, name := range []string{"none", "otlp", "stdout"} {
+		if exporter, err := ParseExporter(name); err != nil || string(exporter) != name {
+			t.Errorf("ParseExporter(%s) = %s, %v", name, exporter, err)
+		}
+	}
+
+	if _, err := ParseExporter("jaeger"); err == nil {
+		t.Errorf("ParseExporter(jaeger) want error")
+	}
+}
+
+func TestTracingFactsRepository(t *testing.T) {
+	recorder := setupRecorder(t)
+	id := primitive.NewObjectID()
+	factsRepository := NewTracingFactsRepository(repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{
+		id: {ID: id, Fact: "Whales sing.", Approved: true},
+	}, false))
+
+	ctx, parent := Start(context.Background(), "request")
+	requestRepository := factsRepository.WithContext(ctx)
+	if _, err := requestRepository.ReadOne(id); err != nil {
+		t.Fatalf("ReadOne() unexpected error = %v", err)
+	}
+	if _, err := requestRepository.ReadOne(primitive.NewObjectID()); err == nil {
+		t.Fatalf("ReadOne() want not found error")
+	}
+	parent.End()
+
+	failingRepository := NewTracingFactsRepository(repository.NewMockFactsRepository(nil, true))
+	if _, err := failingRepository.Count(); err == nil {
+		t.Fatalf("Count() want error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d spans, want 4", len(spans))
+	}
+	for _, span := range spans[:2] {
+		if span.Name() != "FactsRepository.ReadOne" {
+			t.Errorf("span name = %s, want FactsRepository.ReadOne", span.Name())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the span of the context", span.Name())
+		}
+		if span.Status().Code == codes.Error {
+			t.Errorf("span %s has error status, facts that are not found are no errors", span.Name())
+		}
+	}
+	if count := spans[3]; count.Name() != "FactsRepository.Count" || count.Status().Code != codes.Error || count.Parent().IsValid() {
+		t.Errorf("span %s want error status without parent, got %v", count.Name(), count.Status())
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := setupRecorder(t)
+	e := echo.New()
+	e.Use(Middleware("test-api"))
+	var spanContext trace.SpanContext
+	e.GET("/facts/:id", func(c echo.Context) error {
+		spanContext = trace.SpanContextFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/facts/1", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	if spanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the trace ID of the traceparent header", spanContext.TraceID())
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "/facts/:id" || spans[0].Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("want span of the route as child of the span of the traceparent header, got %v", spans)
+	}
+}
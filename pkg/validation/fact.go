@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"regexp"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	MinFactLength   = 10
+	MaxFactLength   = 1000
+	MaxSourceLength = 2048
+	MaxAnimalLength = 100
+	MaxTags         = 20
+	MaxTagLength    = 50
+)
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// ValidateFact validates the content of a fact that is created or updated, by any api or by imports. The fact is
+// expected to be normalized like the facts handlers do, e.g. with trimmed text and lower case tags.
+func ValidateFact(fact *repository.Fact) error {
+	validator := &Validator{}
+	validator.String("fact", fact.Fact, NotBlank, Length(MinFactLength, MaxFactLength), NoControlCharacters)
+	validator.String("source", fact.Source, NotBlank, Length(1, MaxSourceLength), NoControlCharacters, URLOrDOI)
+	validator.String("animal", fact.Animal, Length(0, MaxAnimalLength), NoControlCharacters)
+	validator.Strings("tags", fact.Tags, MaxTags, Length(1, MaxTagLength), NoControlCharacters)
+	validator.String("language", fact.Language, Optional(Matches(languagePattern, "a language tag like en or pt-br")))
+
+	return validator.Err()
+}
@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DecodeJSON decodes the JSON of the body into the value and rejects unknown fields and values of the wrong type with
+// Errors, so that e.g. misspelled fields are not silently ignored. Other errors are errors of malformed JSON.
+func DecodeJSON(body io.Reader, value any) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(value)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return Errors{{Field: typeErr.Field, Code: CodeInvalidType, Message: "must be of type " + typeErr.Type.String()}}
+	} else if errors.Is(err, io.EOF) {
+		// an empty body is an empty object, its required fields are reported by the validation
+		return nil
+	} else if err != nil && strings.HasPrefix(err.Error(), "json: unknown field ") {
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return Errors{{Field: field, Code: CodeUnknownField, Message: "is not a known field"}}
+	} else if err != nil {
+		return errors.Wrap(err, "request body is not valid JSON")
+	}
+
+	return nil
+}
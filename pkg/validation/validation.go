@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the codes of the field errors, they are stable like the codes of the problem details
+const (
+	CodeRequired         = "required"
+	CodeTooShort         = "too_short"
+	CodeTooLong          = "too_long"
+	CodeTooMany          = "too_many"
+	CodeInvalidFormat    = "invalid_format"
+	CodeInvalidType      = "invalid_type"
+	CodeControlCharacter = "control_character"
+	CodeUnknownField     = "unknown_field"
+)
+
+// FieldError is the violation of a rule by the value of a field.
+type FieldError struct {
+	// Field is the JSON name of the field, items of lists have their index like tags[1].
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors are all field errors of a value, it is the error of a value that is not valid.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldError := range e {
+		messages = append(messages, fmt.Sprintf("%s: %s", fieldError.Field, fieldError.Message))
+	}
+
+	return "validation failed: " + strings.Join(messages, ", ")
+}
+
+// Rule checks a value, it returns the code and message of the violation or an empty code if the value is valid.
+type Rule func(value string) (code string, message string)
+
+// Validator collects the field errors of the fields of a value, only the first violated rule of a field is reported.
+type Validator struct {
+	errors Errors
+}
+
+func (v *Validator) String(field string, value string, rules ...Rule) {
+	for _, rule := range rules {
+		if code, message := rule(value); code != "" {
+			v.errors = append(v.errors, FieldError{Field: field, Code: code, Message: message})
+			return
+		}
+	}
+}
+
+// Strings checks the number of values and every value with the rules.
+func (v *Validator) Strings(field string, values []string, maxItems int, rules ...Rule) {
+	if len(values) > maxItems {
+		v.errors = append(v.errors, FieldError{Field: field, Code: CodeTooMany, Message: fmt.Sprintf("must not have more than %d items", maxItems)})
+		return
+	}
+
+	for i, value := range values {
+		v.String(fmt.Sprintf("%s[%d]", field, i), value, rules...)
+	}
+}
+
+// Err returns the collected field errors as Errors, nil if all fields are valid.
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return v.errors
+}
+
+// NotBlank requires a value that is not empty after trimming white space.
+func NotBlank(value string) (string, string) {
+	if strings.TrimSpace(value) == "" {
+		return CodeRequired, "must not be empty"
+	}
+
+	return "", ""
+}
+
+// Length requires a value with at least minLength and at most maxLength characters.
+func Length(minLength int, maxLength int) Rule {
+	return func(value string) (string, string) {
+		length := utf8.RuneCountInString(value)
+		if length < minLength {
+			return CodeTooShort, fmt.Sprintf("must have at least %d characters", minLength)
+		} else if length > maxLength {
+			return CodeTooLong, fmt.Sprintf("must not have more than %d characters", maxLength)
+		}
+
+		return "", ""
+	}
+}
+
+// NoControlCharacters forbids control characters like new lines and invalid UTF-8.
+func NoControlCharacters(value string) (string, string) {
+	if !utf8.ValidString(value) {
+		return CodeInvalidFormat, "must be valid UTF-8"
+	}
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return CodeControlCharacter, "must not contain control characters"
+	}
+
+	return "", ""
+}
+
+var doiPattern = regexp.MustCompile(`^(?i:doi:)?10\.\d{4,9}/\S+$`)
+
+// ParseDOI returns the DOI of the value without the doi: prefix, if the value is a DOI.
+func ParseDOI(value string) (string, bool) {
+	if !doiPattern.MatchString(value) {
+		return "", false
+	}
+
+	return value[strings.Index(value, "10."):], true
+}
+
+// URLOrDOI requires an absolute http or https URL or a DOI like 10.1000/182 or doi:10.1000/182.
+func URLOrDOI(value string) (string, string) {
+	if doiPattern.MatchString(value) {
+		return "", ""
+	}
+
+	parsedURL, err := url.Parse(value)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return CodeInvalidFormat, "must be an http or https URL or a DOI"
+	}
+
+	return "", ""
+}
+
+// Matches requires a value that matches the pattern, the description is part of the message.
+func Matches(pattern *regexp.Regexp, description string) Rule {
+	return func(value string) (string, string) {
+		if !pattern.MatchString(value) {
+			return CodeInvalidFormat, "must be " + description
+		}
+
+		return "", ""
+	}
+}
+
+// Optional applies the rules only to values that are not empty.
+func Optional(rules ...Rule) Rule {
+	return func(value string) (string, string) {
+		if value == "" {
+			return "", ""
+		}
+
+		for _, rule := range rules {
+			if code, message := rule(value); code != "" {
+				return code, message
+			}
+		}
+
+		return "", ""
+	}
+}
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+func validFact() *repository.Fact {
+	return &repository.Fact{
+		Fact:     "Octopuses have three hearts.",
+		Source:   "https://factanimal.com/octopus/",
+		Animal:   "octopus",
+		Tags:     []string{"ocean"},
+		Language: "en",
+	}
+}
+
+func TestValidateFact(t *testing.T) {
+	tests := []struct {
+		name       string
+		change     func(fact *repository.Fact)
+		wantErrors Errors
+	}{
+		{
+			name:   "valid fact",
+			change: func(fact *repository.Fact) {},
+		},
+		{
+			name:   "doi as source and regional language",
+			change: func(fact *repository.Fact) { fact.Source = "doi:10.1000/182"; fact.Language = "pt-br" },
+		},
+		{
+			name:       "blank fact",
+			change:     func(fact *repository.Fact) { fact.Fact = "   " },
+			wantErrors: Errors{{Field: "fact", Code: CodeRequired, Message: "must not be empty"}},
+		},
+		{
+			name:       "too long fact",
+			change:     func(fact *repository.Fact) { fact.Fact = strings.Repeat("a", MaxFactLength+1) },
+			wantErrors: Errors{{Field: "fact", Code: CodeTooLong, Message: "must not have more than 1000 characters"}},
+		},
+		{
+			name:       "control characters",
+			change:     func(fact *repository.Fact) { fact.Fact = "Octopuses have\x00three hearts." },
+			wantErrors: Errors{{Field: "fact", Code: CodeControlCharacter, Message: "must not contain control characters"}},
+		},
+		{
+			name: "source without scheme and invalid tags",
+			change: func(fact *repository.Fact) {
+				fact.Source = "factanimal.com/octopus/"
+				fact.Tags = []string{"ocean", strings.Repeat("a", MaxTagLength+1)}
+			},
+			wantErrors: Errors{
+				{Field: "source", Code: CodeInvalidFormat, Message: "must be an http or https URL or a DOI"},
+				{Field: "tags[1]", Code: CodeTooLong, Message: "must not have more than 50 characters"},
+			},
+		},
+		{
+			name:       "too many tags",
+			change:     func(fact *repository.Fact) { fact.Tags = make([]string, MaxTags+1) },
+			wantErrors: Errors{{Field: "tags", Code: CodeTooMany, Message: "must not have more than 20 items"}},
+		},
+		{
+			name:       "invalid language",
+			change:     func(fact *repository.Fact) { fact.Language = "english" },
+			wantErrors: Errors{{Field: "language", Code: CodeInvalidFormat, Message: "must be a language tag like en or pt-br"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := validFact()
+			tt.change(fact)
+
+			err := ValidateFact(fact)
+			var gotErrors Errors
+			if err != nil && !errors.As(err, &gotErrors) {
+				t.Fatalf("ValidateFact() error = %v, want Errors", err)
+			}
+			if !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("ValidateFact() = %v, want %v", gotErrors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	value := &struct {
+		Fact string `json:"fact"`
+	}{}
+
+	if err := DecodeJSON(strings.NewReader(`{"fact":"Octopuses have three hearts."}`), value); err != nil || value.Fact == "" {
+		t.Errorf("DecodeJSON() = %+v, %v, want the decoded value", value, err)
+	}
+	if err := DecodeJSON(strings.NewReader(""), value); err != nil {
+		t.Errorf("DecodeJSON() unexpected error = %v for an empty body", err)
+	}
+
+	err := DecodeJSON(strings.NewReader(`{"fact":"Octopuses have three hearts.","sorce":"https://factanimal.com/"}`), value)
+	wantErrors := Errors{{Field: "sorce", Code: CodeUnknownField, Message: "is not a known field"}}
+	if !reflect.DeepEqual(err, wantErrors) {
+		t.Errorf("DecodeJSON() error = %v, want %v", err, wantErrors)
+	}
+
+	var validationErrs Errors
+	if err := DecodeJSON(strings.NewReader(`{"fact":`), value); err == nil || errors.As(err, &validationErrs) {
+		t.Errorf("DecodeJSON() error = %v, want error of malformed JSON", err)
+	}
+}
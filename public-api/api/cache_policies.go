@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/cafo13/animal-facts/pkg/httpcache"
+)
+
+// DefaultCachePolicies are the Cache-Control policies of the public routes by route name, they can be overwritten with
+// the CACHE_CONTROL_POLICIES environment variable. Facts by ID change rarely and are validated with their ETag, random
+// facts must never be cached.
+var DefaultCachePolicies = httpcache.Policies{
+	"fact":   "public, max-age=3600, stale-while-revalidate=86400",
+	"count":  "public, max-age=60",
+	"list":   "public, max-age=60",
+	"random": httpcache.NoStore,
+	"card":   "public, max-age=86400",
+	// feed readers poll at most every 15 minutes without a conditional request
+	"feed":  "public, max-age=900",
+	"embed": "public, max-age=86400",
+	// the widget is only cached for an hour, so that changes of the widget get to the pages soon
+	"widget": "public, max-age=3600",
+}
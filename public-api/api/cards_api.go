@@ -0,0 +1,173 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/httpcache"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/public-api/card"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+var cardContentTypes = map[handler.CardFormat]string{
+	handler.CardFormatPNG: "image/png",
+	handler.CardFormatSVG: "image/svg+xml",
+}
+
+type CardsApi struct {
+	cardsApiRoutes []router.Route
+	cardsHandler   *handler.CardsHandler
+	factsHandler   *handler.FactsHandler
+	cachePolicies  httpcache.Policies
+}
+
+func NewCardsApi(cardsHandler *handler.CardsHandler, factsHandler *handler.FactsHandler, cachePolicies httpcache.Policies) *CardsApi {
+	return &CardsApi{cardsHandler: cardsHandler, factsHandler: factsHandler, cachePolicies: cachePolicies}
+}
+
+func (a *CardsApi) SetupRoutes() {
+	a.cardsApiRoutes = []router.Route{}
+	for _, format := range []handler.CardFormat{handler.CardFormatPNG, handler.CardFormatSVG} {
+		a.cardsApiRoutes = append(a.cardsApiRoutes,
+			router.Route{
+				Method:      "GET",
+				Path:        fmt.Sprintf("/facts/random/card.%s", format),
+				HandlerFunc: a.getRandomCard(format),
+				// the redirect is never cached, so that every request gets another fact, while the card itself is cacheable
+				Middlewares: []echo.MiddlewareFunc{a.cachePolicies.Middleware("random")},
+			},
+			router.Route{
+				Method:      "GET",
+				Path:        fmt.Sprintf("/facts/:id/card.%s", format),
+				HandlerFunc: a.getCard(format),
+				Middlewares: []echo.MiddlewareFunc{a.cachePolicies.Middleware("card")},
+			},
+		)
+	}
+}
+
+func (a *CardsApi) GetRoutes() []router.Route {
+	return a.cardsApiRoutes
+}
+
+// getCard
+//
+//	@Summary      gets fact card
+//	@Description  renders the fact with its source as PNG (card.png) or SVG (card.svg) image, cards can be cached by their ETag
+//	@Produce      png,image/svg+xml
+//	@Param        width   query  int     false  "width in pixels (300-2400, default 1200)"
+//	@Param        height  query  int     false  "height in pixels (150-2400, default 630)"
+//	@Param        theme   query  string  false  "light (default), dark or brand"
+//	@Success      200  {file}    binary
+//	@Success      304
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/:id/card.png [get]
+func (a *CardsApi) getCard(format handler.CardFormat) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Param("id")
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return problem.BadRequest("id from request path is not a valid object id in hex string format")
+		}
+
+		options, err := parseCardOptions(c.QueryParams())
+		if err != nil {
+			return problem.BadRequest(err.Error())
+		}
+
+		factCard, err := a.cardsHandler.GetCard(objID, format, options)
+		if errors.Is(err, handler.ErrNotFound) {
+			return problem.NotFound(fmt.Sprintf("fact with ID '%s' not found", id))
+		} else if err != nil {
+			return err
+		}
+
+		if httpcache.NotModified(c, factCard.ETag, factCard.LastModified) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		image, err := factCard.Render()
+		if err != nil {
+			return err
+		}
+
+		return c.Blob(http.StatusOK, cardContentTypes[format], image)
+	}
+}
+
+// getRandomCard
+//
+//	@Summary      gets random fact card
+//	@Description  redirects to the card of a random fact, the query parameters are passed on
+//	@Param        width   query  int     false  "width in pixels (300-2400, default 1200)"
+//	@Param        height  query  int     false  "height in pixels (150-2400, default 630)"
+//	@Param        theme   query  string  false  "light (default), dark or brand"
+//	@Success      302
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/random/card.png [get]
+func (a *CardsApi) getRandomCard(format handler.CardFormat) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, err := parseCardOptions(c.QueryParams()); err != nil {
+			return problem.BadRequest(err.Error())
+		}
+
+		fact, err := a.factsHandler.WithContext(c.Request().Context()).GetRandomApproved()
+		if err != nil {
+			return err
+		}
+
+		cardUrl := url.URL{
+			Path:     fmt.Sprintf("/%s/facts/%s/card.%s", basePathV1, fact.ID, format),
+			RawQuery: c.QueryString(),
+		}
+		return c.Redirect(http.StatusFound, cardUrl.String())
+	}
+}
+
+func parseCardOptions(query url.Values) (card.Options, error) {
+	options := card.Options{Width: card.DefaultWidth, Height: card.DefaultHeight, Theme: card.ThemeLight}
+
+	if width := query.Get("width"); width != "" {
+		var err error
+		options.Width, err = strconv.Atoi(width)
+		if err != nil || options.Width < card.MinWidth || options.Width > card.MaxWidth {
+			return options, errors.Errorf("width must be an integer between %d and %d", card.MinWidth, card.MaxWidth)
+		}
+	}
+
+	if height := query.Get("height"); height != "" {
+		var err error
+		options.Height, err = strconv.Atoi(height)
+		if err != nil || options.Height < card.MinHeight || options.Height > card.MaxHeight {
+			return options, errors.Errorf("height must be an integer between %d and %d", card.MinHeight, card.MaxHeight)
+		}
+	}
+
+	if theme := query.Get("theme"); theme != "" {
+		var themeNames []string
+		found := false
+		for _, t := range card.Themes {
+			themeNames = append(themeNames, t.Name)
+			if strings.EqualFold(t.Name, theme) {
+				options.Theme, found = t, true
+			}
+		}
+		if !found {
+			return options, errors.Errorf("theme '%s' is not valid, valid themes are %s", theme, strings.Join(themeNames, ", "))
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	echoSwagger "github.com/swaggo/echo-swagger"
+
+	"github.com/cafo13/animal-facts/pkg/router"
+	_ "github.com/cafo13/animal-facts/public-api/docs"
+	_ "github.com/cafo13/animal-facts/public-api/docs/v2"
+)
+
+// DocsApi serves the swagger documentation of the api, it is mounted without the prefix of a version. The docs of the
+// api v1 are at /swagger, the docs of the api v2 at /swagger/v2.
+type DocsApi struct {
+	docsApiRoutes []router.Route
+}
+
+func NewDocsApi() *DocsApi {
+	return &DocsApi{}
+}
+
+func (d *DocsApi) SetupRoutes() {
+	d.docsApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/swagger/*",
+			HandlerFunc: echoSwagger.WrapHandler,
+		},
+		{
+			Method:      "GET",
+			Path:        "/swagger/v2/*",
+			HandlerFunc: echoSwagger.EchoWrapHandler(echoSwagger.InstanceName("v2")),
+		},
+	}
+}
+
+func (d *DocsApi) GetRoutes() []router.Route {
+	return d.docsApiRoutes
+}
@@ -3,65 +3,108 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/labstack/echo/v4"
-	echoSwagger "github.com/swaggo/echo-swagger"
 
+	"github.com/cafo13/animal-facts/pkg/analytics"
+	"github.com/cafo13/animal-facts/pkg/httpcache"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
 	"github.com/cafo13/animal-facts/pkg/router"
-	_ "github.com/cafo13/animal-facts/public-api/docs"
+	"github.com/cafo13/animal-facts/pkg/shuffle"
 	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/cafo13/animal-facts/public-api/render"
 )
 
 var (
 	basePathV1 = "api/v1"
 )
 
-type ErrorResult struct {
-	Error string `json:"error"`
-}
+const (
+	shuffleTokenHeader = "Shuffle-Token"
+	maxRandomCount     = 50
+	maxSeedLength      = 128
+	defaultListLimit   = 20
+	maxListLimit       = 100
+)
+
+var sparseFieldNames = []string{"id", "fact", "source"}
 
 type CountResult struct {
 	Count int `json:"count"`
 }
 
+// Fact is the representation of a handler.Fact in the api v1.
+type Fact struct {
+	ID     string `json:"id"`
+	Fact   string `json:"fact"`
+	Source string `json:"source"`
+}
+
+// SparseFact is a Fact that only contains the fields requested with the fields query parameter.
+type SparseFact struct {
+	ID     *string `json:"id,omitempty"`
+	Fact   *string `json:"fact,omitempty"`
+	Source *string `json:"source,omitempty"`
+}
+
 type FactsApi struct {
 	factsApiRoutes []router.Route
 	factsHandler   *handler.FactsHandler
+	serveRecorder  *analytics.ServeRecorder
+	shuffleCodec   *shuffle.Codec
+	cachePolicies  httpcache.Policies
 }
 
-func NewFactsApi(factsHandler *handler.FactsHandler) *FactsApi {
-	return &FactsApi{factsHandler: factsHandler}
+// NewFactsApi creates the facts API, serves of facts are recorded by the serveRecorder unless it is nil.
+func NewFactsApi(factsHandler *handler.FactsHandler, serveRecorder *analytics.ServeRecorder, shuffleCodec *shuffle.Codec, cachePolicies httpcache.Policies) *FactsApi {
+	return &FactsApi{factsHandler: factsHandler, serveRecorder: serveRecorder, shuffleCodec: shuffleCodec, cachePolicies: cachePolicies}
 }
 
 func (f *FactsApi) SetupRoutes() {
 	f.factsApiRoutes = []router.Route{
 		{
 			Method:      "GET",
-			Path:        "/swagger/*",
-			HandlerFunc: echoSwagger.WrapHandler,
-		},
-		{
-			Method:      "GET",
-			Path:        "/health-public",
-			HandlerFunc: f.getHealth,
+			Path:        "/facts",
+			HandlerFunc: f.getRandomApproved,
+			Middlewares: []echo.MiddlewareFunc{
+				f.cachePolicies.Middleware("random"),
+				render.Negotiate(),
+			},
 		},
 		{
 			Method:      "GET",
-			Path:        fmt.Sprintf("/%s/facts", basePathV1),
-			HandlerFunc: f.getRandomApproved,
+			Path:        "/facts/list",
+			HandlerFunc: f.getList,
+			Middlewares: []echo.MiddlewareFunc{
+				f.cachePolicies.Middleware("list"),
+				render.Negotiate(),
+			},
 		},
 		{
 			Method:      "GET",
-			Path:        fmt.Sprintf("%s/facts/:id", basePathV1),
+			Path:        "/facts/:id",
 			HandlerFunc: f.get,
+			Middlewares: []echo.MiddlewareFunc{
+				f.cachePolicies.Middleware("fact"),
+				render.Negotiate(),
+			},
 		},
 		{
 			Method:      "GET",
-			Path:        fmt.Sprintf("%s/facts/count", basePathV1),
+			Path:        "/facts/count",
 			HandlerFunc: f.getCount,
+			Middlewares: []echo.MiddlewareFunc{
+				f.cachePolicies.Middleware("count"),
+				render.Negotiate(),
+			},
 		},
 	}
 }
@@ -70,70 +113,328 @@ func (f *FactsApi) GetRoutes() []router.Route {
 	return f.factsApiRoutes
 }
 
-func (f *FactsApi) getHealth(c echo.Context) error {
-	// TODO check database connection for health check and maybe other connections?
-	return c.String(http.StatusOK, "Healthy")
-}
-
 // getRandomApproved
 //
 //	@Summary      gets random fact
 //	@Description  gets random fact from the database
-//	@Produce      json
-//	@Success      200  {object}  handler.Fact
-//	@Failure      404  {object}  ErrorResult
-//	@Failure      500  {object}  ErrorResult
+//	@Description  with the count query parameter, a list of that many distinct random facts is returned instead of a single fact
+//	@Description  with the seed query parameter, the same seed always returns the same facts as long as the facts don't change
+//	@Description  with the shuffle query parameter, facts don't repeat until all facts were returned: pass an empty value to
+//	@Description  start and the value of the Shuffle-Token response header of the previous response to continue
+//	@Produce      json,plain,xml,text/csv,application/yaml,text/markdown
+//	@Param        count    query  int     false  "number of distinct random facts (1-50)"
+//	@Param        seed     query  string  false  "seed for a reproducible selection"
+//	@Param        shuffle  query  string  false  "shuffle token"
+//	@Success      200  {object}  Fact
+//	@Header       200  {string}  Shuffle-Token  "shuffle token for the next request, only set if the shuffle query parameter was passed"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts [get]
 func (f *FactsApi) getRandomApproved(c echo.Context) error {
-	fact, err := f.factsHandler.GetRandomApproved()
+	queryParams := c.QueryParams()
+	if queryParams.Has("shuffle") {
+		if queryParams.Has("count") || queryParams.Has("seed") {
+			return problem.BadRequest("shuffle can not be combined with count or seed")
+		}
+		return f.getShuffled(c)
+	}
+
+	facts, err := f.randomFacts(c)
+	if err != nil {
+		return err
+	}
+
+	if queryParams.Has("count") {
+		v1Facts := toFacts(facts)
+		return render.Render(c, http.StatusOK, &v1Facts)
+	}
+
+	return render.Render(c, http.StatusOK, toFact(facts[0]))
+}
+
+// randomFacts returns the random facts selected with the count and seed query parameters, one fact without count.
+func (f *FactsApi) randomFacts(c echo.Context) ([]*handler.Fact, error) {
+	queryParams := c.QueryParams()
+
+	var seed *string
+	if queryParams.Has("seed") {
+		seedParam := c.QueryParam("seed")
+		if len(seedParam) > maxSeedLength {
+			return nil, problem.BadRequest(fmt.Sprintf("seed must not be longer than %d characters", maxSeedLength))
+		}
+		seed = &seedParam
+	}
+
+	count := 1
+	if queryParams.Has("count") {
+		var err error
+		count, err = strconv.Atoi(c.QueryParam("count"))
+		if err != nil || count < 1 || count > maxRandomCount {
+			return nil, problem.BadRequest(fmt.Sprintf("count must be an integer between 1 and %d", maxRandomCount))
+		}
+	}
+
+	facts, err := f.factsHandler.WithContext(c.Request().Context()).GetRandomApprovedMany(count, seed)
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return nil, err
 	}
 
-	return c.JSON(http.StatusOK, &fact)
+	for _, fact := range facts {
+		f.recordServe(c, fact, "random")
+	}
+
+	return facts, nil
+}
+
+func (f *FactsApi) getShuffled(c echo.Context) error {
+	fact, err := f.nextShuffled(c)
+	if err != nil {
+		return err
+	}
+
+	return render.Render(c, http.StatusOK, toFact(fact))
+}
+
+// nextShuffled returns the next fact of the shuffle token of the shuffle query parameter, the token of the next
+// request is set as Shuffle-Token header.
+func (f *FactsApi) nextShuffled(c echo.Context) (*handler.Fact, error) {
+	token, err := shuffle.NewToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if encodedToken := c.QueryParam("shuffle"); encodedToken != "" {
+		token, err = f.shuffleCodec.Decode(encodedToken)
+		if err != nil {
+			return nil, problem.BadRequest("shuffle token is not valid")
+		}
+	}
+
+	fact, nextToken, err := f.factsHandler.WithContext(c.Request().Context()).GetNextShuffled(token)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Response().Header().Set(shuffleTokenHeader, f.shuffleCodec.Encode(nextToken))
+	c.Response().Header().Add(echo.HeaderAccessControlExposeHeaders, shuffleTokenHeader)
+	f.recordServe(c, fact, "random")
+	return fact, nil
 }
 
 // get
 //
 //	@Summary      gets fact
-//	@Description  gets fact by ID from the database
-//	@Produce      json
-//	@Success      200  {object}  handler.Fact
-//	@Failure      404  {object}  ErrorResult
-//	@Failure      500  {object}  ErrorResult
+//	@Description  gets fact by ID from the database, the response has an ETag and a Last-Modified header for conditional requests
+//	@Produce      json,plain,xml,text/csv,application/yaml,text/markdown
+//	@Success      200  {object}  Fact
+//	@Success      304
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts/:id [get]
 func (f *FactsApi) get(c echo.Context) error {
 	id := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, ErrorResult{Error: "id from request path is not a valid object id in hex string format"})
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
 	}
-	fact, err := f.factsHandler.Get(objID)
+	fact, version, err := f.factsHandler.WithContext(c.Request().Context()).GetWithVersion(objID)
 	if errors.Is(err, handler.ErrNotFound) {
-		return c.JSON(http.StatusNotFound, ErrorResult{Error: fmt.Sprintf("fact with ID '%s' not found", id)})
+		return problem.NotFound(fmt.Sprintf("fact with ID '%s' not found", id))
 	} else if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return err
+	}
+
+	f.recordServe(c, fact, "by-id")
+	v1Fact := toFact(fact)
+	if httpcache.NotModified(c, representationETag(c, factETag(v1Fact)), version.LastModified) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
-	return c.JSON(http.StatusOK, &fact)
+	return render.Render(c, http.StatusOK, v1Fact)
 }
 
 // getCount
 //
 //	@Summary      gets fact count
-//	@Description  gets fact count from the database
-//	@Produce      json
+//	@Description  gets fact count from the database, the response has an ETag for conditional requests
+//	@Produce      json,plain,xml,text/csv,application/yaml,text/markdown
 //	@Success      200  {object}  CountResult
-//	@Failure      500  {object}  ErrorResult
+//	@Success      304
+//	@Failure      500  {object}  problem.Problem
 //	@Router       /facts/count [get]
 func (f *FactsApi) getCount(c echo.Context) error {
-	count, err := f.factsHandler.GetFactsCount()
+	count, err := f.factsHandler.WithContext(c.Request().Context()).GetFactsCount()
+	if err != nil {
+		return err
+	}
+
+	if httpcache.NotModified(c, representationETag(c, fmt.Sprintf("count-%d", count)), time.Time{}) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return render.Render(c, http.StatusOK, &CountResult{Count: count})
+}
+
+// getList
+//
+//	@Summary      lists facts
+//	@Description  lists facts page by page ordered by creation time, the URL of the next page is in the Link response header
+//	@Produce      json,plain,xml,text/csv,application/yaml,text/markdown
+//	@Param        animal         query  string  false  "only facts about this animal"
+//	@Param        tag            query  string  false  "only facts with this tag"
+//	@Param        language       query  string  false  "only facts in this language"
+//	@Param        created_after  query  string  false  "only facts created after this RFC 3339 timestamp"
+//	@Param        sort           query  string  false  "created (oldest first, default) or -created (newest first)"
+//	@Param        limit          query  int     false  "facts per page (1-100, default 20)"
+//	@Param        cursor         query  string  false  "cursor of the page, taken from the Link header of the previous page"
+//	@Param        fields         query  string  false  "comma separated fields to return (id, fact, source), all if empty"
+//	@Success      200  {array}   SparseFact
+//	@Header       200  {string}  Link  "link to the next page with rel=next, not set on the last page"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/list [get]
+func (f *FactsApi) getList(c echo.Context) error {
+	query, err := parseListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	fields := sparseFieldNames
+	if fieldsParam := c.QueryParam("fields"); fieldsParam != "" {
+		fields = strings.Split(fieldsParam, ",")
+		for _, field := range fields {
+			if !slices.Contains(sparseFieldNames, field) {
+				return problem.BadRequest(fmt.Sprintf("field '%s' is not valid, valid fields are %s", field, strings.Join(sparseFieldNames, ", ")))
+			}
+		}
+	}
+
+	page, err := f.listApproved(c, query)
+	if err != nil {
+		return err
+	}
+
+	if page.NextCursor != "" {
+		c.Response().Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageUrl(c, page.NextCursor)))
+		c.Response().Header().Add(echo.HeaderAccessControlExposeHeaders, "Link")
+	}
+
+	sparseFacts := make([]*SparseFact, 0, len(page.Facts))
+	for _, fact := range page.Facts {
+		sparseFacts = append(sparseFacts, toSparseFact(fact, fields))
+	}
+
+	return render.Render(c, http.StatusOK, &sparseFacts)
+}
+
+// parseListQuery parses the filters, the sort order, the limit and the cursor of a page of facts.
+func parseListQuery(c echo.Context) (handler.ListQuery, error) {
+	query := handler.ListQuery{
+		Animal:   strings.ToLower(strings.TrimSpace(c.QueryParam("animal"))),
+		Tag:      strings.ToLower(strings.TrimSpace(c.QueryParam("tag"))),
+		Language: strings.ToLower(strings.TrimSpace(c.QueryParam("language"))),
+		Cursor:   c.QueryParam("cursor"),
+		Limit:    defaultListLimit,
+	}
+
+	if createdAfter := c.QueryParam("created_after"); createdAfter != "" {
+		createdAfterTime, err := time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			return query, problem.BadRequest(fmt.Sprintf("created_after '%s' is not a valid RFC 3339 timestamp", createdAfter))
+		}
+		query.CreatedAfter = &createdAfterTime
+	}
+
+	switch c.QueryParam("sort") {
+	case "", "created":
+	case "-created":
+		query.SortDescending = true
+	default:
+		return query, problem.BadRequest("sort must be either created or -created")
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		var err error
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 || query.Limit > maxListLimit {
+			return query, problem.BadRequest(fmt.Sprintf("limit must be an integer between 1 and %d", maxListLimit))
+		}
+	}
+
+	return query, nil
+}
+
+func (f *FactsApi) listApproved(c echo.Context, query handler.ListQuery) (*handler.FactsPage, error) {
+	page, err := f.factsHandler.WithContext(c.Request().Context()).ListApproved(query)
+	if errors.Is(err, handler.ErrInvalidCursor) {
+		return nil, problem.BadRequest("cursor is not valid for this sort order")
+	}
+
+	return page, err
+}
+
+// pageUrl returns the absolute URL of the request with the cursor of another page.
+func pageUrl(c echo.Context, cursor string) string {
+	pageUrl := *c.Request().URL
+	pageQuery := pageUrl.Query()
+	pageQuery.Set("cursor", cursor)
+	pageUrl.RawQuery = pageQuery.Encode()
+	pageUrl.Scheme = c.Scheme()
+	pageUrl.Host = c.Request().Host
+
+	return pageUrl.String()
+}
+
+func toFact(fact *handler.Fact) *Fact {
+	return &Fact{ID: fact.ID, Fact: fact.Fact, Source: fact.Source}
+}
+
+// factETag returns the ETag of the fields of the fact in the api v1, changes of other fields don't invalidate caches.
+func factETag(fact *Fact) string {
+	return httpcache.HashETag(fact.ID, fact.Fact, fact.Source)
+}
+
+func toFacts(facts []*handler.Fact) []*Fact {
+	v1Facts := make([]*Fact, 0, len(facts))
+	for _, fact := range facts {
+		v1Facts = append(v1Facts, toFact(fact))
+	}
+
+	return v1Facts
+}
+
+func toSparseFact(fact *handler.Fact, fields []string) *SparseFact {
+	sparseFact := &SparseFact{}
+	for _, field := range fields {
+		switch field {
+		case "id":
+			sparseFact.ID = &fact.ID
+		case "fact":
+			sparseFact.Fact = &fact.Fact
+		case "source":
+			sparseFact.Source = &fact.Source
+		}
+	}
+
+	return sparseFact
+}
+
+func (f *FactsApi) recordServe(c echo.Context, fact *handler.Fact, endpoint string) {
+	factID, err := primitive.ObjectIDFromHex(fact.ID)
 	if err != nil {
-		// TODO only log error and return generic message as internal server error should not be displayed to user
-		return c.JSON(http.StatusInternalServerError, ErrorResult{Error: err.Error()})
+		return
 	}
 
-	return c.JSON(http.StatusOK, &CountResult{Count: count})
+	f.serveRecorder.Record(&repository.ServeEvent{
+		FactID:     factID,
+		Endpoint:   endpoint,
+		ClientType: analytics.ClientType(c.Request().UserAgent()),
+		Timestamp:  time.Now(),
+	})
+}
+
+// representationETag returns the strong ETag of the response, which differs between the formats of the response.
+func representationETag(c echo.Context, etag string) string {
+	return httpcache.StrongETag(etag + "-" + render.FormatOf(c).Name)
 }
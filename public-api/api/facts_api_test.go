@@ -10,7 +10,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/cafo13/animal-facts/pkg/problem"
 	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/shuffle"
 	"github.com/cafo13/animal-facts/public-api/handler"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
@@ -28,7 +30,7 @@ func getFactsApi() (*FactsApi, error) {
 	}
 
 	factsHandler := handler.NewFactsHandler(fatsRepository)
-	factsApi := NewFactsApi(factsHandler)
+	factsApi := NewFactsApi(factsHandler, nil, shuffle.NewCodec([]byte("integration-test-secret")), DefaultCachePolicies)
 	return factsApi, nil
 }
 
@@ -110,9 +112,9 @@ func Test_RunApiIntegrationTests_get(t *testing.T) {
 		{
 			name:           "get not found status on trying to get fact with id that not exists in test database",
 			requestFactID:  "507f1f77bcf86cd799439011",
-			wantResponse:   `{"error":"fact with ID '507f1f77bcf86cd799439011' not found"}`,
+			wantResponse:   `{"type":"urn:animal-facts:problem:not_found","title":"Not Found","status":404,"detail":"fact with ID '507f1f77bcf86cd799439011' not found","instance":"/","code":"not_found"}`,
 			wantHttpStatus: http.StatusNotFound,
-			wantErr:        false,
+			wantErr:        true,
 		},
 	}
 	for _, tt := range tests {
@@ -129,6 +131,9 @@ func Test_RunApiIntegrationTests_get(t *testing.T) {
 				t.Errorf("RunApiIntegrationTests_get() error = %v, wantErr = %v", gotErr, tt.wantErr)
 				return
 			}
+			if gotErr != nil {
+				problem.HTTPErrorHandler(gotErr, c)
+			}
 
 			if rec.Code != tt.wantHttpStatus {
 				t.Errorf("RunApiIntegrationTests_get() gotHttpStatus = %v, wantHttpStatus = %v", rec.Code, tt.wantHttpStatus)
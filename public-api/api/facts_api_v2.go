@@ -0,0 +1,368 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/httpcache"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/cafo13/animal-facts/public-api/render"
+)
+
+var (
+	basePathV2 = "api/v2"
+)
+
+// Document is the envelope of the responses of the api v2, data is a FactV2, a list of them or a CountV2.
+type Document struct {
+	Data  any   `json:"data"`
+	Meta  Meta  `json:"meta"`
+	Links Links `json:"links"`
+}
+
+type Meta struct {
+	// Count is the number of facts in data, it is only set for lists
+	Count *int `json:"count,omitempty"`
+	// Limit is the maximum number of facts in data, it is only set for pages and trending facts
+	Limit int `json:"limit,omitempty"`
+	// ShuffleToken continues the shuffled facts with the next request, it is only set for shuffled facts
+	ShuffleToken string `json:"shuffleToken,omitempty"`
+}
+
+type Links struct {
+	Self string `json:"self"`
+	// Next is the URL of the next page, it is only set for pages that are not the last page
+	Next string `json:"next,omitempty"`
+}
+
+// FactV2 is the representation of a handler.Fact in the api v2.
+type FactV2 struct {
+	ID        string       `json:"id"`
+	Fact      string       `json:"fact"`
+	Animal    string       `json:"animal"`
+	Tags      []string     `json:"tags"`
+	Language  string       `json:"language"`
+	Citations []CitationV2 `json:"citations"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	// ApprovedAt is the time the fact was approved the last time
+	ApprovedAt time.Time `json:"approvedAt"`
+}
+
+type CitationV2 struct {
+	// Type is url or doi
+	Type string `json:"type"`
+	// DOI is only set for citations of type doi
+	DOI string `json:"doi,omitempty"`
+	URL string `json:"url"`
+}
+
+type CountV2 struct {
+	Count int `json:"count"`
+}
+
+// FactsApiV2 serves the facts in the api v2, it shares the handlers and the parsing of the requests with the api v1.
+type FactsApiV2 struct {
+	factsApiRoutes  []router.Route
+	factsApi        *FactsApi
+	trendingHandler *handler.TrendingHandler
+}
+
+func NewFactsApiV2(factsApi *FactsApi, trendingHandler *handler.TrendingHandler) *FactsApiV2 {
+	return &FactsApiV2{factsApi: factsApi, trendingHandler: trendingHandler}
+}
+
+func (f *FactsApiV2) SetupRoutes() {
+	cachePolicies := f.factsApi.cachePolicies
+	f.factsApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/facts",
+			HandlerFunc: f.getList,
+			Middlewares: []echo.MiddlewareFunc{
+				cachePolicies.Middleware("list"),
+				render.Negotiate(render.FormatJSON),
+			},
+		},
+		{
+			Method:      "GET",
+			Path:        "/facts/random",
+			HandlerFunc: f.getRandomApproved,
+			Middlewares: []echo.MiddlewareFunc{
+				cachePolicies.Middleware("random"),
+				render.Negotiate(render.FormatJSON),
+			},
+		},
+		{
+			Method:      "GET",
+			Path:        "/facts/trending",
+			HandlerFunc: f.getTrending,
+			Middlewares: []echo.MiddlewareFunc{
+				render.Negotiate(render.FormatJSON),
+			},
+		},
+		{
+			Method:      "GET",
+			Path:        "/facts/count",
+			HandlerFunc: f.getCount,
+			Middlewares: []echo.MiddlewareFunc{
+				cachePolicies.Middleware("count"),
+				render.Negotiate(render.FormatJSON),
+			},
+		},
+		{
+			Method:      "GET",
+			Path:        "/facts/:id",
+			HandlerFunc: f.get,
+			Middlewares: []echo.MiddlewareFunc{
+				cachePolicies.Middleware("fact"),
+				render.Negotiate(render.FormatJSON),
+			},
+		},
+	}
+}
+
+func (f *FactsApiV2) GetRoutes() []router.Route {
+	return f.factsApiRoutes
+}
+
+// getList
+//
+//	@Summary      lists facts
+//	@Description  lists facts page by page ordered by creation time, the URL of the next page is in links.next
+//	@Tags         v2
+//	@Produce      json
+//	@Param        animal         query  string  false  "only facts about this animal"
+//	@Param        tag            query  string  false  "only facts with this tag"
+//	@Param        language       query  string  false  "only facts in this language"
+//	@Param        created_after  query  string  false  "only facts created after this RFC 3339 timestamp"
+//	@Param        sort           query  string  false  "created (oldest first, default) or -created (newest first)"
+//	@Param        limit          query  int     false  "facts per page (1-100, default 20)"
+//	@Param        cursor         query  string  false  "cursor of the page, taken from links.next of the previous page"
+//	@Success      200  {object}  Document{data=[]FactV2}
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      406  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts [get]
+func (f *FactsApiV2) getList(c echo.Context) error {
+	query, err := parseListQuery(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := f.factsApi.listApproved(c, query)
+	if err != nil {
+		return err
+	}
+
+	document := listDocument(c, page.Facts)
+	document.Meta.Limit = query.Limit
+	if page.NextCursor != "" {
+		document.Links.Next = pageUrl(c, page.NextCursor)
+	}
+
+	return c.JSON(http.StatusOK, document)
+}
+
+// getRandomApproved
+//
+//	@Summary      gets random facts
+//	@Description  gets a random fact, or with the count query parameter a list of that many distinct random facts
+//	@Description  with the seed query parameter, the same seed always returns the same facts as long as the facts don't change
+//	@Description  with the shuffle query parameter, facts don't repeat until all facts were returned: pass an empty value to
+//	@Description  start and meta.shuffleToken (also the Shuffle-Token response header) of the previous response to continue
+//	@Tags         v2
+//	@Produce      json
+//	@Param        count    query  int     false  "number of distinct random facts (1-50)"
+//	@Param        seed     query  string  false  "seed for a reproducible selection"
+//	@Param        shuffle  query  string  false  "shuffle token"
+//	@Success      200  {object}  Document{data=FactV2}
+//	@Header       200  {string}  Shuffle-Token  "shuffle token for the next request, only set if the shuffle query parameter was passed"
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      406  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/random [get]
+func (f *FactsApiV2) getRandomApproved(c echo.Context) error {
+	queryParams := c.QueryParams()
+	if queryParams.Has("shuffle") {
+		if queryParams.Has("count") || queryParams.Has("seed") {
+			return problem.BadRequest("shuffle can not be combined with count or seed")
+		}
+
+		fact, err := f.factsApi.nextShuffled(c)
+		if err != nil {
+			return err
+		}
+		document := factDocument(c, fact)
+		document.Meta.ShuffleToken = c.Response().Header().Get(shuffleTokenHeader)
+		return c.JSON(http.StatusOK, document)
+	}
+
+	facts, err := f.factsApi.randomFacts(c)
+	if err != nil {
+		return err
+	}
+
+	if queryParams.Has("count") {
+		return c.JSON(http.StatusOK, listDocument(c, facts))
+	}
+
+	return c.JSON(http.StatusOK, factDocument(c, facts[0]))
+}
+
+// getTrending
+//
+//	@Summary      gets trending facts
+//	@Description  gets the facts that were served most often recently, where older serves count less than newer ones
+//	@Tags         v2
+//	@Produce      json
+//	@Param        limit  query  int  false  "maximum number of facts (1-50, default 10)"
+//	@Success      200  {object}  Document{data=[]FactV2}
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      406  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/trending [get]
+func (f *FactsApiV2) getTrending(c echo.Context) error {
+	limit, err := parseTrendingLimit(c)
+	if err != nil {
+		return err
+	}
+
+	facts, err := f.trendingHandler.GetTrending(limit)
+	if err != nil {
+		return err
+	}
+
+	document := listDocument(c, facts)
+	document.Meta.Limit = limit
+	return c.JSON(http.StatusOK, document)
+}
+
+// getCount
+//
+//	@Summary      gets fact count
+//	@Description  gets fact count from the database, the response has an ETag for conditional requests
+//	@Tags         v2
+//	@Produce      json
+//	@Success      200  {object}  Document{data=CountV2}
+//	@Success      304
+//	@Failure      406  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/count [get]
+func (f *FactsApiV2) getCount(c echo.Context) error {
+	count, err := f.factsApi.factsHandler.WithContext(c.Request().Context()).GetFactsCount()
+	if err != nil {
+		return err
+	}
+
+	if httpcache.NotModified(c, representationETag(c, fmt.Sprintf("count-%d-v2", count)), time.Time{}) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSON(http.StatusOK, &Document{Data: &CountV2{Count: count}, Links: Links{Self: selfUrl(c)}})
+}
+
+// get
+//
+//	@Summary      gets fact
+//	@Description  gets fact by ID from the database, the response has an ETag and a Last-Modified header for conditional requests
+//	@Tags         v2
+//	@Produce      json
+//	@Success      200  {object}  Document{data=FactV2}
+//	@Success      304
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      406  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/:id [get]
+func (f *FactsApiV2) get(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+	fact, version, err := f.factsApi.factsHandler.WithContext(c.Request().Context()).GetWithVersion(objID)
+	if errors.Is(err, handler.ErrNotFound) {
+		return problem.NotFound(fmt.Sprintf("fact with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	f.factsApi.recordServe(c, fact, "by-id")
+	if httpcache.NotModified(c, representationETag(c, factV2ETag(toFactV2(fact))), version.LastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSON(http.StatusOK, factDocument(c, fact))
+}
+
+// factV2ETag returns the ETag of the fields of the fact in the api v2.
+func factV2ETag(fact *FactV2) string {
+	values := []string{
+		fact.ID,
+		fact.Fact,
+		fact.Animal,
+		fact.Language,
+		fact.CreatedAt.Format(time.RFC3339Nano),
+		fact.UpdatedAt.Format(time.RFC3339Nano),
+		fact.ApprovedAt.Format(time.RFC3339Nano),
+		// the number of citations keeps the boundary between the citations and the tags in the hash
+		strconv.Itoa(len(fact.Citations)),
+	}
+	for _, citation := range fact.Citations {
+		values = append(values, citation.Type, citation.DOI, citation.URL)
+	}
+	values = append(values, fact.Tags...)
+
+	return httpcache.HashETag(values...)
+}
+
+func toFactV2(fact *handler.Fact) *FactV2 {
+	citations := make([]CitationV2, 0, len(fact.Citations))
+	for _, citation := range fact.Citations {
+		citations = append(citations, CitationV2{Type: citation.Type, DOI: citation.DOI, URL: citation.URL})
+	}
+
+	return &FactV2{
+		ID:         fact.ID,
+		Fact:       fact.Fact,
+		Animal:     fact.Animal,
+		Tags:       append([]string{}, fact.Tags...),
+		Language:   fact.Language,
+		Citations:  citations,
+		CreatedAt:  fact.CreatedAt,
+		UpdatedAt:  fact.UpdatedAt,
+		ApprovedAt: fact.ApprovedAt,
+	}
+}
+
+func factDocument(c echo.Context, fact *handler.Fact) *Document {
+	return &Document{
+		Data:  toFactV2(fact),
+		Links: Links{Self: fmt.Sprintf("%s/%s/facts/%s", requestBaseUrl(c), basePathV2, fact.ID)},
+	}
+}
+
+func listDocument(c echo.Context, facts []*handler.Fact) *Document {
+	v2Facts := make([]*FactV2, 0, len(facts))
+	for _, fact := range facts {
+		v2Facts = append(v2Facts, toFactV2(fact))
+	}
+	count := len(v2Facts)
+
+	return &Document{Data: v2Facts, Meta: Meta{Count: &count}, Links: Links{Self: selfUrl(c)}}
+}
+
+// selfUrl returns the absolute URL of the request.
+func selfUrl(c echo.Context) string {
+	return requestBaseUrl(c) + c.Request().URL.RequestURI()
+}
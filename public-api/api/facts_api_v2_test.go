@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/pkg/shuffle"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+var (
+	whaleID = primitive.NewObjectID()
+	whale   = repository.Fact{
+		ID:         whaleID,
+		Fact:       "The Blue Whale is the largest animal that has ever lived.",
+		Source:     "https://factanimal.com/blue-whale/",
+		Animal:     "blue whale",
+		Tags:       []string{"ocean", "mammal"},
+		Language:   "en",
+		Approved:   true,
+		ApprovedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		CreatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	octopusID = primitive.NewObjectID()
+	octopus   = repository.Fact{
+		ID:         octopusID,
+		Fact:       "Octopuses have three hearts.",
+		Source:     "doi:10.1000/182",
+		Approved:   true,
+		ApprovedAt: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+		CreatedAt:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func newVersionsTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	factsRepository := repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{whaleID: &whale, octopusID: &octopus}, false)
+	factsApi := NewFactsApi(handler.NewFactsHandler(factsRepository), nil, shuffle.NewCodec([]byte("test-secret")), DefaultCachePolicies)
+	factsApiV2 := NewFactsApiV2(factsApi, handler.NewTrendingHandler(factsRepository, repository.NewMockServeStatsRepository(nil, false)))
+
+	e := echo.New()
+	e.HTTPErrorHandler = problem.HTTPErrorHandler
+	for prefix, api := range map[string]router.Api{"/api/v1": factsApi, "/api/v2": factsApiV2} {
+		api.SetupRoutes()
+		for _, route := range api.GetRoutes() {
+			e.Add(route.Method, prefix+route.Path, route.HandlerFunc, route.Middlewares...)
+		}
+	}
+
+	return e
+}
+
+func serve(e *echo.Echo, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestFactsApiV2_get(t *testing.T) {
+	e := newVersionsTestServer(t)
+
+	recorder := serve(e, "/api/v2/facts/"+octopusID.Hex())
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	want := `{"data":{"id":"` + octopusID.Hex() + `","fact":"Octopuses have three hearts.","animal":"","tags":[],"language":"",` +
+		`"citations":[{"type":"doi","doi":"10.1000/182","url":"https://doi.org/10.1000/182"}],` +
+		`"createdAt":"2024-01-02T00:00:00Z","updatedAt":"2024-01-05T00:00:00Z","approvedAt":"2024-01-04T00:00:00Z"},` +
+		`"meta":{},"links":{"self":"http://example.com/api/v2/facts/` + octopusID.Hex() + `"}}`
+	if got := strings.TrimSuffix(recorder.Body.String(), "\n"); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+	if recorder.Header().Get("ETag") == "" {
+		t.Errorf("ETag header is missing")
+	}
+
+	recorder = serve(e, "/api/v2/facts/"+primitive.NewObjectID().Hex())
+	if recorder.Code != http.StatusNotFound || recorder.Header().Get(echo.HeaderContentType) != problem.MediaType {
+		t.Errorf("status = %d with %s, want problem details with %d", recorder.Code, recorder.Header().Get(echo.HeaderContentType), http.StatusNotFound)
+	}
+
+	recorder = serve(e, "/api/v2/facts/"+octopusID.Hex()+"?format=csv")
+	if recorder.Code != http.StatusNotAcceptable {
+		t.Errorf("status = %d, want %d for csv", recorder.Code, http.StatusNotAcceptable)
+	}
+}
+
+func TestFactsApiV2_getList(t *testing.T) {
+	e := newVersionsTestServer(t)
+
+	recorder := serve(e, "/api/v2/facts?limit=1")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var document struct {
+		Data  []FactV2 `json:"data"`
+		Meta  Meta     `json:"meta"`
+		Links Links    `json:"links"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &document); err != nil {
+		t.Fatalf("body = %s, want document: %v", recorder.Body.String(), err)
+	}
+	if len(document.Data) != 1 || document.Data[0].ID != whaleID.Hex() || document.Data[0].Animal != "blue whale" ||
+		len(document.Data[0].Tags) != 2 || document.Data[0].Citations[0].Type != handler.CitationTypeURL {
+		t.Errorf("data = %+v, want the first fact", document.Data)
+	}
+	if document.Meta.Count == nil || *document.Meta.Count != 1 || document.Meta.Limit != 1 {
+		t.Errorf("meta = %+v, want count and limit 1", document.Meta)
+	}
+	if document.Links.Self != "http://example.com/api/v2/facts?limit=1" || !strings.Contains(document.Links.Next, "cursor=") {
+		t.Errorf("links = %+v, want self and next", document.Links)
+	}
+	if link := recorder.Header().Get("Link"); link != "" {
+		t.Errorf("Link = %s, want the next page in the links of the document only", link)
+	}
+
+	recorder = serve(e, strings.TrimPrefix(document.Links.Next, "http://example.com"))
+	document.Links = Links{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &document); err != nil {
+		t.Fatalf("body = %s, want document: %v", recorder.Body.String(), err)
+	}
+	if len(document.Data) != 1 || document.Data[0].ID != octopusID.Hex() || document.Links.Next != "" {
+		t.Errorf("second page = %s, want the last fact", recorder.Body.String())
+	}
+}
+
+func TestFactsApiV2_getList_language(t *testing.T) {
+	e := newVersionsTestServer(t)
+
+	// octopus was stored without a language, so it is in the default language
+	for language, want := range map[string]int{"en": 2, "de": 0} {
+		recorder := serve(e, "/api/v2/facts?language="+language)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+		}
+
+		var document struct {
+			Data []FactV2 `json:"data"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &document); err != nil {
+			t.Fatalf("body = %s, want document: %v", recorder.Body.String(), err)
+		}
+		if len(document.Data) != want {
+			t.Errorf("language %s: got %d facts, want %d", language, len(document.Data), want)
+		}
+	}
+}
+
+func TestFactsApi_v1Compatibility(t *testing.T) {
+	e := newVersionsTestServer(t)
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{
+			target: "/api/v1/facts/" + whaleID.Hex(),
+			want:   `{"id":"` + whaleID.Hex() + `","fact":"The Blue Whale is the largest animal that has ever lived.","source":"https://factanimal.com/blue-whale/"}` + "\n",
+		},
+		{
+			target: "/api/v1/facts/" + whaleID.Hex() + "?format=xml",
+			want: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<fact><id>` + whaleID.Hex() + `</id>` +
+				`<fact>The Blue Whale is the largest animal that has ever lived.</fact><source>https://factanimal.com/blue-whale/</source></fact>` + "\n",
+		},
+		{
+			target: "/api/v1/facts/list?fields=id",
+			want:   `[{"id":"` + whaleID.Hex() + `"},{"id":"` + octopusID.Hex() + `"}]` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			recorder := serve(e, tt.target)
+			if recorder.Code != http.StatusOK || recorder.Body.String() != tt.want {
+				t.Errorf("response = %d %q, want %q", recorder.Code, recorder.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFactsApi_etagPerVersion(t *testing.T) {
+	original := whale
+	t.Cleanup(func() { whale = original })
+	e := newVersionsTestServer(t)
+
+	etags := func() (string, string) {
+		return serve(e, "/api/v1/facts/"+whaleID.Hex()).Header().Get("ETag"), serve(e, "/api/v2/facts/"+whaleID.Hex()).Header().Get("ETag")
+	}
+	v1ETag, v2ETag := etags()
+	if v1ETag == "" || v2ETag == "" || v1ETag == v2ETag {
+		t.Fatalf("ETags = %s and %s, want different ETags of both versions", v1ETag, v2ETag)
+	}
+
+	whale.Tags = []string{"ocean"}
+	changedV1ETag, changedV2ETag := etags()
+	if changedV1ETag != v1ETag {
+		t.Errorf("v1 ETag = %s after the tags changed, want %s as the tags are not in the api v1", changedV1ETag, v1ETag)
+	}
+	if changedV2ETag == v2ETag {
+		t.Errorf("v2 ETag = %s after the tags changed, want a changed ETag", changedV2ETag)
+	}
+
+	whale.Source = "https://en.wikipedia.org/wiki/Blue_whale"
+	if changedV1ETag, _ = etags(); changedV1ETag == v1ETag {
+		t.Errorf("v1 ETag = %s after the source changed, want a changed ETag", changedV1ETag)
+	}
+}
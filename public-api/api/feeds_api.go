@@ -0,0 +1,146 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/httpcache"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/public-api/feed"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+const (
+	feedTitle          = "Animal Facts"
+	feedDescription    = "The latest approved facts about animals"
+	feedAuthor         = "Animal Facts"
+	feedItemTitleRunes = 80
+)
+
+type feedFormat struct {
+	name        string
+	contentType string
+	render      func(*feed.Feed) ([]byte, error)
+}
+
+var (
+	feedFormatRSS      = feedFormat{name: "latest.rss", contentType: feed.MediaTypeRSS, render: feed.RSS}
+	feedFormatAtom     = feedFormat{name: "latest.atom", contentType: feed.MediaTypeAtom, render: feed.Atom}
+	feedFormatJSONFeed = feedFormat{name: "feed.json", contentType: feed.MediaTypeJSONFeed, render: feed.JSONFeed}
+)
+
+type FeedsApi struct {
+	feedsApiRoutes []router.Route
+	feedsHandler   *handler.FeedsHandler
+	cachePolicies  httpcache.Policies
+}
+
+func NewFeedsApi(feedsHandler *handler.FeedsHandler, cachePolicies httpcache.Policies) *FeedsApi {
+	return &FeedsApi{feedsHandler: feedsHandler, cachePolicies: cachePolicies}
+}
+
+func (f *FeedsApi) SetupRoutes() {
+	f.feedsApiRoutes = []router.Route{}
+	for _, format := range []feedFormat{feedFormatRSS, feedFormatAtom, feedFormatJSONFeed} {
+		f.feedsApiRoutes = append(f.feedsApiRoutes, router.Route{
+			Method:      "GET",
+			Path:        fmt.Sprintf("/feeds/%s", format.name),
+			HandlerFunc: f.getFeed(format),
+			Middlewares: []echo.MiddlewareFunc{f.cachePolicies.Middleware("feed")},
+		})
+	}
+}
+
+func (f *FeedsApi) GetRoutes() []router.Route {
+	return f.feedsApiRoutes
+}
+
+// getFeed
+//
+//	@Summary      gets feed of latest facts
+//	@Description  gets the most recently approved facts as RSS 2.0 (latest.rss), Atom (latest.atom) or JSON Feed 1.1 (feed.json)
+//	@Produce      application/rss+xml,application/atom+xml,application/feed+json
+//	@Param        animal  query  string  false  "only facts about this animal"
+//	@Param        tag     query  string  false  "only facts with this tag"
+//	@Success      200  {file}    binary
+//	@Success      304
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /feeds/latest.rss [get]
+func (f *FeedsApi) getFeed(format feedFormat) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		query := handler.FeedQuery{
+			Animal: strings.ToLower(strings.TrimSpace(c.QueryParam("animal"))),
+			Tag:    strings.ToLower(strings.TrimSpace(c.QueryParam("tag"))),
+		}
+
+		latestApproved, err := f.feedsHandler.GetLatestApproved(query)
+		if err != nil {
+			return err
+		}
+
+		if httpcache.NotModified(c, httpcache.StrongETag(latestApproved.ETag), latestApproved.LastModified) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		body, err := format.render(toFeed(c, query, latestApproved))
+		if err != nil {
+			return err
+		}
+
+		return c.Blob(http.StatusOK, format.contentType+"; charset=UTF-8", body)
+	}
+}
+
+func toFeed(c echo.Context, query handler.FeedQuery, latestApproved *handler.LatestApprovedFeed) *feed.Feed {
+	baseUrl := requestBaseUrl(c)
+
+	title := feedTitle
+	if query.Animal != "" {
+		title += fmt.Sprintf(" about %s", query.Animal)
+	}
+	if query.Tag != "" {
+		title += fmt.Sprintf(" tagged %s", query.Tag)
+	}
+
+	result := &feed.Feed{
+		Title:       title,
+		Description: feedDescription,
+		HomePageURL: baseUrl + "/",
+		FeedURL:     baseUrl + c.Request().URL.RequestURI(),
+		Author:      feedAuthor,
+		Updated:     latestApproved.LastModified,
+	}
+	for _, item := range latestApproved.Items {
+		factUrl := fmt.Sprintf("%s/%s/facts/%s", baseUrl, basePathV1, item.Fact.ID)
+		result.Items = append(result.Items, &feed.Item{
+			ID:        factUrl,
+			URL:       factUrl,
+			Title:     feedItemTitle(item.Fact.Fact),
+			Content:   item.Fact.Fact,
+			Source:    item.Fact.Source,
+			Tags:      item.Tags,
+			Published: item.ApprovedAt,
+			Updated:   item.UpdatedAt,
+		})
+	}
+
+	return result
+}
+
+// feedItemTitle shortens the fact to a title, cutting at the last word that fits.
+func feedItemTitle(fact string) string {
+	runes := []rune(fact)
+	if len(runes) <= feedItemTitleRunes {
+		return fact
+	}
+
+	title := string(runes[:feedItemTitleRunes])
+	if lastSpace := strings.LastIndex(title, " "); lastSpace > 0 {
+		title = title[:lastSpace]
+	}
+
+	return strings.TrimRight(title, " ,.;:") + "…"
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+func TestFeedsApi_getFeed_ifModifiedSince(t *testing.T) {
+	factsRepository := repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{whaleID: &whale, octopusID: &octopus}, false)
+	feedsApi := NewFeedsApi(handler.NewFeedsHandler(factsRepository), DefaultCachePolicies)
+	feedsApi.SetupRoutes()
+	e := echo.New()
+	for _, route := range feedsApi.GetRoutes() {
+		e.Add(route.Method, route.Path, route.HandlerFunc, route.Middlewares...)
+	}
+
+	serveSince := func(ifModifiedSince string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/feeds/latest.rss", nil)
+		if ifModifiedSince != "" {
+			request.Header.Set(echo.HeaderIfModifiedSince, ifModifiedSince)
+		}
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := serveSince("")
+	lastModified := recorder.Header().Get(echo.HeaderLastModified)
+	// octopus was updated last
+	if recorder.Code != http.StatusOK || lastModified != octopus.UpdatedAt.Format(http.TimeFormat) {
+		t.Fatalf("response = %d with Last-Modified %q, want %d with the update of the latest item", recorder.Code, lastModified, http.StatusOK)
+	}
+
+	if recorder = serveSince(lastModified); recorder.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d if not modified since the last modification", recorder.Code, http.StatusNotModified)
+	}
+	if recorder = serveSince(octopus.UpdatedAt.Add(-time.Second).Format(http.TimeFormat)); recorder.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d if modified since", recorder.Code, http.StatusOK)
+	}
+
+	err := factsRepository.Update(whaleID, func(fact *repository.Fact) (*repository.Fact, error) {
+		fact.Approved = false
+		return fact, nil
+	})
+	if err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if recorder = serveSince(lastModified); recorder.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d after an item was removed", recorder.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/apikey"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/public-api/graphql"
+)
+
+const (
+	maxGraphQLQueryLength = 10000
+)
+
+type GraphQLApi struct {
+	graphQLApiRoutes []router.Route
+	graphQLServer    *graphql.Server
+}
+
+func NewGraphQLApi(graphQLServer *graphql.Server) *GraphQLApi {
+	return &GraphQLApi{graphQLServer: graphQLServer}
+}
+
+func (g *GraphQLApi) SetupRoutes() {
+	g.graphQLApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/graphql",
+			HandlerFunc: g.query,
+		},
+		{
+			Method:      "POST",
+			Path:        "/graphql",
+			HandlerFunc: g.query,
+			// the schema has no mutations, so POST requests only read facts
+			Scope: apikey.ScopeReadFacts,
+		},
+	}
+}
+
+func (g *GraphQLApi) GetRoutes() []router.Route {
+	return g.graphQLApiRoutes
+}
+
+// query executes a GraphQL query, which is sent as JSON body with POST or as query parameters with GET.
+// Errors of the query are part of the response with status 200, only requests that are no GraphQL requests at all
+// are rejected with status 400.
+func (g *GraphQLApi) query(c echo.Context) error {
+	request := &graphql.Request{CorrelationID: problem.CorrelationID(c)}
+	if c.Request().Method == http.MethodGet {
+		request.Query = c.QueryParam("query")
+		request.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return problem.BadRequest("variables must be a JSON object")
+			}
+		}
+	} else if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+		return problem.BadRequest("request body must be a JSON object with query, operationName and variables")
+	}
+
+	if request.Query == "" {
+		return problem.BadRequest("query must not be empty")
+	}
+	if len(request.Query) > maxGraphQLQueryLength {
+		return problem.BadRequest(fmt.Sprintf("query must not be longer than %d characters", maxGraphQLQueryLength))
+	}
+
+	return c.JSON(http.StatusOK, g.graphQLServer.Execute(c.Request().Context(), request))
+}
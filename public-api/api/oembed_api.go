@@ -0,0 +1,308 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/httpcache"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/cafo13/animal-facts/public-api/widget"
+)
+
+const (
+	oEmbedVersion      = "1.0"
+	oEmbedProviderName = "Animal Facts"
+	// oEmbedHeight is the height consumers should reserve for the snippet, facts are short enough to fit
+	oEmbedHeight = 200
+	// embedCacheAge allows consumers to cache the embeds of a fact for a day, the widget loads the current fact anyway
+	embedCacheAge = 86400
+
+	baseUrlContextKey = "api.baseUrl"
+)
+
+// OEmbedResult is the oEmbed response of the rich type, see https://oembed.com.
+type OEmbedResult struct {
+	XMLName      xml.Name `json:"-" xml:"oembed"`
+	Type         string   `json:"type" xml:"type"`
+	Version      string   `json:"version" xml:"version"`
+	Title        string   `json:"title" xml:"title"`
+	AuthorName   string   `json:"author_name,omitempty" xml:"author_name,omitempty"`
+	AuthorURL    string   `json:"author_url,omitempty" xml:"author_url,omitempty"`
+	ProviderName string   `json:"provider_name" xml:"provider_name"`
+	ProviderURL  string   `json:"provider_url" xml:"provider_url"`
+	CacheAge     int      `json:"cache_age" xml:"cache_age"`
+	HTML         string   `json:"html" xml:"html"`
+	Width        int      `json:"width" xml:"width"`
+	Height       int      `json:"height" xml:"height"`
+}
+
+type OEmbedApi struct {
+	oEmbedApiRoutes []router.Route
+	factsHandler    *handler.FactsHandler
+	cachePolicies   httpcache.Policies
+}
+
+func NewOEmbedApi(factsHandler *handler.FactsHandler, cachePolicies httpcache.Policies) *OEmbedApi {
+	return &OEmbedApi{factsHandler: factsHandler, cachePolicies: cachePolicies}
+}
+
+func (o *OEmbedApi) SetupRoutes() {
+	o.oEmbedApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/facts/random/embed.html",
+			HandlerFunc: o.getEmbed,
+			Middlewares: []echo.MiddlewareFunc{o.cachePolicies.Middleware("random")},
+		},
+		{
+			Method:      "GET",
+			Path:        "/facts/:id/embed.html",
+			HandlerFunc: o.getEmbed,
+			Middlewares: []echo.MiddlewareFunc{o.cachePolicies.Middleware("embed")},
+		},
+		{
+			Method:      "GET",
+			Path:        "/embed/widget.js",
+			HandlerFunc: o.getWidget,
+			Middlewares: []echo.MiddlewareFunc{o.cachePolicies.Middleware("widget")},
+		},
+	}
+}
+
+func (o *OEmbedApi) GetRoutes() []router.Route {
+	return o.oEmbedApiRoutes
+}
+
+// DiscoveryRoutes returns the routes of the oEmbed endpoint, which consumers discover at a fixed path, so they are
+// registered without the prefix of a version.
+func (o *OEmbedApi) DiscoveryRoutes() []router.Route {
+	return []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/oembed",
+			HandlerFunc: o.getOEmbed,
+			Middlewares: []echo.MiddlewareFunc{o.cachePolicies.Middleware("embed")},
+		},
+	}
+}
+
+// getOEmbed
+//
+//	@Summary      gets oEmbed of fact
+//	@Description  implements the oEmbed spec (https://oembed.com) for fact URLs like /api/v1/facts/{id} and /api/v1/facts/random,
+//	@Description  the html of the rich response is the embeddable snippet of the fact with the widget script
+//	@Produce      json,xml
+//	@Param        url        query  string  true   "URL of the fact"
+//	@Param        format     query  string  false  "json (default) or xml"
+//	@Param        maxwidth   query  int     false  "maximum width in pixels"
+//	@Param        maxheight  query  int     false  "maximum height in pixels"
+//	@Success      200  {object}  OEmbedResult
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Failure      501  {object}  problem.Problem
+//	@Router       /oembed [get]
+func (o *OEmbedApi) getOEmbed(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "xml" {
+		return problem.NotImplemented("format must be json or xml")
+	}
+
+	rawFactUrl := c.QueryParam("url")
+	if rawFactUrl == "" {
+		return problem.BadRequest("url query parameter is required")
+	}
+	factID, ok := parseFactUrl(rawFactUrl)
+	if !ok {
+		return problem.NotFound(fmt.Sprintf("url '%s' is not the url of a fact", rawFactUrl))
+	}
+
+	width, err := parseOEmbedDimension(c.QueryParam("maxwidth"), widget.DefaultWidth)
+	if err != nil {
+		return problem.BadRequest(fmt.Sprintf("maxwidth %v", err))
+	}
+	height, err := parseOEmbedDimension(c.QueryParam("maxheight"), oEmbedHeight)
+	if err != nil {
+		return problem.BadRequest(fmt.Sprintf("maxheight %v", err))
+	}
+
+	fact, snippet, err := o.getSnippet(c, factID, width)
+	if errors.Is(err, handler.ErrNotFound) {
+		return problem.NotFound(fmt.Sprintf("fact with ID '%s' not found", factID))
+	} else if err != nil {
+		return err
+	}
+
+	result := OEmbedResult{
+		Type:         "rich",
+		Version:      oEmbedVersion,
+		Title:        fact.Fact,
+		AuthorName:   fact.Source,
+		AuthorURL:    (widget.Snippet{Source: fact.Source}).SourceURL(),
+		ProviderName: oEmbedProviderName,
+		ProviderURL:  requestBaseUrl(c) + "/",
+		CacheAge:     embedCacheAge,
+		HTML:         snippet,
+		Width:        width,
+		Height:       height,
+	}
+	if format == "xml" {
+		return c.XML(http.StatusOK, result)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// getEmbed
+//
+//	@Summary      gets embeddable fact
+//	@Description  gets the embeddable HTML snippet of the fact (/facts/{id}/embed.html) or of a random fact (/facts/random/embed.html),
+//	@Description  the widget script of the snippet loads the current fact, a random snippet loads another random fact on every page view
+//	@Produce      html
+//	@Param        maxwidth  query  int  false  "maximum width in pixels"
+//	@Success      200  {string}  string
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/:id/embed.html [get]
+func (o *OEmbedApi) getEmbed(c echo.Context) error {
+	factID := c.Param("id")
+	if factID == "" {
+		factID = widget.RandomFactID
+	} else if _, err := primitive.ObjectIDFromHex(factID); err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	width, err := parseOEmbedDimension(c.QueryParam("maxwidth"), widget.DefaultWidth)
+	if err != nil {
+		return problem.BadRequest(fmt.Sprintf("maxwidth %v", err))
+	}
+
+	_, snippet, err := o.getSnippet(c, factID, width)
+	if errors.Is(err, handler.ErrNotFound) {
+		return problem.NotFound(fmt.Sprintf("fact with ID '%s' not found", factID))
+	} else if err != nil {
+		return err
+	}
+
+	// oEmbed discovery, see https://oembed.com/#section4
+	factUrl := fmt.Sprintf("%s/%s/facts/%s", requestBaseUrl(c), basePathV1, factID)
+	oEmbedUrl := fmt.Sprintf("%s/oembed?url=%s", requestBaseUrl(c), url.QueryEscape(factUrl))
+	header := c.Response().Header()
+	header.Add("Link", fmt.Sprintf(`<%s>; rel="alternate"; type="application/json+oembed"`, oEmbedUrl))
+	header.Add("Link", fmt.Sprintf(`<%s&format=xml>; rel="alternate"; type="text/xml+oembed"`, oEmbedUrl))
+
+	return c.HTML(http.StatusOK, snippet)
+}
+
+// getWidget
+//
+//	@Summary      gets embed widget
+//	@Description  gets the script of the embeddable snippets, it renders every blockquote with class animal-fact and a data-fact-id
+//	@Description  attribute (a fact ID or random) with the current fact
+//	@Produce      application/javascript
+//	@Success      200  {string}  string
+//	@Router       /embed/widget.js [get]
+func (o *OEmbedApi) getWidget(c echo.Context) error {
+	return c.Blob(http.StatusOK, "application/javascript; charset=utf-8", widget.Script())
+}
+
+// getSnippet returns the fact with its snippet, factID is the ID of a fact or widget.RandomFactID.
+func (o *OEmbedApi) getSnippet(c echo.Context, factID string, width int) (*handler.Fact, string, error) {
+	var fact *handler.Fact
+	if factID == widget.RandomFactID {
+		var err error
+		fact, err = o.factsHandler.WithContext(c.Request().Context()).GetRandomApproved()
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		objID, err := primitive.ObjectIDFromHex(factID)
+		if err != nil {
+			return nil, "", handler.ErrNotFound
+		}
+		fact, err = o.factsHandler.WithContext(c.Request().Context()).Get(objID)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	baseUrl := requestBaseUrl(c)
+	snippet, err := widget.Snippet{
+		FactID:    factID,
+		Fact:      fact.Fact,
+		Source:    fact.Source,
+		FactURL:   fmt.Sprintf("%s/%s/facts/%s", baseUrl, basePathV1, fact.ID),
+		ScriptURL: fmt.Sprintf("%s/%s/embed/widget.js", baseUrl, basePathV1),
+		Width:     width,
+	}.Render()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return fact, snippet, nil
+}
+
+// parseFactUrl returns the fact ID of a fact URL of this API, or widget.RandomFactID for the URL of random facts. The
+// host is not checked, so that the URLs work behind proxies and with other domains of the API.
+func parseFactUrl(rawFactUrl string) (string, bool) {
+	factUrl, err := url.Parse(rawFactUrl)
+	if err != nil || (factUrl.Scheme != "https" && factUrl.Scheme != "http") {
+		return "", false
+	}
+
+	factID, found := strings.CutPrefix(factUrl.Path, fmt.Sprintf("/%s/facts/", basePathV1))
+	factID = strings.TrimSuffix(factID, "/embed.html")
+	if !found || (factID != widget.RandomFactID && !primitive.IsValidObjectID(factID)) {
+		return "", false
+	}
+
+	return factID, true
+}
+
+// parseOEmbedDimension returns the maximum of the consumer, which is capped to the default of the provider.
+func parseOEmbedDimension(maximum string, defaultValue int) (int, error) {
+	if maximum == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(maximum)
+	if err != nil || value < 1 {
+		return 0, errors.New("must be a positive integer")
+	}
+
+	return min(value, defaultValue), nil
+}
+
+// BaseUrl is a middleware that sets the public base URL of the api (like https://animal-facts.cafo.dev) for the links
+// of the responses, e.g. the script of embed snippets. The links are not built from the Host header of the request, as
+// the responses can be stored by shared caches and a forged Host header would end up in them.
+func BaseUrl(baseUrl string) echo.MiddlewareFunc {
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(baseUrlContextKey, baseUrl)
+			return next(c)
+		}
+	}
+}
+
+// requestBaseUrl returns the base URL set by BaseUrl, routes without the middleware (e.g. in tests) use the host of the
+// request.
+func requestBaseUrl(c echo.Context) string {
+	if baseUrl, ok := c.Get(baseUrlContextKey).(string); ok {
+		return baseUrl
+	}
+
+	return fmt.Sprintf("%s://%s", c.Scheme(), c.Request().Host)
+}
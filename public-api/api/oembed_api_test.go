@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+func TestOEmbedApi_getOEmbed_baseUrl(t *testing.T) {
+	factsRepository := repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{whaleID: &whale}, false)
+	oEmbedApi := NewOEmbedApi(handler.NewFactsHandler(factsRepository), DefaultCachePolicies)
+	e := echo.New()
+	e.Use(BaseUrl("https://animal-facts.cafo.dev/"))
+	for _, route := range oEmbedApi.DiscoveryRoutes() {
+		e.Add(route.Method, route.Path, route.HandlerFunc, route.Middlewares...)
+	}
+
+	factUrl := "https://animal-facts.cafo.dev/api/v1/facts/" + whaleID.Hex()
+	request := httptest.NewRequest(http.MethodGet, "/oembed?url="+url.QueryEscape(factUrl), nil)
+	request.Host = "attacker.example"
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var result OEmbedResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("body = %s, want oEmbed result: %v", recorder.Body.String(), err)
+	}
+	if !strings.Contains(result.HTML, `src="https://animal-facts.cafo.dev/api/v1/embed/widget.js"`) ||
+		strings.Contains(result.HTML, "attacker.example") || result.ProviderURL != "https://animal-facts.cafo.dev/" {
+		t.Errorf("result = %+v, want the links built from the configured base URL instead of the Host header", result)
+	}
+}
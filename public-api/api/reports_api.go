@@ -0,0 +1,110 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/time/rate"
+
+	"github.com/cafo13/animal-facts/pkg/apikey"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/cafo13/animal-facts/public-api/render"
+)
+
+type CreateReportResult struct {
+	Id string `json:"id"`
+}
+
+type ReportsApi struct {
+	reportsApiRoutes []router.Route
+	reportsHandler   *handler.ReportsHandler
+}
+
+func NewReportsApi(reportsHandler *handler.ReportsHandler) *ReportsApi {
+	return &ReportsApi{reportsHandler: reportsHandler}
+}
+
+func (r *ReportsApi) SetupRoutes() {
+	r.reportsApiRoutes = []router.Route{
+		{
+			Method:      "POST",
+			Path:        "/facts/:id/reports",
+			HandlerFunc: r.createReport,
+			Scope:       apikey.ScopeCreateReport,
+			Middlewares: []echo.MiddlewareFunc{
+				render.Negotiate(),
+				reportsRateLimiter(),
+			},
+		},
+	}
+}
+
+func (r *ReportsApi) GetRoutes() []router.Route {
+	return r.reportsApiRoutes
+}
+
+// reportsRateLimiter allows every client (identified by its IP) to send a burst of 5 reports and one more report per minute after that.
+func reportsRateLimiter() echo.MiddlewareFunc {
+	return echoMiddleware.RateLimiterWithConfig(echoMiddleware.RateLimiterConfig{
+		Store: echoMiddleware.NewRateLimiterMemoryStoreWithConfig(echoMiddleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Every(time.Minute),
+			Burst:     5,
+			ExpiresIn: 10 * time.Minute,
+		}),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		ErrorHandler: func(c echo.Context, err error) error {
+			return problem.Forbidden("could not identify client")
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return problem.RateLimited("too many reports, please try again later", time.Minute)
+		},
+	})
+}
+
+// createReport
+//
+//	@Summary      report fact
+//	@Description  reports an inaccuracy or other problem of a fact, valid reasons are incorrect, outdated, missing-source, offensive and other
+//	@Accept       json
+//	@Produce      json,plain,xml,text/csv,application/yaml,text/markdown
+//	@Param        request body handler.Report true "report"
+//	@Success      201  {object}  CreateReportResult
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      404  {object}  problem.Problem
+//	@Failure      429  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/:id/reports [post]
+func (r *ReportsApi) createReport(c echo.Context) error {
+	id := c.Param("id")
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return problem.BadRequest("id from request path is not a valid object id in hex string format")
+	}
+
+	report := &handler.Report{}
+	if err := c.Bind(report); err != nil {
+		return problem.BadRequest(err.Error())
+	}
+
+	reportID, err := r.reportsHandler.Create(objID, report)
+	if errors.Is(err, handler.ErrInvalidReportReason) {
+		return problem.BadRequest(fmt.Sprintf("report reason '%s' is not valid", report.Reason))
+	} else if errors.Is(err, handler.ErrInvalidReportText) {
+		return problem.BadRequest("report text is too long")
+	} else if errors.Is(err, handler.ErrNotFound) {
+		return problem.NotFound(fmt.Sprintf("fact with ID '%s' not found", id))
+	} else if err != nil {
+		return err
+	}
+
+	return render.Render(c, http.StatusCreated, CreateReportResult{Id: reportID.Hex()})
+}
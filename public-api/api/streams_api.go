@@ -0,0 +1,51 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+)
+
+type StreamsApi struct {
+	streamsApiRoutes []router.Route
+	eventBus         *events.Bus
+}
+
+func NewStreamsApi(eventBus *events.Bus) *StreamsApi {
+	return &StreamsApi{eventBus: eventBus}
+}
+
+func (s *StreamsApi) SetupRoutes() {
+	s.streamsApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/facts/approved/stream",
+			HandlerFunc: s.streamApproved,
+		},
+	}
+}
+
+func (s *StreamsApi) GetRoutes() []router.Route {
+	return s.streamsApiRoutes
+}
+
+// streamApproved
+//
+//	@Summary      streams newly approved facts
+//	@Description  streams the facts as server-sent events of type fact.approved as soon as they are approved, clients resume after the last received event with the Last-Event-ID header
+//	@Produce      text/event-stream
+//	@Param        lastEventId    query   int  false  "ID of the last received event, the Last-Event-ID header takes precedence"
+//	@Param        Last-Event-ID  header  int  false  "ID of the last received event"
+//	@Success      200  {object}  events.Event
+//	@Failure      400  {object}  problem.Problem
+//	@Router       /facts/approved/stream [get]
+func (s *StreamsApi) streamApproved(c echo.Context) error {
+	lastEventID, err := events.LastEventID(c)
+	if err != nil {
+		return problem.BadRequest("last event ID must be a positive integer")
+	}
+
+	return events.ServeSSE(c, s.eventBus, lastEventID, events.TypeFilter(string(events.TypeFactApproved)))
+}
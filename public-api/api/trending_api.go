@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/router"
+	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/cafo13/animal-facts/public-api/render"
+)
+
+const (
+	defaultTrendingLimit = 10
+	maxTrendingLimit     = 50
+)
+
+type TrendingApi struct {
+	trendingApiRoutes []router.Route
+	trendingHandler   *handler.TrendingHandler
+}
+
+func NewTrendingApi(trendingHandler *handler.TrendingHandler) *TrendingApi {
+	return &TrendingApi{trendingHandler: trendingHandler}
+}
+
+func (t *TrendingApi) SetupRoutes() {
+	t.trendingApiRoutes = []router.Route{
+		{
+			Method:      "GET",
+			Path:        "/facts/trending",
+			HandlerFunc: t.getTrending,
+			Middlewares: []echo.MiddlewareFunc{
+				render.Negotiate(),
+			},
+		},
+	}
+}
+
+func (t *TrendingApi) GetRoutes() []router.Route {
+	return t.trendingApiRoutes
+}
+
+// getTrending
+//
+//	@Summary      gets trending facts
+//	@Description  gets the facts that were served most often recently, where older serves count less than newer ones
+//	@Produce      json,plain,xml,text/csv,application/yaml,text/markdown
+//	@Param        limit  query  int  false  "maximum number of facts (1-50, default 10)"
+//	@Success      200  {array}   Fact
+//	@Failure      400  {object}  problem.Problem
+//	@Failure      500  {object}  problem.Problem
+//	@Router       /facts/trending [get]
+func (t *TrendingApi) getTrending(c echo.Context) error {
+	limit, err := parseTrendingLimit(c)
+	if err != nil {
+		return err
+	}
+
+	facts, err := t.trendingHandler.GetTrending(limit)
+	if err != nil {
+		return err
+	}
+
+	v1Facts := toFacts(facts)
+	return render.Render(c, http.StatusOK, &v1Facts)
+}
+
+func parseTrendingLimit(c echo.Context) (int, error) {
+	limit := defaultTrendingLimit
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		var err error
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxTrendingLimit {
+			return 0, problem.BadRequest(fmt.Sprintf("limit must be an integer between 1 and %d", maxTrendingLimit))
+		}
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,295 @@
+package card
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// LayoutVersion changes whenever the rendering changes, so that cached cards get invalidated.
+	LayoutVersion = 1
+
+	DefaultWidth  = 1200
+	DefaultHeight = 630
+	MinWidth      = 300
+	MaxWidth      = 2400
+	MinHeight     = 150
+	MaxHeight     = 2400
+
+	minFactFontSize = 8.0
+	lineSpacing     = 1.3
+	fontFamily      = "GoRegular"
+)
+
+type Theme struct {
+	Name       string
+	Background color.RGBA
+	Text       color.RGBA
+	Muted      color.RGBA
+	Accent     color.RGBA
+}
+
+var (
+	ThemeLight = Theme{
+		Name:       "light",
+		Background: color.RGBA{R: 0xfa, G: 0xfa, B: 0xf7, A: 0xff},
+		Text:       color.RGBA{R: 0x1f, G: 0x29, B: 0x37, A: 0xff},
+		Muted:      color.RGBA{R: 0x6b, G: 0x72, B: 0x80, A: 0xff},
+		Accent:     color.RGBA{R: 0x2e, G: 0x7d, B: 0x32, A: 0xff},
+	}
+	ThemeDark = Theme{
+		Name:       "dark",
+		Background: color.RGBA{R: 0x11, G: 0x18, B: 0x27, A: 0xff},
+		Text:       color.RGBA{R: 0xf3, G: 0xf4, B: 0xf6, A: 0xff},
+		Muted:      color.RGBA{R: 0x9c, G: 0xa3, B: 0xaf, A: 0xff},
+		Accent:     color.RGBA{R: 0x66, G: 0xbb, B: 0x6a, A: 0xff},
+	}
+	ThemeBrand = Theme{
+		Name:       "brand",
+		Background: color.RGBA{R: 0x2e, G: 0x7d, B: 0x32, A: 0xff},
+		Text:       color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		Muted:      color.RGBA{R: 0xc8, G: 0xe6, B: 0xc9, A: 0xff},
+		Accent:     color.RGBA{R: 0xff, G: 0xc1, B: 0x07, A: 0xff},
+	}
+
+	Themes = []Theme{ThemeLight, ThemeDark, ThemeBrand}
+)
+
+type Options struct {
+	Width  int
+	Height int
+	Theme  Theme
+}
+
+var parseFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(goregular.TTF)
+})
+
+type textLine struct {
+	text     string
+	baseline int
+}
+
+type layout struct {
+	padding    int
+	accentBar  image.Rectangle
+	factSize   float64
+	factLines  []textLine
+	sourceSize float64
+	sourceLine *textLine
+}
+
+func newFace(size float64) (font.Face, error) {
+	f, err := parseFont()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse embedded font")
+	}
+
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+}
+
+// computeLayout wraps the fact with the largest font size that fits the card and places the source attribution below.
+func computeLayout(fact string, source string, options Options) (*layout, error) {
+	minSide := min(options.Width, options.Height)
+	l := &layout{padding: max(8, minSide*6/100)}
+	l.accentBar = image.Rect(0, 0, max(4, l.padding/4), options.Height)
+	textLeft := l.padding + l.accentBar.Dx()
+	textWidth := options.Width - textLeft - l.padding
+	bottom := options.Height - l.padding
+
+	attribution := attributionText(source)
+	if attribution != "" {
+		l.sourceSize = math.Max(10, math.Min(40, float64(options.Height)*0.045))
+		sourceFace, err := newFace(l.sourceSize)
+		if err != nil {
+			return nil, err
+		}
+		l.sourceLine = &textLine{text: truncate(sourceFace, attribution, textWidth), baseline: bottom}
+		sourceFace.Close()
+		bottom -= int(l.sourceSize*lineSpacing) + l.padding/2
+	}
+
+	availableHeight := bottom - l.padding
+	for size := math.Max(minFactFontSize, float64(options.Height)*0.12); ; size *= 0.92 {
+		if size < minFactFontSize {
+			size = minFactFontSize
+		}
+
+		face, err := newFace(size)
+		if err != nil {
+			return nil, err
+		}
+
+		lines := wrap(face, fact, textWidth)
+		lineHeight := int(size * lineSpacing)
+		maxLines := max(1, availableHeight/lineHeight)
+		if len(lines) > maxLines && size > minFactFontSize {
+			face.Close()
+			continue
+		}
+
+		if len(lines) > maxLines {
+			lines = lines[:maxLines]
+			lines[maxLines-1] = truncate(face, lines[maxLines-1]+"…", textWidth)
+		}
+
+		l.factSize = size
+		ascent := face.Metrics().Ascent.Ceil()
+		for i, line := range lines {
+			l.factLines = append(l.factLines, textLine{text: line, baseline: l.padding + ascent + i*lineHeight})
+		}
+		face.Close()
+		return l, nil
+	}
+}
+
+// attributionText shortens URL sources to host and path, other sources are used as they are.
+func attributionText(source string) string {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return ""
+	}
+
+	if u, err := url.Parse(source); err == nil && u.Host != "" {
+		source = strings.TrimSuffix(strings.TrimPrefix(u.Host, "www.")+u.Path, "/")
+	}
+
+	return "Source: " + source
+}
+
+// wrap breaks the text into lines that fit into the width, words that are too long on their own are split.
+func wrap(face font.Face, text string, width int) []string {
+	maxWidth := fixed.I(width)
+	var lines []string
+	current := ""
+	for _, word := range strings.FieldsFunc(text, unicode.IsSpace) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if font.MeasureString(face, candidate) <= maxWidth {
+			current = candidate
+			continue
+		}
+
+		if current != "" {
+			lines = append(lines, current)
+		}
+		current = ""
+		for _, r := range word {
+			if current != "" && font.MeasureString(face, current+string(r)) > maxWidth {
+				lines = append(lines, current)
+				current = ""
+			}
+			current += string(r)
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// truncate shortens the text with an ellipsis until it fits into the width.
+func truncate(face font.Face, text string, width int) string {
+	runes := []rune(text)
+	for len(runes) > 1 && font.MeasureString(face, string(runes)) > fixed.I(width) {
+		runes = append(runes[:len(runes)-2], '…')
+	}
+
+	return string(runes)
+}
+
+func RenderPNG(fact string, source string, options Options) ([]byte, error) {
+	l, err := computeLayout(fact, source, options)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, options.Width, options.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(options.Theme.Background), image.Point{}, draw.Src)
+	draw.Draw(img, l.accentBar, image.NewUniform(options.Theme.Accent), image.Point{}, draw.Src)
+
+	textLeft := l.padding + l.accentBar.Dx()
+	drawLines := func(size float64, textColor color.RGBA, lines []textLine) error {
+		face, err := newFace(size)
+		if err != nil {
+			return err
+		}
+		defer face.Close()
+
+		drawer := &font.Drawer{Dst: img, Src: image.NewUniform(textColor), Face: face}
+		for _, line := range lines {
+			drawer.Dot = fixed.P(textLeft, line.baseline)
+			drawer.DrawString(line.text)
+		}
+		return nil
+	}
+
+	if err := drawLines(l.factSize, options.Theme.Text, l.factLines); err != nil {
+		return nil, err
+	}
+	if l.sourceLine != nil {
+		if err := drawLines(l.sourceSize, options.Theme.Muted, []textLine{*l.sourceLine}); err != nil {
+			return nil, err
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, errors.Wrap(err, "failed to encode png")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// RenderSVG renders the same layout as RenderPNG, the font is embedded so that the text looks the same everywhere.
+func RenderSVG(fact string, source string, options Options) ([]byte, error) {
+	l, err := computeLayout(fact, source, options)
+	if err != nil {
+		return nil, err
+	}
+
+	hex := func(c color.RGBA) string {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	escape := func(text string) string {
+		var buffer bytes.Buffer
+		_ = xml.EscapeText(&buffer, []byte(text))
+		return buffer.String()
+	}
+
+	textLeft := l.padding + l.accentBar.Dx()
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, options.Width, options.Height, options.Width, options.Height)
+	fmt.Fprintf(&svg, `<defs><style>@font-face{font-family:"%s";src:url(data:font/ttf;base64,%s) format("truetype");}</style></defs>`, fontFamily, base64.StdEncoding.EncodeToString(goregular.TTF))
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`, hex(options.Theme.Background))
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="%s"/>`, l.accentBar.Dx(), l.accentBar.Dy(), hex(options.Theme.Accent))
+	for _, line := range l.factLines {
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" font-family="%s" font-size="%.2f" fill="%s" xml:space="preserve">%s</text>`, textLeft, line.baseline, fontFamily, l.factSize, hex(options.Theme.Text), escape(line.text))
+	}
+	if l.sourceLine != nil {
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" font-family="%s" font-size="%.2f" fill="%s" xml:space="preserve">%s</text>`, textLeft, l.sourceLine.baseline, fontFamily, l.sourceSize, hex(options.Theme.Muted), escape(l.sourceLine.text))
+	}
+	svg.WriteString(`</svg>`)
+
+	return []byte(svg.String()), nil
+}
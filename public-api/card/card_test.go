@@ -0,0 +1,86 @@
+package card
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+const exampleFact = "The Blue Whale is the largest animal that has ever lived."
+
+func TestRenderPNG(t *testing.T) {
+	tests := []struct {
+		name    string
+		fact    string
+		options Options
+	}{
+		{name: "default size", fact: exampleFact, options: Options{Width: DefaultWidth, Height: DefaultHeight, Theme: ThemeLight}},
+		{name: "minimum size with long fact", fact: strings.Repeat(exampleFact+" ", 20), options: Options{Width: MinWidth, Height: MinHeight, Theme: ThemeDark}},
+		{name: "long word", fact: strings.Repeat("a", 500), options: Options{Width: 400, Height: 400, Theme: ThemeBrand}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderPNG(tt.fact, "https://factanimal.com/blue-whale/", tt.options)
+			if err != nil {
+				t.Errorf("RenderPNG() unexpected error = %v", err)
+				return
+			}
+
+			img, err := png.Decode(bytes.NewReader(got))
+			if err != nil {
+				t.Errorf("RenderPNG() returned invalid png: %v", err)
+				return
+			}
+			if img.Bounds().Dx() != tt.options.Width || img.Bounds().Dy() != tt.options.Height {
+				t.Errorf("RenderPNG() size = %dx%d, want %dx%d", img.Bounds().Dx(), img.Bounds().Dy(), tt.options.Width, tt.options.Height)
+			}
+			if r, g, b, _ := img.At(img.Bounds().Dx()-1, img.Bounds().Dy()-1).RGBA(); uint8(r>>8) != tt.options.Theme.Background.R || uint8(g>>8) != tt.options.Theme.Background.G || uint8(b>>8) != tt.options.Theme.Background.B {
+				t.Errorf("RenderPNG() background doesn't match theme %s", tt.options.Theme.Name)
+			}
+		})
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	got, err := RenderSVG("Cats & dogs <3", "", Options{Width: DefaultWidth, Height: DefaultHeight, Theme: ThemeLight})
+	if err != nil {
+		t.Errorf("RenderSVG() unexpected error = %v", err)
+		return
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(got))
+	var texts []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		if charData, ok := token.(xml.CharData); ok && strings.TrimSpace(string(charData)) != "" && !strings.Contains(string(charData), "@font-face") {
+			texts = append(texts, string(charData))
+		}
+	}
+	if strings.Join(texts, " ") != "Cats & dogs <3" {
+		t.Errorf("RenderSVG() texts = %q, want the fact", texts)
+	}
+}
+
+func Test_computeLayout(t *testing.T) {
+	options := Options{Width: MinWidth, Height: MinHeight, Theme: ThemeLight}
+	l, err := computeLayout(strings.Repeat(exampleFact+" ", 50), "https://www.factanimal.com/blue-whale/", options)
+	if err != nil {
+		t.Errorf("computeLayout() unexpected error = %v", err)
+		return
+	}
+
+	if l.factSize != minFactFontSize {
+		t.Errorf("computeLayout() fact size = %v, want %v", l.factSize, minFactFontSize)
+	}
+	if last := l.factLines[len(l.factLines)-1]; !strings.HasSuffix(last.text, "…") || last.baseline > l.sourceLine.baseline {
+		t.Errorf("computeLayout() last line = %+v, want truncated line above the source", last)
+	}
+	if l.sourceLine.text != "Source: factanimal.com/blue-whale" {
+		t.Errorf("computeLayout() source = %q", l.sourceLine.text)
+	}
+}
@@ -19,30 +19,84 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/embed/widget.js": {
+            "get": {
+                "description": "gets the script of the embeddable snippets, it renders every blockquote with class animal-fact and a data-fact-id\nattribute (a fact ID or random) with the current fact",
+                "produces": [
+                    "application/javascript"
+                ],
+                "summary": "gets embed widget",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/facts": {
             "get": {
-                "description": "gets random fact from the database",
+                "description": "gets random fact from the database\nwith the count query parameter, a list of that many distinct random facts is returned instead of a single fact\nwith the seed query parameter, the same seed always returns the same facts as long as the facts don't change\nwith the shuffle query parameter, facts don't repeat until all facts were returned: pass an empty value to\nstart and the value of the Shuffle-Token response header of the previous response to continue",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "text/markdown"
                 ],
                 "summary": "gets random fact",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "number of distinct random facts (1-50)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "seed for a reproducible selection",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "shuffle token",
+                        "name": "shuffle",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.Fact"
+                            "$ref": "#/definitions/api.Fact"
+                        },
+                        "headers": {
+                            "Shuffle-Token": {
+                                "type": "string",
+                                "description": "shuffle token for the next request, only set if the shuffle query parameter was passed"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -50,39 +104,251 @@ const docTemplate = `{
         },
         "/facts/:id": {
             "get": {
-                "description": "gets fact by ID from the database",
+                "description": "gets fact by ID from the database, the response has an ETag and a Last-Modified header for conditional requests",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "text/markdown"
                 ],
                 "summary": "gets fact",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.Fact"
+                            "$ref": "#/definitions/api.Fact"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
             }
         },
-        "/facts/count": {
+        "/facts/:id/card.png": {
             "get": {
-                "description": "gets fact count from the database",
+                "description": "renders the fact with its source as PNG (card.png) or SVG (card.svg) image, cards can be cached by their ETag",
                 "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "summary": "gets fact card",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "width in pixels (300-2400, default 1200)",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "height in pixels (150-2400, default 630)",
+                        "name": "height",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "light (default), dark or brand",
+                        "name": "theme",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/:id/embed.html": {
+            "get": {
+                "description": "gets the embeddable HTML snippet of the fact (/facts/{id}/embed.html) or of a random fact (/facts/random/embed.html),\nthe widget script of the snippet loads the current fact, a random snippet loads another random fact on every page view",
+                "produces": [
+                    "text/html"
+                ],
+                "summary": "gets embeddable fact",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maximum width in pixels",
+                        "name": "maxwidth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/:id/reports": {
+            "post": {
+                "description": "reports an inaccuracy or other problem of a fact, valid reasons are incorrect, outdated, missing-source, offensive and other",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "text/markdown"
+                ],
+                "summary": "report fact",
+                "parameters": [
+                    {
+                        "description": "report",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.Report"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateReportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/approved/stream": {
+            "get": {
+                "description": "streams the facts as server-sent events of type fact.approved as soon as they are approved, clients resume after the last received event with the Last-Event-ID header",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "summary": "streams newly approved facts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the last received event, the Last-Event-ID header takes precedence",
+                        "name": "lastEventId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/count": {
+            "get": {
+                "description": "gets fact count from the database, the response has an ETag for conditional requests",
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "text/markdown"
+                ],
                 "summary": "gets fact count",
                 "responses": {
                     "200": {
@@ -91,10 +357,305 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.CountResult"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/list": {
+            "get": {
+                "description": "lists facts page by page ordered by creation time, the URL of the next page is in the Link response header",
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "text/markdown"
+                ],
+                "summary": "lists facts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "only facts about this animal",
+                        "name": "animal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only facts with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only facts in this language",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only facts created after this RFC 3339 timestamp",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created (oldest first, default) or -created (newest first)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "facts per page (1-100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of the page, taken from the Link header of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated fields to return (id, fact, source), all if empty",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.SparseFact"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "link to the next page with rel=next, not set on the last page"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResult"
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/random/card.png": {
+            "get": {
+                "description": "redirects to the card of a random fact, the query parameters are passed on",
+                "summary": "gets random fact card",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "width in pixels (300-2400, default 1200)",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "height in pixels (150-2400, default 630)",
+                        "name": "height",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "light (default), dark or brand",
+                        "name": "theme",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/facts/trending": {
+            "get": {
+                "description": "gets the facts that were served most often recently, where older serves count less than newer ones",
+                "produces": [
+                    "application/json",
+                    "text/plain",
+                    "text/xml",
+                    "text/csv",
+                    "application/yaml",
+                    "text/markdown"
+                ],
+                "summary": "gets trending facts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maximum number of facts (1-50, default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.Fact"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/latest.rss": {
+            "get": {
+                "description": "gets the most recently approved facts as RSS 2.0 (latest.rss), Atom (latest.atom) or JSON Feed 1.1 (feed.json)",
+                "produces": [
+                    "application/rss+xml",
+                    "application/atom+xml",
+                    "application/feed+json"
+                ],
+                "summary": "gets feed of latest facts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "only facts about this animal",
+                        "name": "animal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only facts with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/oembed": {
+            "get": {
+                "description": "implements the oEmbed spec (https://oembed.com) for fact URLs like /api/v1/facts/{id} and /api/v1/facts/random,\nthe html of the rich response is the embeddable snippet of the fact with the widget script",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "summary": "gets oEmbed of fact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "URL of the fact",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or xml",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum width in pixels",
+                        "name": "maxwidth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum height in pixels",
+                        "name": "maxheight",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.OEmbedResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
                         }
                     }
                 }
@@ -110,15 +671,67 @@ const docTemplate = `{
                 }
             }
         },
-        "api.ErrorResult": {
+        "api.CreateReportResult": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.Fact": {
             "type": "object",
             "properties": {
-                "error": {
+                "fact": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "source": {
                     "type": "string"
                 }
             }
         },
-        "handler.Fact": {
+        "api.OEmbedResult": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "author_url": {
+                    "type": "string"
+                },
+                "cache_age": {
+                    "type": "integer"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "html": {
+                    "type": "string"
+                },
+                "provider_name": {
+                    "type": "string"
+                },
+                "provider_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.SparseFact": {
             "type": "object",
             "properties": {
                 "fact": {
@@ -131,6 +744,139 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "factId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/events.Type"
+                }
+            }
+        },
+        "events.Type": {
+            "type": "string",
+            "enum": [
+                "fact.created",
+                "fact.updated",
+                "fact.approved",
+                "fact.unapproved",
+                "fact.deleted"
+            ],
+            "x-enum-varnames": [
+                "TypeFactCreated",
+                "TypeFactUpdated",
+                "TypeFactApproved",
+                "TypeFactUnapproved",
+                "TypeFactDeleted"
+            ]
+        },
+        "handler.Report": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "problem.Code": {
+            "type": "string",
+            "enum": [
+                "bad_request",
+                "validation_failed",
+                "unauthorized",
+                "forbidden",
+                "not_found",
+                "method_not_allowed",
+                "not_acceptable",
+                "conflict",
+                "unsupported_media_type",
+                "rate_limited",
+                "internal_error",
+                "not_implemented",
+                "service_unavailable"
+            ],
+            "x-enum-varnames": [
+                "CodeBadRequest",
+                "CodeValidationFailed",
+                "CodeUnauthorized",
+                "CodeForbidden",
+                "CodeNotFound",
+                "CodeMethodNotAllowed",
+                "CodeNotAcceptable",
+                "CodeConflict",
+                "CodeUnsupportedMedia",
+                "CodeRateLimited",
+                "CodeInternal",
+                "CodeNotImplemented",
+                "CodeUnavailable"
+            ]
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the machine readable code of the problem, the last segment of the type.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/problem.Code"
+                        }
+                    ]
+                },
+                "correlationId": {
+                    "description": "CorrelationID is the ID of the request in the logs, it is the X-Request-ID header of the response.",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Errors are the field errors of requests that failed the validation.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/validation.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "validation.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Field is the JSON name of the field, items of lists have their index like tags[1].",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
         }
     },
     "externalDocs": {
@@ -0,0 +1,260 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"time"
+)
+
+const (
+	MediaTypeRSS      = "application/rss+xml"
+	MediaTypeAtom     = "application/atom+xml"
+	MediaTypeJSONFeed = "application/feed+json"
+
+	atomNamespace   = "http://www.w3.org/2005/Atom"
+	jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+)
+
+// Feed is the format independent content of a feed, all URLs are absolute.
+type Feed struct {
+	Title       string
+	Description string
+	// HomePageURL is the URL of the website the feed belongs to.
+	HomePageURL string
+	// FeedURL is the URL the feed itself is served at.
+	FeedURL string
+	Author  string
+	Updated time.Time
+	Items   []*Item
+}
+
+type Item struct {
+	// ID is a permanent and unique URL of the item.
+	ID        string
+	URL       string
+	Title     string
+	Content   string
+	Source    string
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders the feed as RSS 2.0.
+func RSS(feed *Feed) ([]byte, error) {
+	document := rss{
+		Version: "2.0",
+		AtomNS:  atomNamespace,
+		Channel: rssChannel{
+			Title:       feed.Title,
+			Link:        feed.HomePageURL,
+			Description: feed.Description,
+			AtomLink:    atomLink{Href: feed.FeedURL, Rel: "self", Type: MediaTypeRSS},
+		},
+	}
+	if !feed.Updated.IsZero() {
+		document.Channel.LastBuildDate = feed.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range feed.Items {
+		rssItem := rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			Description: item.Content,
+			GUID:        rssGUID{IsPermaLink: item.ID == item.URL, Value: item.ID},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Categories:  item.Tags,
+		}
+		// the source element of RSS refers to another RSS feed, so the source of the fact is part of the description
+		if item.Source != "" {
+			rssItem.Description += "\n\nSource: " + item.Source
+		}
+		document.Channel.Items = append(document.Channel.Items, rssItem)
+	}
+
+	return marshalXML(document)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Summary string      `xml:"subtitle"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Links      []atomLink     `xml:"link"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom renders the feed as Atom 1.0 (RFC 4287).
+func Atom(feed *Feed) ([]byte, error) {
+	document := atomFeed{
+		XMLNS:   atomNamespace,
+		ID:      feed.FeedURL,
+		Title:   feed.Title,
+		Summary: feed.Description,
+		Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: feed.Author},
+		Links: []atomLink{
+			{Href: feed.FeedURL, Rel: "self", Type: MediaTypeAtom},
+			{Href: feed.HomePageURL, Rel: "alternate"},
+		},
+	}
+
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Href: item.URL, Rel: "alternate"}},
+			Content:   atomContent{Type: "text", Value: item.Content},
+		}
+		if isAbsoluteURL(item.Source) {
+			entry.Links = append(entry.Links, atomLink{Href: item.Source, Rel: "related"})
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		document.Entries = append(document.Entries, entry)
+	}
+
+	return marshalXML(document)
+}
+
+type jsonFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url,omitempty"`
+	FeedURL     string           `json:"feed_url,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Authors     []jsonFeedAuthor `json:"authors,omitempty"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url,omitempty"`
+	ExternalURL   string   `json:"external_url,omitempty"`
+	Title         string   `json:"title,omitempty"`
+	ContentText   string   `json:"content_text"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// JSONFeed renders the feed as JSON Feed 1.1.
+func JSONFeed(feed *Feed) ([]byte, error) {
+	document := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       feed.Title,
+		HomePageURL: feed.HomePageURL,
+		FeedURL:     feed.FeedURL,
+		Description: feed.Description,
+		Items:       []jsonFeedItem{},
+	}
+	if feed.Author != "" {
+		document.Authors = []jsonFeedAuthor{{Name: feed.Author}}
+	}
+
+	for _, item := range feed.Items {
+		jsonItem := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentText:   item.Content,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		if isAbsoluteURL(item.Source) {
+			jsonItem.ExternalURL = item.Source
+		}
+		document.Items = append(document.Items, jsonItem)
+	}
+
+	return json.Marshal(document)
+}
+
+func marshalXML(document interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buffer)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	buffer.WriteString("\n")
+
+	return buffer.Bytes(), nil
+}
+
+func isAbsoluteURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
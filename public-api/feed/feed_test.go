@@ -0,0 +1,329 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+var exampleFeed = &Feed{
+	Title:       "Animal Facts",
+	Description: "The latest approved facts about animals",
+	HomePageURL: "https://animal-facts.cafo.dev/",
+	FeedURL:     "https://animal-facts.cafo.dev/api/v1/feeds/latest.rss?tag=ocean",
+	Author:      "Animal Facts",
+	Updated:     time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+	Items: []*Item{
+		{
+			ID:        "https://animal-facts.cafo.dev/api/v1/facts/6578bf140e487ecc049c7594",
+			URL:       "https://animal-facts.cafo.dev/api/v1/facts/6578bf140e487ecc049c7594",
+			Title:     "The Blue Whale is the largest animal that has ever lived.",
+			Content:   "The Blue Whale is the largest animal that has ever lived.",
+			Source:    "https://factanimal.com/blue-whale/",
+			Tags:      []string{"ocean", "mammal"},
+			Published: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			Updated:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			ID:        "https://animal-facts.cafo.dev/api/v1/facts/6578bf140e487ecc049c7595",
+			URL:       "https://animal-facts.cafo.dev/api/v1/facts/6578bf140e487ecc049c7595",
+			Title:     "Octopuses have three hearts & blue blood.",
+			Content:   "Octopuses have three hearts & blue blood.",
+			Source:    "some book",
+			Published: time.Date(2024, 1, 14, 8, 0, 0, 0, time.FixedZone("CET", 3600)),
+			Updated:   time.Date(2024, 1, 14, 9, 0, 0, 0, time.FixedZone("CET", 3600)),
+		},
+	},
+}
+
+func assertAbsoluteURL(t *testing.T, name string, value string) {
+	t.Helper()
+	if u, err := url.Parse(value); err != nil || !u.IsAbs() || u.Host == "" {
+		t.Errorf("%s = %q is not an absolute URL", name, value)
+	}
+}
+
+func assertUnique(t *testing.T, name string, values []string) {
+	t.Helper()
+	seen := map[string]bool{}
+	for _, value := range values {
+		if seen[value] {
+			t.Errorf("%s %q is not unique", name, value)
+		}
+		seen[value] = true
+	}
+}
+
+// TestRSS validates the feed against the RSS 2.0 specification (https://www.rssboard.org/rss-specification).
+func TestRSS(t *testing.T) {
+	body, err := RSS(exampleFeed)
+	if err != nil {
+		t.Fatalf("RSS() unexpected error = %v", err)
+	}
+
+	var document struct {
+		XMLName xml.Name
+		Version string `xml:"version,attr"`
+		Channel []struct {
+			Title       []string `xml:"title"`
+			Description []string `xml:"description"`
+			// links contains both the RSS link and the atom:link elements, they are told apart by their namespace
+			Links []struct {
+				XMLName xml.Name
+				Href    string `xml:"href,attr"`
+				Rel     string `xml:"rel,attr"`
+				Type    string `xml:"type,attr"`
+				Value   string `xml:",chardata"`
+			} `xml:"link"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title       string `xml:"title"`
+				Link        string `xml:"link"`
+				Description string `xml:"description"`
+				GUID        struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+				Categories []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &document); err != nil {
+		t.Fatalf("RSS() is not well-formed XML: %v", err)
+	}
+
+	if document.XMLName.Local != "rss" || document.Version != "2.0" {
+		t.Fatalf("RSS() root = <%s version=%q>, want <rss version=\"2.0\">", document.XMLName.Local, document.Version)
+	}
+	if len(document.Channel) != 1 {
+		t.Fatalf("RSS() has %d channels, want exactly one", len(document.Channel))
+	}
+	channel := document.Channel[0]
+	var links, selfLinks []string
+	for _, link := range channel.Links {
+		switch link.XMLName.Space {
+		case "":
+			links = append(links, link.Value)
+		case "http://www.w3.org/2005/Atom":
+			if link.Rel == "self" && link.Type == MediaTypeRSS {
+				selfLinks = append(selfLinks, link.Href)
+			}
+		}
+	}
+	if len(channel.Title) != 1 || len(links) != 1 || len(channel.Description) != 1 {
+		t.Fatalf("RSS() channel needs exactly one title, link and description")
+	}
+	assertAbsoluteURL(t, "channel link", links[0])
+	if _, err := time.Parse(time.RFC1123Z, channel.LastBuildDate); err != nil {
+		t.Errorf("RSS() lastBuildDate %q is not an RFC 822 date: %v", channel.LastBuildDate, err)
+	}
+	// recommended by the RSS Advisory Board, so that readers know the URL of the feed
+	if len(selfLinks) != 1 || selfLinks[0] != exampleFeed.FeedURL {
+		t.Errorf("RSS() atom:link self = %v, want %q", selfLinks, exampleFeed.FeedURL)
+	}
+
+	if len(channel.Items) != len(exampleFeed.Items) {
+		t.Fatalf("RSS() has %d items, want %d", len(channel.Items), len(exampleFeed.Items))
+	}
+	var guids []string
+	for i, item := range channel.Items {
+		if item.Title == "" && item.Description == "" {
+			t.Errorf("RSS() item %d needs a title or a description", i)
+		}
+		assertAbsoluteURL(t, "item link", item.Link)
+		if item.GUID.IsPermaLink == "true" {
+			assertAbsoluteURL(t, "permalink guid", item.GUID.Value)
+		}
+		guids = append(guids, item.GUID.Value)
+		if pubDate, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil || !pubDate.Equal(exampleFeed.Items[i].Published) {
+			t.Errorf("RSS() item %d pubDate = %q, want RFC 822 date of %v", i, item.PubDate, exampleFeed.Items[i].Published)
+		}
+		if !strings.Contains(item.Description, exampleFeed.Items[i].Source) {
+			t.Errorf("RSS() item %d description doesn't contain the source", i)
+		}
+	}
+	assertUnique(t, "guid", guids)
+	if strings.Join(channel.Items[0].Categories, ",") != "ocean,mammal" {
+		t.Errorf("RSS() categories = %v, want tags", channel.Items[0].Categories)
+	}
+}
+
+// TestAtom validates the feed against the Atom specification (RFC 4287).
+func TestAtom(t *testing.T) {
+	body, err := Atom(exampleFeed)
+	if err != nil {
+		t.Fatalf("Atom() unexpected error = %v", err)
+	}
+
+	type link struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	}
+	var document struct {
+		XMLName xml.Name
+		ID      []string `xml:"http://www.w3.org/2005/Atom id"`
+		Title   []string `xml:"http://www.w3.org/2005/Atom title"`
+		Updated []string `xml:"http://www.w3.org/2005/Atom updated"`
+		Authors []struct {
+			Name string `xml:"http://www.w3.org/2005/Atom name"`
+		} `xml:"http://www.w3.org/2005/Atom author"`
+		Links   []link `xml:"http://www.w3.org/2005/Atom link"`
+		Entries []struct {
+			ID        []string `xml:"http://www.w3.org/2005/Atom id"`
+			Title     []string `xml:"http://www.w3.org/2005/Atom title"`
+			Updated   []string `xml:"http://www.w3.org/2005/Atom updated"`
+			Published string   `xml:"http://www.w3.org/2005/Atom published"`
+			Links     []link   `xml:"http://www.w3.org/2005/Atom link"`
+			Content   struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"http://www.w3.org/2005/Atom content"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"http://www.w3.org/2005/Atom category"`
+		} `xml:"http://www.w3.org/2005/Atom entry"`
+	}
+	if err := xml.Unmarshal(body, &document); err != nil {
+		t.Fatalf("Atom() is not well-formed XML: %v", err)
+	}
+
+	if document.XMLName.Space != "http://www.w3.org/2005/Atom" || document.XMLName.Local != "feed" {
+		t.Fatalf("Atom() root = %v, want feed in the Atom namespace", document.XMLName)
+	}
+	if len(document.ID) != 1 || len(document.Title) != 1 || len(document.Updated) != 1 {
+		t.Fatalf("Atom() feed needs exactly one id, title and updated")
+	}
+	assertAbsoluteURL(t, "feed id", document.ID[0])
+	if _, err := time.Parse(time.RFC3339, document.Updated[0]); err != nil {
+		t.Errorf("Atom() updated %q is not an RFC 3339 date: %v", document.Updated[0], err)
+	}
+	// the feed needs an author, because the entries don't have one
+	if len(document.Authors) == 0 || document.Authors[0].Name == "" {
+		t.Errorf("Atom() feed needs an author with a name")
+	}
+	var selfLinks int
+	for _, l := range document.Links {
+		if l.Rel == "self" {
+			selfLinks++
+			if l.Href != exampleFeed.FeedURL {
+				t.Errorf("Atom() self link = %q, want %q", l.Href, exampleFeed.FeedURL)
+			}
+		}
+	}
+	if selfLinks != 1 {
+		t.Errorf("Atom() has %d self links, want one", selfLinks)
+	}
+
+	if len(document.Entries) != len(exampleFeed.Items) {
+		t.Fatalf("Atom() has %d entries, want %d", len(document.Entries), len(exampleFeed.Items))
+	}
+	var ids []string
+	for i, entry := range document.Entries {
+		if len(entry.ID) != 1 || len(entry.Title) != 1 || len(entry.Updated) != 1 {
+			t.Errorf("Atom() entry %d needs exactly one id, title and updated", i)
+			continue
+		}
+		assertAbsoluteURL(t, "entry id", entry.ID[0])
+		ids = append(ids, entry.ID[0])
+		updated, err := time.Parse(time.RFC3339, entry.Updated[0])
+		if err != nil || !updated.Equal(exampleFeed.Items[i].Updated) {
+			t.Errorf("Atom() entry %d updated = %q, want RFC 3339 date of %v", i, entry.Updated[0], exampleFeed.Items[i].Updated)
+		}
+		if published, err := time.Parse(time.RFC3339, entry.Published); err != nil || published.After(updated) {
+			t.Errorf("Atom() entry %d published = %q, want RFC 3339 date not after updated", i, entry.Published)
+		}
+		// without a content element with src, an alternate link is required
+		var alternate bool
+		for _, l := range entry.Links {
+			alternate = alternate || l.Rel == "alternate"
+		}
+		if !alternate {
+			t.Errorf("Atom() entry %d needs an alternate link", i)
+		}
+		if entry.Content.Type != "text" || entry.Content.Value != exampleFeed.Items[i].Content {
+			t.Errorf("Atom() entry %d content = %+v, want text content", i, entry.Content)
+		}
+	}
+	assertUnique(t, "entry id", ids)
+}
+
+// TestJSONFeed validates the feed against the JSON Feed 1.1 specification (https://www.jsonfeed.org/version/1.1/).
+func TestJSONFeed(t *testing.T) {
+	body, err := JSONFeed(exampleFeed)
+	if err != nil {
+		t.Fatalf("JSONFeed() unexpected error = %v", err)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		t.Fatalf("JSONFeed() is not valid JSON: %v", err)
+	}
+
+	if document["version"] != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("JSONFeed() version = %v", document["version"])
+	}
+	if title, _ := document["title"].(string); title == "" {
+		t.Errorf("JSONFeed() needs a title")
+	}
+	for _, key := range []string{"home_page_url", "feed_url"} {
+		value, _ := document[key].(string)
+		assertAbsoluteURL(t, key, value)
+	}
+	if authors, ok := document["authors"].([]interface{}); !ok || len(authors) == 0 {
+		t.Errorf("JSONFeed() authors = %v, want a list of authors", document["authors"])
+	}
+
+	items, ok := document["items"].([]interface{})
+	if !ok || len(items) != len(exampleFeed.Items) {
+		t.Fatalf("JSONFeed() items = %v, want %d items", document["items"], len(exampleFeed.Items))
+	}
+	var ids []string
+	for i, rawItem := range items {
+		item := rawItem.(map[string]interface{})
+		id, ok := item["id"].(string)
+		if !ok || id == "" {
+			t.Errorf("JSONFeed() item %d needs a string id", i)
+		}
+		ids = append(ids, id)
+		if _, hasText := item["content_text"].(string); !hasText {
+			if _, hasHTML := item["content_html"].(string); !hasHTML {
+				t.Errorf("JSONFeed() item %d needs content_text or content_html", i)
+			}
+		}
+		for _, key := range []string{"date_published", "date_modified"} {
+			if value, _ := item[key].(string); value != "" {
+				if _, err := time.Parse(time.RFC3339, value); err != nil {
+					t.Errorf("JSONFeed() item %d %s %q is not an RFC 3339 date", i, key, value)
+				}
+			}
+		}
+		if externalURL, ok := item["external_url"].(string); ok {
+			assertAbsoluteURL(t, "external_url", externalURL)
+		}
+	}
+	assertUnique(t, "item id", ids)
+	if _, hasExternalURL := items[1].(map[string]interface{})["external_url"]; hasExternalURL {
+		t.Errorf("JSONFeed() sources that are no URLs must not be used as external_url")
+	}
+}
+
+func TestEmptyFeed(t *testing.T) {
+	emptyFeed := *exampleFeed
+	emptyFeed.Items = nil
+	emptyFeed.Updated = time.Time{}
+
+	for name, render := range map[string]func(*Feed) ([]byte, error){"rss": RSS, "atom": Atom, "json": JSONFeed} {
+		body, err := render(&emptyFeed)
+		if err != nil {
+			t.Errorf("%s unexpected error = %v", name, err)
+		}
+		if name == "json" && !strings.Contains(string(body), `"items":[]`) {
+			t.Errorf("%s items must be an empty list, got %s", name, body)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gqlgo "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	logger "github.com/cafo13/animal-facts/pkg/log"
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+const (
+	DefaultMaxDepth      = 8
+	DefaultMaxComplexity = 1000
+	// maxParallelism limits the concurrently running resolvers of a request, it is high enough that the resolvers of
+	// a full page of facts run at once and their loads end up in one batch
+	maxParallelism = maxFactsFirst
+)
+
+//go:embed schema.graphql
+var schemaString string
+
+type Options struct {
+	// MaxDepth is the maximum nesting of fields in a query.
+	MaxDepth int
+	// MaxComplexity is the maximum complexity of a query, every field counts one and the fields below a field with
+	// a first argument count first times.
+	MaxComplexity int
+	// Introspection enables the __schema and __type queries.
+	Introspection bool
+}
+
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	// CorrelationID is the ID of the request in the logs, it is part of the errors that are not shown to the client.
+	CorrelationID string `json:"-"`
+}
+
+// argumentError is an error of the arguments of a field, its message is shown to the client. The messages of other
+// errors of resolvers are only logged, as they can contain internals like database errors.
+type argumentError struct {
+	message string
+}
+
+func argumentErrorf(format string, args ...interface{}) error {
+	return &argumentError{fmt.Sprintf(format, args...)}
+}
+
+func (a *argumentError) Error() string {
+	return a.message
+}
+
+type Server struct {
+	schema          *gqlgo.Schema
+	parsedSchema    *ast.Schema
+	maxComplexity   int
+	factsRepository repository.FactsRepository
+}
+
+func NewServer(factsHandler *handler.FactsHandler, factsRepository repository.FactsRepository, options Options) (*Server, error) {
+	schemaOptions := []gqlgo.SchemaOpt{
+		gqlgo.UseStringDescriptions(),
+		gqlgo.MaxDepth(options.MaxDepth),
+		gqlgo.MaxParallelism(maxParallelism),
+	}
+	if !options.Introspection {
+		schemaOptions = append(schemaOptions, gqlgo.DisableIntrospection())
+	}
+
+	schema, err := gqlgo.ParseSchema(schemaString, &queryResolver{factsHandler}, schemaOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse graphql schema")
+	}
+
+	// the schema is parsed a second time to analyze the complexity of queries before executing them
+	parsedSchema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaString})
+	if gqlErr != nil {
+		return nil, errors.Wrap(gqlErr, "failed to load graphql schema for complexity analysis")
+	}
+
+	return &Server{
+		schema:          schema,
+		parsedSchema:    parsedSchema,
+		maxComplexity:   options.MaxComplexity,
+		factsRepository: factsRepository,
+	}, nil
+}
+
+// Execute runs the query, errors are part of the response as GraphQL requires. Queries whose complexity can't be
+// calculated are not executed, so that the limit of the complexity can't be bypassed.
+func (s *Server) Execute(ctx context.Context, request *Request) *gqlgo.Response {
+	complexity, err := s.complexity(request)
+	if err != nil {
+		return &gqlgo.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}}
+	}
+	if complexity > s.maxComplexity {
+		return &gqlgo.Response{Errors: []*gqlerrors.QueryError{
+			gqlerrors.Errorf("query has a complexity of %d, the maximum is %d", complexity, s.maxComplexity),
+		}}
+	}
+
+	ctx = context.WithValue(ctx, loadersContextKey{}, newLoaders(s.factsRepository))
+	response := s.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+	sanitizeErrors(ctx, response, request.CorrelationID)
+	return response
+}
+
+// sanitizeErrors replaces the messages of unexpected errors of resolvers with a generic message, like the problem
+// details of the REST endpoints, and logs them with the correlation ID.
+func sanitizeErrors(ctx context.Context, response *gqlgo.Response, correlationID string) {
+	for _, queryErr := range response.Errors {
+		var argumentErr *argumentError
+		if queryErr.ResolverError == nil || errors.As(queryErr.ResolverError, &argumentErr) {
+			continue
+		}
+
+		logger.FromContext(ctx).
+			WithError(queryErr.ResolverError).
+			WithField("correlation_id", correlationID).
+			Errorf("failed to resolve graphql field %v", queryErr.Path)
+		queryErr.Message = problem.UnexpectedDetail
+		if correlationID != "" {
+			queryErr.Extensions = map[string]interface{}{"correlationId": correlationID}
+		}
+	}
+}
+
+// complexity calculates the complexity of the operation, it fails if the query is not valid or the operation is not
+// unambiguous.
+func (s *Server) complexity(request *Request) (int, error) {
+	document, gqlErrs := gqlparser.LoadQuery(s.parsedSchema, request.Query)
+	if len(gqlErrs) > 0 {
+		return 0, errors.Errorf("query is not valid: %s", gqlErrs[0].Message)
+	}
+
+	operation := document.Operations.ForName(request.OperationName)
+	if operation == nil {
+		if request.OperationName != "" {
+			return 0, errors.Errorf("query has no operation named '%s'", request.OperationName)
+		}
+		if len(document.Operations) != 1 {
+			return 0, errors.New("query has more than one operation, operationName must select one of them")
+		}
+		operation = document.Operations[0]
+	}
+
+	return selectionSetComplexity(operation.SelectionSet, request.Variables), nil
+}
+
+func selectionSetComplexity(selectionSet ast.SelectionSet, variables map[string]interface{}) int {
+	complexity := 0
+	for _, selection := range selectionSet {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			childComplexity := selectionSetComplexity(selection.SelectionSet, variables)
+			if first, ok := intValue(selection.ArgumentMap(variables)["first"]); ok {
+				childComplexity *= max(first, 1)
+			}
+			complexity += 1 + childComplexity
+		case *ast.FragmentSpread:
+			if selection.Definition != nil {
+				complexity += selectionSetComplexity(selection.Definition.SelectionSet, variables)
+			}
+		case *ast.InlineFragment:
+			complexity += selectionSetComplexity(selection.SelectionSet, variables)
+		}
+	}
+
+	return complexity
+}
+
+// intValue converts literals (int64) and variables (float64 or json.Number from decoded JSON) to int.
+func intValue(value interface{}) (int, bool) {
+	switch value := value.(type) {
+	case int64:
+		return int(value), true
+	case int32:
+		return int(value), true
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	case json.Number:
+		i, err := value.Int64()
+		return int(i), err == nil
+	}
+
+	return 0, false
+}
+
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -0,0 +1,335 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+// countingFactsRepository counts the reads, so that tests can check that reads are batched.
+type countingFactsRepository struct {
+	repository.FactsRepository
+	readOneCalls     atomic.Int32
+	readManyCalls    atomic.Int32
+	readManyIDsCalls atomic.Int32
+	readRelatedCalls atomic.Int32
+	// readManyErr fails the reads of many facts if it is set
+	readManyErr error
+}
+
+// WithContext keeps counting the reads with the context of a request.
+func (c *countingFactsRepository) WithContext(ctx context.Context) repository.FactsRepository {
+	return c
+}
+
+func (c *countingFactsRepository) ReadOne(id primitive.ObjectID) (*repository.Fact, error) {
+	c.readOneCalls.Add(1)
+	return c.FactsRepository.ReadOne(id)
+}
+
+func (c *countingFactsRepository) ReadManyIDs(filterFunc func(fact *repository.Fact) bool) ([]primitive.ObjectID, error) {
+	c.readManyIDsCalls.Add(1)
+	return c.FactsRepository.ReadManyIDs(filterFunc)
+}
+
+func (c *countingFactsRepository) ReadRelated(query repository.RelatedFactsQuery) ([]*repository.Fact, error) {
+	c.readRelatedCalls.Add(1)
+	return c.FactsRepository.ReadRelated(query)
+}
+
+func (c *countingFactsRepository) ReadMany(ids []primitive.ObjectID) ([]*repository.Fact, error) {
+	c.readManyCalls.Add(1)
+	if c.readManyErr != nil {
+		return nil, c.readManyErr
+	}
+	return c.FactsRepository.ReadMany(ids)
+}
+
+func newTestServer(t *testing.T, options Options) (*Server, *countingFactsRepository, []*repository.Fact) {
+	t.Helper()
+
+	now := time.Now()
+	var facts []*repository.Fact
+	newFact := func(text string, animal string, tags ...string) *repository.Fact {
+		fact := &repository.Fact{
+			ID:        primitive.NewObjectID(),
+			Fact:      text,
+			Source:    "https://factanimal.com/",
+			Animal:    animal,
+			Tags:      tags,
+			Language:  "en",
+			Approved:  true,
+			CreatedAt: now.Add(time.Duration(len(facts)) * time.Minute),
+		}
+		facts = append(facts, fact)
+		return fact
+	}
+	newFact("The Blue Whale is the largest animal that has ever lived.", "whale", "ocean", "mammal")
+	newFact("Whales sing.", "whale", "ocean")
+	newFact("Octopuses have three hearts.", "octopus", "ocean")
+	newFact("Cats sleep a lot.", "cat", "mammal")
+	newFact("Rocks are no animals.", "")
+
+	factsByID := map[primitive.ObjectID]*repository.Fact{}
+	for _, fact := range facts {
+		factsByID[fact.ID] = fact
+	}
+	factsRepository := &countingFactsRepository{FactsRepository: repository.NewMockFactsRepository(factsByID, false)}
+
+	server, err := NewServer(handler.NewFactsHandler(factsRepository), factsRepository, options)
+	if err != nil {
+		t.Fatalf("NewServer() unexpected error = %v", err)
+	}
+
+	return server, factsRepository, facts
+}
+
+func execute(t *testing.T, server *Server, query string, variables map[string]interface{}) (map[string]interface{}, []string) {
+	t.Helper()
+
+	response := server.Execute(context.Background(), &Request{Query: query, Variables: variables})
+	var errorMessages []string
+	for _, err := range response.Errors {
+		errorMessages = append(errorMessages, err.Message)
+	}
+
+	var data map[string]interface{}
+	if len(response.Data) > 0 {
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			t.Fatalf("Execute() returned invalid data: %v", err)
+		}
+	}
+
+	return data, errorMessages
+}
+
+var defaultOptions = Options{MaxDepth: DefaultMaxDepth, MaxComplexity: DefaultMaxComplexity, Introspection: true}
+
+func TestServer_Execute_fact(t *testing.T) {
+	server, _, facts := newTestServer(t, defaultOptions)
+
+	data, errs := execute(t, server, `query($id: ID!) {
+		fact(id: $id) { id fact animal tags relatedFacts(first: 2) { fact } }
+		missing: fact(id: "6578bf140e487ecc049c7594") { id }
+	}`, map[string]interface{}{"id": facts[0].ID.Hex()})
+	if len(errs) > 0 {
+		t.Fatalf("Execute() unexpected errors = %v", errs)
+	}
+
+	fact := data["fact"].(map[string]interface{})
+	if fact["fact"] != facts[0].Fact || fact["animal"] != "whale" {
+		t.Errorf("fact = %v, want %v", fact, facts[0])
+	}
+	related := fact["relatedFacts"].([]interface{})
+	// "Whales sing." shares the animal and a tag, the octopus and the cat share one tag each
+	if len(related) != 2 || related[0].(map[string]interface{})["fact"] != facts[1].Fact {
+		t.Errorf("relatedFacts = %v, want the other whale fact first", related)
+	}
+	if data["missing"] != nil {
+		t.Errorf("missing = %v, want null", data["missing"])
+	}
+}
+
+func TestServer_Execute_facts(t *testing.T) {
+	server, factsRepository, facts := newTestServer(t, defaultOptions)
+
+	query := `query($after: String) {
+		facts(first: 2, after: $after, filter: {tag: " OCEAN "}) {
+			nodes { id tags relatedFacts { id } }
+			pageInfo { endCursor hasNextPage }
+		}
+		count(filter: {tag: "ocean"})
+	}`
+	data, errs := execute(t, server, query, nil)
+	if len(errs) > 0 {
+		t.Fatalf("Execute() unexpected errors = %v", errs)
+	}
+
+	connection := data["facts"].(map[string]interface{})
+	nodes := connection["nodes"].([]interface{})
+	pageInfo := connection["pageInfo"].(map[string]interface{})
+	if len(nodes) != 2 || nodes[0].(map[string]interface{})["id"] != facts[0].ID.Hex() || pageInfo["hasNextPage"] != true {
+		t.Errorf("facts = %v, want the first two ocean facts and a next page", connection)
+	}
+	if data["count"] != float64(3) {
+		t.Errorf("count = %v, want 3", data["count"])
+	}
+	// one read of the page, the related facts of both nodes are loaded with one read
+	if calls := factsRepository.readManyCalls.Load(); calls > 3 {
+		t.Errorf("ReadMany() was called %d times, want the reads to be batched", calls)
+	}
+	if calls := factsRepository.readOneCalls.Load(); calls > 0 {
+		t.Errorf("ReadOne() was called %d times, want all reads to be batched", calls)
+	}
+	// the related facts are queried by the animals and tags of the nodes instead of reading all facts
+	if calls := factsRepository.readRelatedCalls.Load(); calls != 1 {
+		t.Errorf("ReadRelated() was called %d times, want 1", calls)
+	}
+	if calls := factsRepository.readManyIDsCalls.Load(); calls > 1 {
+		t.Errorf("ReadManyIDs() was called %d times, want only the count to read the IDs of all facts", calls)
+	}
+
+	data, errs = execute(t, server, query, map[string]interface{}{"after": pageInfo["endCursor"]})
+	if len(errs) > 0 {
+		t.Fatalf("Execute() unexpected errors = %v", errs)
+	}
+	connection = data["facts"].(map[string]interface{})
+	if nodes := connection["nodes"].([]interface{}); len(nodes) != 1 || nodes[0].(map[string]interface{})["id"] != facts[2].ID.Hex() {
+		t.Errorf("facts = %v, want the last ocean fact", connection)
+	}
+
+	_, errs = execute(t, server, `{ facts(first: 101) { nodes { id } } }`, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0], "first must be between 1 and 100") {
+		t.Errorf("Execute() errors = %v, want first to be validated", errs)
+	}
+}
+
+func TestServer_Execute_errors(t *testing.T) {
+	server, factsRepository, facts := newTestServer(t, defaultOptions)
+	factsRepository.readManyErr = errors.New("connection to mongodb.internal:27017 refused")
+
+	response := server.Execute(context.Background(), &Request{
+		Query:         `query($id: ID!) { fact(id: $id) { id } invalid: fact(id: "invalid") { id } }`,
+		Variables:     map[string]interface{}{"id": facts[0].ID.Hex()},
+		CorrelationID: "request-1",
+	})
+	messages := map[string]*gqlerrors.QueryError{}
+	for _, queryErr := range response.Errors {
+		messages[queryErr.Message] = queryErr
+	}
+	if len(response.Errors) != 2 || messages["id is not a valid object id in hex string format"] == nil {
+		t.Fatalf("Execute() errors = %v, want the argument error unchanged", response.Errors)
+	}
+	unexpected := messages[problem.UnexpectedDetail]
+	if unexpected == nil || unexpected.Extensions["correlationId"] != "request-1" {
+		t.Errorf("Execute() errors = %v, want the repository error replaced with a generic message", response.Errors)
+	}
+}
+
+func TestServer_Execute_randomFact(t *testing.T) {
+	server, _, _ := newTestServer(t, defaultOptions)
+
+	data, errs := execute(t, server, `{ cat: randomFact(filter: {animal: "cat"}) { fact } dog: randomFact(filter: {animal: "dog"}) { fact } }`, nil)
+	if len(errs) > 0 {
+		t.Fatalf("Execute() unexpected errors = %v", errs)
+	}
+	if cat := data["cat"].(map[string]interface{}); cat["fact"] != "Cats sleep a lot." {
+		t.Errorf("randomFact = %v, want the cat fact", cat)
+	}
+	if data["dog"] != nil {
+		t.Errorf("randomFact = %v, want null without matching facts", data["dog"])
+	}
+}
+
+func TestServer_Execute_limits(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   Options
+		query     string
+		variables map[string]interface{}
+		wantError string
+	}{
+		{
+			name:      "too deep",
+			options:   Options{MaxDepth: 3, MaxComplexity: DefaultMaxComplexity},
+			query:     `{ facts { nodes { relatedFacts { id } } } }`,
+			wantError: "exceeds max depth",
+		},
+		{
+			name:      "too complex",
+			options:   Options{MaxDepth: DefaultMaxDepth, MaxComplexity: 100},
+			query:     `{ facts(first: 100) { nodes { id fact } } }`,
+			wantError: "complexity of 301",
+		},
+		{
+			name:      "too complex with variables and fragments",
+			options:   Options{MaxDepth: DefaultMaxDepth, MaxComplexity: 100},
+			query:     `query($first: Int) { facts(first: $first) { nodes { ...related } } } fragment related on Fact { relatedFacts(first: 20) { id } }`,
+			variables: map[string]interface{}{"first": float64(10)},
+			wantError: "complexity of 221",
+		},
+		{
+			name:      "introspection disabled",
+			options:   Options{MaxDepth: DefaultMaxDepth, MaxComplexity: DefaultMaxComplexity, Introspection: false},
+			query:     `{ __schema { types { name } } }`,
+			wantError: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _, _ := newTestServer(t, tt.options)
+
+			data, errs := execute(t, server, tt.query, tt.variables)
+			if tt.wantError == "" {
+				if schema, _ := data["__schema"].(map[string]interface{}); schema != nil && schema["types"] != nil {
+					t.Errorf("Execute() returned the schema although introspection is disabled")
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(strings.Join(errs, "; "), tt.wantError) {
+				t.Errorf("Execute() errors = %v, want error containing %q", errs, tt.wantError)
+			}
+		})
+	}
+
+	t.Run("not analyzable", func(t *testing.T) {
+		server, factsRepository, _ := newTestServer(t, defaultOptions)
+		for query, wantError := range map[string]string{
+			`{ facts(first: 100) { nodes { unknown } } }`:                      "query is not valid",
+			`query a { count } query b { facts(first: 100) { nodes { id } } }`: "more than one operation",
+		} {
+			if _, errs := execute(t, server, query, nil); len(errs) != 1 || !strings.Contains(errs[0], wantError) {
+				t.Errorf("Execute() errors = %v, want error containing %q", errs, wantError)
+			}
+		}
+		response := server.Execute(context.Background(), &Request{Query: `query a { count }`, OperationName: "b"})
+		if len(response.Errors) != 1 || !strings.Contains(response.Errors[0].Message, "no operation named 'b'") {
+			t.Errorf("Execute() errors = %v, want the unknown operation to be rejected", response.Errors)
+		}
+		if calls := factsRepository.readManyCalls.Load(); calls > 0 {
+			t.Errorf("ReadMany() was called %d times, want queries that can't be analyzed not to be executed", calls)
+		}
+	})
+
+	t.Run("introspection enabled", func(t *testing.T) {
+		server, _, _ := newTestServer(t, defaultOptions)
+		data, errs := execute(t, server, `{ __type(name: "Fact") { name } }`, nil)
+		if len(errs) > 0 || data["__type"].(map[string]interface{})["name"] != "Fact" {
+			t.Errorf("Execute() data = %v, errors = %v, want the Fact type", data, errs)
+		}
+	})
+}
+
+func TestLoader_fetchesWithContextOfLoad(t *testing.T) {
+	type contextKey struct{}
+	var fetchedWith interface{}
+	l := newLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+		fetchedWith = ctx.Value(contextKey{})
+		return map[int]int{1: 1}, nil
+	})
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "request-1")
+	if _, found, err := l.Load(ctx, 1); err != nil || !found {
+		t.Fatalf("Load() = %v, %v, want the value", found, err)
+	}
+	if fetchedWith != "request-1" {
+		t.Errorf("fetch got context value %v, want the context of the load", fetchedWith)
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := l.Load(canceledCtx, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load() error = %v, want the cancellation of the context", err)
+	}
+}
@@ -0,0 +1,147 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLoaderWait     = 2 * time.Millisecond
+	defaultLoaderMaxBatch = 100
+)
+
+// loader batches all loads that happen within wait into one fetch, so that resolvers that run concurrently for the
+// items of a list don't read the repository one by one. Results are cached, a loader lives for one request. A batch
+// is fetched with the context of the first load of the batch.
+type loader[K comparable, V any] struct {
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mutex sync.Mutex
+	cache map[K]*loadResult[V]
+	batch *loadBatch[K, V]
+}
+
+type loadResult[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+type loadBatch[K comparable, V any] struct {
+	ctx     context.Context
+	keys    []K
+	results []*loadResult[V]
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:    fetch,
+		wait:     defaultLoaderWait,
+		maxBatch: defaultLoaderMaxBatch,
+		cache:    map[K]*loadResult[V]{},
+	}
+}
+
+// Load returns the value of the key and whether it was found.
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.mutex.Lock()
+	result := l.enqueue(ctx, key)
+	l.mutex.Unlock()
+
+	return wait(ctx, result)
+}
+
+// LoadMany returns the values of the keys that were found in the order of the keys, all keys are fetched in one batch.
+func (l *loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	l.mutex.Lock()
+	results := make([]*loadResult[V], 0, len(keys))
+	for _, key := range keys {
+		results = append(results, l.enqueue(ctx, key))
+	}
+	l.mutex.Unlock()
+
+	values := make([]V, 0, len(keys))
+	for _, result := range results {
+		value, found, err := wait(ctx, result)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// prime caches the value of the key unless it was loaded before, e.g. if it was read together with other values.
+func (l *loader[K, V]) prime(key K, value V) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, cached := l.cache[key]; cached {
+		return
+	}
+	result := &loadResult[V]{done: make(chan struct{}), value: value, found: true}
+	close(result.done)
+	l.cache[key] = result
+}
+
+// enqueue adds the key to the current batch unless it was loaded before, the mutex has to be locked.
+func (l *loader[K, V]) enqueue(ctx context.Context, key K) *loadResult[V] {
+	if result, cached := l.cache[key]; cached {
+		return result
+	}
+
+	result := &loadResult[V]{done: make(chan struct{})}
+	l.cache[key] = result
+
+	if l.batch == nil {
+		batch := &loadBatch[K, V]{ctx: ctx}
+		l.batch = batch
+		time.AfterFunc(l.wait, func() {
+			l.mutex.Lock()
+			if l.batch != batch {
+				// the batch was already dispatched because it was full
+				l.mutex.Unlock()
+				return
+			}
+			l.batch = nil
+			l.mutex.Unlock()
+			l.run(batch)
+		})
+	}
+
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results = append(l.batch.results, result)
+	if len(l.batch.keys) >= l.maxBatch {
+		go l.run(l.batch)
+		l.batch = nil
+	}
+
+	return result
+}
+
+func (l *loader[K, V]) run(batch *loadBatch[K, V]) {
+	values, err := l.fetch(batch.ctx, batch.keys)
+	for i, key := range batch.keys {
+		result := batch.results[i]
+		result.value, result.found = values[key]
+		result.err = err
+		close(result.done)
+	}
+}
+
+func wait[V any](ctx context.Context, result *loadResult[V]) (V, bool, error) {
+	select {
+	case <-result.done:
+		return result.value, result.found, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
@@ -0,0 +1,325 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	gqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+const (
+	maxFactsFirst   = 100
+	maxRelatedFirst = 20
+)
+
+type loadersContextKey struct{}
+
+// loaders are created for every request, so that cached facts are never older than the request.
+type loaders struct {
+	facts        *loader[primitive.ObjectID, *repository.Fact]
+	relatedFacts *loader[primitive.ObjectID, []primitive.ObjectID]
+}
+
+func newLoaders(factsRepository repository.FactsRepository) *loaders {
+	l := &loaders{}
+	l.facts = newLoader(func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]*repository.Fact, error) {
+		facts, err := factsRepository.WithContext(ctx).ReadMany(ids)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get facts")
+		}
+
+		factsByID := make(map[primitive.ObjectID]*repository.Fact, len(facts))
+		for _, fact := range facts {
+			factsByID[fact.ID] = fact
+		}
+		return factsByID, nil
+	})
+	l.relatedFacts = newLoader(func(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID][]primitive.ObjectID, error) {
+		return readRelatedFactIDs(ctx, factsRepository, l.facts, ids)
+	})
+
+	return l
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersContextKey{}).(*loaders)
+}
+
+// readRelatedFactIDs finds the related facts of all facts of a batch with one read of the facts about their animals or
+// with their tags, the facts are ordered by their similarity.
+func readRelatedFactIDs(ctx context.Context, factsRepository repository.FactsRepository, factsLoader *loader[primitive.ObjectID, *repository.Fact], ids []primitive.ObjectID) (map[primitive.ObjectID][]primitive.ObjectID, error) {
+	// the facts of the batch were loaded by the parent resolvers before, so this doesn't read the repository again
+	facts, err := factsLoader.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var query repository.RelatedFactsQuery
+	for _, fact := range facts {
+		if fact.Animal != "" && !slices.Contains(query.Animals, fact.Animal) {
+			query.Animals = append(query.Animals, fact.Animal)
+		}
+		for _, tag := range fact.Tags {
+			if !slices.Contains(query.Tags, tag) {
+				query.Tags = append(query.Tags, tag)
+			}
+		}
+	}
+
+	candidates, err := factsRepository.WithContext(ctx).ReadRelated(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get related facts")
+	}
+	// the related facts are resolved right after, so they are not read again
+	for _, candidate := range candidates {
+		factsLoader.prime(candidate.ID, candidate)
+	}
+
+	relatedFactIDs := make(map[primitive.ObjectID][]primitive.ObjectID, len(facts))
+	for _, fact := range facts {
+		var related []*repository.Fact
+		for _, candidate := range candidates {
+			if similarity(fact, candidate) > 0 {
+				related = append(related, candidate)
+			}
+		}
+		slices.SortFunc(related, func(a, b *repository.Fact) int {
+			if c := similarity(fact, b) - similarity(fact, a); c != 0 {
+				return c
+			}
+			return bytes.Compare(a.ID[:], b.ID[:])
+		})
+
+		relatedIDs := []primitive.ObjectID{}
+		for _, relatedFact := range related[:min(len(related), maxRelatedFirst)] {
+			relatedIDs = append(relatedIDs, relatedFact.ID)
+		}
+		relatedFactIDs[fact.ID] = relatedIDs
+	}
+
+	return relatedFactIDs, nil
+}
+
+// similarity counts the same animal twice and every shared tag once, a fact is not similar to itself.
+func similarity(fact *repository.Fact, other *repository.Fact) int {
+	if fact.ID == other.ID {
+		return 0
+	}
+
+	score := 0
+	if fact.Animal != "" && fact.Animal == other.Animal {
+		score += 2
+	}
+	for _, tag := range fact.Tags {
+		if slices.Contains(other.Tags, tag) {
+			score++
+		}
+	}
+
+	return score
+}
+
+type queryResolver struct {
+	factsHandler *handler.FactsHandler
+}
+
+type factFilterInput struct {
+	Animal   *string
+	Tag      *string
+	Language *string
+}
+
+func (f *factFilterInput) toHandler() handler.FactFilter {
+	var filter handler.FactFilter
+	if f == nil {
+		return filter
+	}
+	if f.Animal != nil {
+		filter.Animal = normalize(*f.Animal)
+	}
+	if f.Tag != nil {
+		filter.Tag = normalize(*f.Tag)
+	}
+	if f.Language != nil {
+		filter.Language = normalize(*f.Language)
+	}
+
+	return filter
+}
+
+func (q *queryResolver) Fact(ctx context.Context, args struct{ ID gqlgo.ID }) (*factResolver, error) {
+	id, err := primitive.ObjectIDFromHex(string(args.ID))
+	if err != nil {
+		return nil, argumentErrorf("id is not a valid object id in hex string format")
+	}
+
+	return loadFact(ctx, id)
+}
+
+func (q *queryResolver) RandomFact(ctx context.Context, args struct{ Filter *factFilterInput }) (*factResolver, error) {
+	fact, err := q.factsHandler.WithContext(ctx).GetRandomApprovedMatching(args.Filter.toHandler())
+	if errors.Is(err, handler.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return loadFact(ctx, toObjectID(fact.ID))
+}
+
+func (q *queryResolver) Facts(ctx context.Context, args struct {
+	First  int32
+	After  *string
+	Filter *factFilterInput
+}) (*factConnectionResolver, error) {
+	if args.First < 1 || args.First > maxFactsFirst {
+		return nil, argumentErrorf("first must be between 1 and %d", maxFactsFirst)
+	}
+
+	filter := args.Filter.toHandler()
+	query := handler.ListQuery{
+		Animal:   filter.Animal,
+		Tag:      filter.Tag,
+		Language: filter.Language,
+		Limit:    int(args.First),
+	}
+	if args.After != nil {
+		query.Cursor = *args.After
+	}
+
+	page, err := q.factsHandler.WithContext(ctx).ListApproved(query)
+	if errors.Is(err, handler.ErrInvalidCursor) {
+		return nil, argumentErrorf("after is not a valid cursor")
+	} else if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(page.Facts))
+	for _, fact := range page.Facts {
+		ids = append(ids, toObjectID(fact.ID))
+	}
+	nodes, err := loadFacts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &factConnectionResolver{nodes: nodes, nextCursor: page.NextCursor}, nil
+}
+
+func (q *queryResolver) Count(ctx context.Context, args struct{ Filter *factFilterInput }) (int32, error) {
+	count, err := q.factsHandler.WithContext(ctx).GetFactsCountMatching(args.Filter.toHandler())
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(count), nil
+}
+
+type factResolver struct {
+	fact *repository.Fact
+}
+
+func loadFact(ctx context.Context, id primitive.ObjectID) (*factResolver, error) {
+	fact, found, err := loadersFrom(ctx).facts.Load(ctx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &factResolver{fact}, nil
+}
+
+func loadFacts(ctx context.Context, ids []primitive.ObjectID) ([]*factResolver, error) {
+	facts, err := loadersFrom(ctx).facts.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*factResolver, 0, len(facts))
+	for _, fact := range facts {
+		resolvers = append(resolvers, &factResolver{fact})
+	}
+	return resolvers, nil
+}
+
+func (f *factResolver) ID() gqlgo.ID {
+	return gqlgo.ID(f.fact.ID.Hex())
+}
+
+func (f *factResolver) Fact() string {
+	return f.fact.Fact
+}
+
+func (f *factResolver) Source() string {
+	return f.fact.Source
+}
+
+func (f *factResolver) Animal() *string {
+	if f.fact.Animal == "" {
+		return nil
+	}
+	return &f.fact.Animal
+}
+
+func (f *factResolver) Tags() []string {
+	if f.fact.Tags == nil {
+		return []string{}
+	}
+	return f.fact.Tags
+}
+
+func (f *factResolver) Language() string {
+	return f.fact.Language
+}
+
+func (f *factResolver) RelatedFacts(ctx context.Context, args struct{ First int32 }) ([]*factResolver, error) {
+	if args.First < 0 || args.First > maxRelatedFirst {
+		return nil, argumentErrorf("first must be between 0 and %d", maxRelatedFirst)
+	}
+
+	relatedIDs, _, err := loadersFrom(ctx).relatedFacts.Load(ctx, f.fact.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadFacts(ctx, relatedIDs[:min(len(relatedIDs), int(args.First))])
+}
+
+type factConnectionResolver struct {
+	nodes      []*factResolver
+	nextCursor string
+}
+
+func (c *factConnectionResolver) Nodes() []*factResolver {
+	return c.nodes
+}
+
+func (c *factConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{c.nextCursor}
+}
+
+type pageInfoResolver struct {
+	nextCursor string
+}
+
+func (p *pageInfoResolver) EndCursor() *string {
+	if p.nextCursor == "" {
+		return nil
+	}
+	return &p.nextCursor
+}
+
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.nextCursor != ""
+}
+
+// toObjectID converts IDs of handler facts back, which are always valid as they come from object IDs.
+func toObjectID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(hex)
+	return id
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	defaultApprovedPollInterval = 5 * time.Second
+	approvedPollLimit           = 100
+)
+
+// ApprovedFact is the data of the events of approved facts, which are streamed by the api v1.
+type ApprovedFact struct {
+	ID     string `json:"id"`
+	Fact   string `json:"fact"`
+	Source string `json:"source"`
+}
+
+// ApprovedFactsWatcher publishes newly approved facts to the event bus of the public API. Facts are approved by the
+// internal API, which runs in another process, so the watcher polls the repository for facts that were approved
+// since the last poll.
+type ApprovedFactsWatcher struct {
+	factsRepository repository.FactsRepository
+	eventBus        *events.Bus
+	interval        time.Duration
+
+	// lastApprovedAt is the approval time of the latest published fact, lastIDs are the published facts with that time
+	lastApprovedAt time.Time
+	lastIDs        map[primitive.ObjectID]bool
+}
+
+func NewApprovedFactsWatcher(factsRepository repository.FactsRepository, eventBus *events.Bus) *ApprovedFactsWatcher {
+	return &ApprovedFactsWatcher{
+		factsRepository: factsRepository,
+		eventBus:        eventBus,
+		interval:        defaultApprovedPollInterval,
+	}
+}
+
+// Run polls until the context is done, only facts that are approved after the start are published.
+func (a *ApprovedFactsWatcher) Run(ctx context.Context) error {
+	a.lastApprovedAt = time.Now()
+	a.lastIDs = map[primitive.ObjectID]bool{}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.poll(); err != nil {
+				log.Logger().WithError(err).Error("failed to poll newly approved facts")
+			}
+		}
+	}
+}
+
+func (a *ApprovedFactsWatcher) poll() error {
+	recentlyApproved, err := a.factsRepository.ReadRecentlyApproved(repository.RecentlyApprovedQuery{Limit: approvedPollLimit})
+	if err != nil {
+		return errors.Wrap(err, "could not get recently approved facts")
+	}
+
+	// the facts are ordered with the latest approval first, they are published in the order of their approval
+	for i := len(recentlyApproved) - 1; i >= 0; i-- {
+		fact := recentlyApproved[i]
+		if fact.ApprovedAt.Before(a.lastApprovedAt) || (fact.ApprovedAt.Equal(a.lastApprovedAt) && a.lastIDs[fact.ID]) {
+			continue
+		}
+
+		if fact.ApprovedAt.After(a.lastApprovedAt) {
+			a.lastApprovedAt = fact.ApprovedAt
+			a.lastIDs = map[primitive.ObjectID]bool{}
+		}
+		a.lastIDs[fact.ID] = true
+		a.eventBus.Publish(events.TypeFactApproved, fact.ID.Hex(), &ApprovedFact{ID: fact.ID.Hex(), Fact: fact.Fact, Source: fact.Source})
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/card"
+)
+
+type CardFormat string
+
+const (
+	CardFormatPNG CardFormat = "png"
+	CardFormatSVG CardFormat = "svg"
+)
+
+// Card is a fact card that is only rendered on demand, so that requests with a matching ETag don't need to render it.
+type Card struct {
+	// ETag changes whenever the fact, the requested card or the layout changes.
+	ETag         string
+	LastModified time.Time
+	fact         *repository.Fact
+	format       CardFormat
+	options      card.Options
+}
+
+func (c *Card) Render() ([]byte, error) {
+	switch c.format {
+	case CardFormatPNG:
+		return card.RenderPNG(c.fact.Fact, c.fact.Source, c.options)
+	case CardFormatSVG:
+		return card.RenderSVG(c.fact.Fact, c.fact.Source, c.options)
+	}
+
+	return nil, errors.Errorf("unknown card format %s", c.format)
+}
+
+type CardsHandler struct {
+	factsRepository repository.FactsRepository
+}
+
+func NewCardsHandler(factsRepository repository.FactsRepository) *CardsHandler {
+	return &CardsHandler{factsRepository}
+}
+
+func (h *CardsHandler) GetCard(id primitive.ObjectID, format CardFormat, options card.Options) (*Card, error) {
+	fact, err := h.factsRepository.ReadOne(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "could not get fact by ID %v", id)
+	}
+
+	lastModified := fact.UpdatedAt
+	if lastModified.IsZero() {
+		lastModified = fact.CreatedAt
+	}
+
+	return &Card{
+		ETag:         cardETag(fact, format, options),
+		LastModified: lastModified,
+		fact:         fact,
+		format:       format,
+		options:      options,
+	}, nil
+}
+
+func cardETag(fact *repository.Fact, format CardFormat, options card.Options) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf(
+		"%s|%d|%s|%s|%dx%d|%d",
+		fact.ID.Hex(), fact.Revision, format, options.Theme.Name, options.Width, options.Height, card.LayoutVersion,
+	)))
+
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
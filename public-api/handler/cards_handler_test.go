@@ -0,0 +1,63 @@
+package handler_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/card"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+func TestCardsHandler_GetCard(t *testing.T) {
+	options := card.Options{Width: card.DefaultWidth, Height: card.DefaultHeight, Theme: card.ThemeLight}
+	fact := exampleFactApproved
+	factsRepository := repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{exampleID: &fact}, false)
+	cardsHandler := handler.NewCardsHandler(factsRepository)
+
+	getETag := func(format handler.CardFormat, options card.Options) string {
+		c, err := cardsHandler.GetCard(exampleID, format, options)
+		if err != nil {
+			t.Fatalf("GetCard() unexpected error = %v", err)
+		}
+		return c.ETag
+	}
+
+	etag := getETag(handler.CardFormatPNG, options)
+	if got := getETag(handler.CardFormatPNG, options); got != etag {
+		t.Errorf("GetCard() ETag = %v, want the same ETag for the same card %v", got, etag)
+	}
+	if got := getETag(handler.CardFormatSVG, options); got == etag {
+		t.Errorf("GetCard() ETag doesn't change with the format")
+	}
+	darkOptions := options
+	darkOptions.Theme = card.ThemeDark
+	if got := getETag(handler.CardFormatPNG, darkOptions); got == etag {
+		t.Errorf("GetCard() ETag doesn't change with the theme")
+	}
+
+	err := factsRepository.Update(exampleID, func(fact *repository.Fact) (*repository.Fact, error) {
+		fact.Fact = "The Blue Whale is the largest animal."
+		return fact, nil
+	})
+	if err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if got := getETag(handler.CardFormatPNG, options); got == etag {
+		t.Errorf("GetCard() ETag doesn't change with the revision of the fact")
+	}
+
+	c, err := cardsHandler.GetCard(exampleID, handler.CardFormatSVG, options)
+	if err != nil {
+		t.Fatalf("GetCard() unexpected error = %v", err)
+	}
+	if image, err := c.Render(); err != nil || len(image) == 0 {
+		t.Errorf("Render() = %d bytes, error = %v", len(image), err)
+	}
+
+	if _, err := cardsHandler.GetCard(primitive.NewObjectID(), handler.CardFormatPNG, options); !errors.Is(err, handler.ErrNotFound) {
+		t.Errorf("GetCard() error = %v, want %v", err, handler.ErrNotFound)
+	}
+}
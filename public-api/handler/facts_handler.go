@@ -1,52 +1,153 @@
 package handler
 
 import (
-	"math/rand"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/rand/v2"
+	"slices"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/shuffle"
+	"github.com/cafo13/animal-facts/pkg/tracing"
+	"github.com/cafo13/animal-facts/pkg/validation"
 )
 
 var (
-	ErrNotFound = errors.New("fact not found")
+	ErrNotFound      = errors.New("fact not found")
+	ErrInvalidCursor = errors.New("invalid cursor")
 )
 
+// Fact is an approved fact as the public api sees it, the versions of the api map it to their representations.
 type Fact struct {
-	ID     string `json:"id"`
-	Fact   string `json:"fact"`
-	Source string `json:"source"`
+	ID        string     `json:"id"`
+	Fact      string     `json:"fact"`
+	Source    string     `json:"source"`
+	Animal    string     `json:"animal"`
+	Tags      []string   `json:"tags"`
+	Language  string     `json:"language"`
+	Citations []Citation `json:"citations"`
+	CreatedAt time.Time  `json:"createdAt"`
+	// UpdatedAt is the creation time for facts that were never updated.
+	UpdatedAt  time.Time `json:"updatedAt"`
+	ApprovedAt time.Time `json:"approvedAt"`
+}
+
+const (
+	CitationTypeURL = "url"
+	CitationTypeDOI = "doi"
+)
+
+// Citation is a reference of the source of a fact, DOIs are resolved to their URL at doi.org.
+type Citation struct {
+	Type string `json:"type"`
+	// DOI is only set for citations of type doi
+	DOI string `json:"doi,omitempty"`
+	URL string `json:"url"`
 }
 
 type FactsHandler struct {
+	ctx             context.Context
 	factsRepository repository.FactsRepository
 }
 
 func NewFactsHandler(factsRepository repository.FactsRepository) *FactsHandler {
-	return &FactsHandler{factsRepository}
+	return &FactsHandler{context.Background(), factsRepository}
 }
 
-func (f *FactsHandler) mapFactToHandler(fact *repository.Fact) *Fact {
+// WithContext returns the handler running its operations with the context, e.g. to trace them as part of a request.
+func (f *FactsHandler) WithContext(ctx context.Context) *FactsHandler {
+	return &FactsHandler{ctx, f.factsRepository}
+}
+
+// trace starts the span of an operation, the returned handler runs the repository calls of the operation in the span.
+func (f *FactsHandler) trace(operation string) (*FactsHandler, trace.Span) {
+	ctx, span := tracing.Start(f.ctx, "FactsHandler."+operation)
+	return &FactsHandler{ctx, f.factsRepository.WithContext(ctx)}, span
+}
+
+func mapFactToHandler(fact *repository.Fact) *Fact {
+	updatedAt := fact.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = fact.CreatedAt
+	}
+
 	return &Fact{
-		ID:     fact.ID.Hex(),
-		Fact:   fact.Fact,
-		Source: fact.Source,
+		ID:         fact.ID.Hex(),
+		Fact:       fact.Fact,
+		Source:     fact.Source,
+		Animal:     fact.Animal,
+		Tags:       append([]string{}, fact.Tags...),
+		Language:   fact.Language,
+		Citations:  citationsOf(fact.Source),
+		CreatedAt:  fact.CreatedAt,
+		UpdatedAt:  updatedAt,
+		ApprovedAt: fact.ApprovedAt,
 	}
 }
 
+// citationsOf returns the citations of the source of a fact, which is a URL or a DOI.
+func citationsOf(source string) []Citation {
+	if source == "" {
+		return []Citation{}
+	}
+	if doi, isDOI := validation.ParseDOI(source); isDOI {
+		return []Citation{{Type: CitationTypeDOI, DOI: doi, URL: "https://doi.org/" + doi}}
+	}
+
+	return []Citation{{Type: CitationTypeURL, URL: source}}
+}
+
+// FactVersion is the version of a fact for conditional requests. The ETag depends on the fields of the representation,
+// so the versions of the api compute it from their representation.
+type FactVersion struct {
+	LastModified time.Time
+}
+
 func (f *FactsHandler) Get(id primitive.ObjectID) (*Fact, error) {
+	fact, _, err := f.GetWithVersion(id)
+	return fact, err
+}
+
+// GetWithVersion returns the fact with its version, the fact was last modified when it was updated or else created.
+func (f *FactsHandler) GetWithVersion(id primitive.ObjectID) (*Fact, *FactVersion, error) {
+	f, span := f.trace("GetWithVersion")
+	defer span.End()
+
 	repositoryFact, err := f.factsRepository.ReadOne(id)
 	if errors.Is(err, repository.ErrNotFound) {
-		return nil, ErrNotFound
+		return nil, nil, ErrNotFound
 	} else if err != nil {
-		return nil, errors.Wrapf(err, "could not get fact by ID %v", id)
+		return nil, nil, errors.Wrapf(err, "could not get fact by ID %v", id)
 	}
 
-	return f.mapFactToHandler(repositoryFact), nil
+	fact := mapFactToHandler(repositoryFact)
+	return fact, &FactVersion{LastModified: fact.UpdatedAt}, nil
 }
 
 func (f *FactsHandler) GetRandomApproved() (*Fact, error) {
+	randomFacts, err := f.GetRandomApprovedMany(1, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return randomFacts[0], nil
+}
+
+// GetRandomApprovedMany returns count distinct random approved facts, or all approved facts in random order if there
+// are less. With a seed, the same facts are returned in the same order as long as the approved facts don't change.
+func (f *FactsHandler) GetRandomApprovedMany(count int, seed *string) ([]*Fact, error) {
+	f, span := f.trace("GetRandomApprovedMany")
+	defer span.End()
+
 	idsOfApprovedFacts, err := f.factsRepository.ReadManyIDs(func(fact *repository.Fact) bool {
 		return true
 	})
@@ -58,17 +159,133 @@ func (f *FactsHandler) GetRandomApproved() (*Fact, error) {
 		return nil, errors.New("no approved facts found")
 	}
 
-	randomFactId := idsOfApprovedFacts[rand.Intn(len(idsOfApprovedFacts))]
+	var random *rand.Rand
+	if seed != nil {
+		// the order of the IDs from the repository is not guaranteed, so it needs to be fixed for reproducible results
+		slices.SortFunc(idsOfApprovedFacts, func(a, b primitive.ObjectID) int {
+			return bytes.Compare(a[:], b[:])
+		})
+		seedHash := sha256.Sum256([]byte(*seed))
+		random = rand.New(rand.NewPCG(binary.BigEndian.Uint64(seedHash[:8]), binary.BigEndian.Uint64(seedHash[8:16])))
+	} else {
+		random = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	// partial Fisher-Yates shuffle, the first count IDs are the selected ones
+	count = min(count, len(idsOfApprovedFacts))
+	for i := 0; i < count; i++ {
+		j := i + random.IntN(len(idsOfApprovedFacts)-i)
+		idsOfApprovedFacts[i], idsOfApprovedFacts[j] = idsOfApprovedFacts[j], idsOfApprovedFacts[i]
+	}
 
-	randomFact, err := f.factsRepository.ReadOne(randomFactId)
+	randomFacts, err := f.factsRepository.ReadMany(idsOfApprovedFacts[:count])
 	if err != nil {
-		return nil, errors.Wrapf(err, "could not get random fact by ID %v", randomFactId)
+		return nil, errors.Wrap(err, "could not get random facts")
+	}
+
+	if len(randomFacts) == 0 {
+		return nil, errors.New("no approved facts found")
 	}
 
-	return f.mapFactToHandler(randomFact), nil
+	result := make([]*Fact, 0, len(randomFacts))
+	for _, randomFact := range randomFacts {
+		result = append(result, mapFactToHandler(randomFact))
+	}
+
+	return result, nil
+}
+
+// GetNextShuffled returns the next approved fact of the permutation of the shuffle token and the token pointing to it,
+// so that a client gets every approved fact once before any fact repeats.
+func (f *FactsHandler) GetNextShuffled(token shuffle.Token) (*Fact, shuffle.Token, error) {
+	f, span := f.trace("GetNextShuffled")
+	defer span.End()
+
+	idsOfApprovedFacts, err := f.factsRepository.ReadManyIDs(func(fact *repository.Fact) bool {
+		return true
+	})
+	if err != nil {
+		return nil, token, errors.Wrap(err, "could not get IDs of approved facts")
+	}
+
+	for len(idsOfApprovedFacts) > 0 {
+		var nextFactId primitive.ObjectID
+		nextFactId, token = token.Next(idsOfApprovedFacts)
+
+		nextFact, err := f.factsRepository.ReadOne(nextFactId)
+		if errors.Is(err, repository.ErrNotFound) {
+			// the fact got unapproved or deleted after reading the IDs, so continue with the next one
+			idsOfApprovedFacts = slices.DeleteFunc(idsOfApprovedFacts, func(id primitive.ObjectID) bool {
+				return id == nextFactId
+			})
+			continue
+		} else if err != nil {
+			return nil, token, errors.Wrapf(err, "could not get shuffled fact by ID %v", nextFactId)
+		}
+
+		return mapFactToHandler(nextFact), token, nil
+	}
+
+	return nil, token, errors.New("no approved facts found")
+}
+
+// FactFilter selects approved facts, filters with empty values are ignored.
+type FactFilter struct {
+	Animal   string
+	Tag      string
+	Language string
+}
+
+func (f FactFilter) matches(fact *repository.Fact) bool {
+	return fact.Approved &&
+		(f.Animal == "" || fact.Animal == f.Animal) &&
+		(f.Tag == "" || slices.Contains(fact.Tags, f.Tag)) &&
+		(f.Language == "" || fact.LanguageOrDefault() == f.Language)
+}
+
+// GetRandomApprovedMatching returns a random approved fact matching the filter, ErrNotFound if no fact matches.
+func (f *FactsHandler) GetRandomApprovedMatching(filter FactFilter) (*Fact, error) {
+	f, span := f.trace("GetRandomApprovedMatching")
+	defer span.End()
+
+	idsOfMatchingFacts, err := f.factsRepository.ReadManyIDs(filter.matches)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get IDs of matching facts")
+	}
+
+	for len(idsOfMatchingFacts) > 0 {
+		i := rand.IntN(len(idsOfMatchingFacts))
+		randomFact, err := f.factsRepository.ReadOne(idsOfMatchingFacts[i])
+		if errors.Is(err, repository.ErrNotFound) {
+			// the fact got unapproved or deleted after reading the IDs, so try another one
+			idsOfMatchingFacts = slices.Delete(idsOfMatchingFacts, i, i+1)
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "could not get random fact by ID %v", idsOfMatchingFacts[i])
+		}
+
+		return mapFactToHandler(randomFact), nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (f *FactsHandler) GetFactsCountMatching(filter FactFilter) (int, error) {
+	f, span := f.trace("GetFactsCountMatching")
+	defer span.End()
+
+	idsOfMatchingFacts, err := f.factsRepository.ReadManyIDs(filter.matches)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get IDs of matching facts")
+	}
+
+	return len(idsOfMatchingFacts), nil
 }
 
 func (f *FactsHandler) GetFactsCount() (int, error) {
+	f, span := f.trace("GetFactsCount")
+	defer span.End()
+
 	factsCount, err := f.factsRepository.Count()
 	if err != nil {
 		return 0, errors.Wrapf(err, "could not get facts count")
@@ -76,3 +293,93 @@ func (f *FactsHandler) GetFactsCount() (int, error) {
 
 	return factsCount, nil
 }
+
+type ListQuery struct {
+	Animal         string
+	Tag            string
+	Language       string
+	CreatedAfter   *time.Time
+	SortDescending bool
+	// Cursor is the next cursor of the previous page, empty for the first page.
+	Cursor string
+	Limit  int
+}
+
+type FactsPage struct {
+	Facts []*Fact
+	// NextCursor points to the next page, it is empty if this is the last page.
+	NextCursor string
+}
+
+type listCursor struct {
+	CreatedAt      time.Time          `json:"c"`
+	ID             primitive.ObjectID `json:"i"`
+	SortDescending bool               `json:"d"`
+}
+
+// ListApproved returns a page of approved facts ordered by creation time.
+func (f *FactsHandler) ListApproved(query ListQuery) (*FactsPage, error) {
+	f, span := f.trace("ListApproved")
+	defer span.End()
+
+	pageQuery := repository.FactsPageQuery{
+		Animal:         query.Animal,
+		Tag:            query.Tag,
+		Language:       query.Language,
+		CreatedAfter:   query.CreatedAfter,
+		SortDescending: query.SortDescending,
+		Limit:          query.Limit + 1,
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeListCursor(query.Cursor)
+		if err != nil || cursor.SortDescending != query.SortDescending {
+			return nil, ErrInvalidCursor
+		}
+		pageQuery.After = &repository.FactsPagePosition{CreatedAt: cursor.CreatedAt, ID: cursor.ID}
+	}
+
+	repositoryFacts, err := f.factsRepository.ReadPage(pageQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get page of approved facts")
+	}
+
+	page := &FactsPage{Facts: []*Fact{}}
+	if len(repositoryFacts) > query.Limit {
+		repositoryFacts = repositoryFacts[:query.Limit]
+		lastFact := repositoryFacts[len(repositoryFacts)-1]
+		page.NextCursor, err = encodeListCursor(listCursor{lastFact.CreatedAt, lastFact.ID, query.SortDescending})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create cursor of next page")
+		}
+	}
+
+	for _, repositoryFact := range repositoryFacts {
+		page.Facts = append(page.Facts, mapFactToHandler(repositoryFact))
+	}
+
+	return page, nil
+}
+
+func encodeListCursor(cursor listCursor) (string, error) {
+	jsonCursor, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(jsonCursor), nil
+}
+
+func decodeListCursor(encodedCursor string) (*listCursor, error) {
+	jsonCursor, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor listCursor
+	if err := json.Unmarshal(jsonCursor, &cursor); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
@@ -2,7 +2,9 @@ package handler_test
 
 import (
 	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/pkg/shuffle"
 	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"reflect"
 	"testing"
@@ -61,9 +63,13 @@ func TestFactsHandler_Get(t *testing.T) {
 				id: exampleID,
 			},
 			want: &handler.Fact{
-				ID:     exampleID.Hex(),
-				Fact:   "The Blue Whale is the largest animal that has ever lived.",
-				Source: "https://factanimal.com/blue-whale/",
+				ID:        exampleID.Hex(),
+				Fact:      "The Blue Whale is the largest animal that has ever lived.",
+				Source:    "https://factanimal.com/blue-whale/",
+				Tags:      []string{},
+				Citations: []handler.Citation{{Type: handler.CitationTypeURL, URL: "https://factanimal.com/blue-whale/"}},
+				CreatedAt: exampleFactApproved.CreatedAt,
+				UpdatedAt: exampleFactApproved.UpdatedAt,
 			},
 			wantErr: false,
 		},
@@ -126,6 +132,20 @@ func TestFactsHandler_Get(t *testing.T) {
 	}
 }
 
+func TestFactsHandler_GetWithVersion(t *testing.T) {
+	fact := exampleFactApproved
+	factsRepository := repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{exampleID: &fact}, false)
+	f := handler.NewFactsHandler(factsRepository)
+
+	_, version, err := f.GetWithVersion(exampleID)
+	if err != nil {
+		t.Fatalf("GetWithVersion() unexpected error = %v", err)
+	}
+	if !version.LastModified.Equal(fact.UpdatedAt) {
+		t.Errorf("GetWithVersion() version = %+v, want LastModified of the update", version)
+	}
+}
+
 func TestFactsHandler_GetRandomApproved(t *testing.T) {
 	type fields struct {
 		factsRepository repository.FactsRepository
@@ -147,9 +167,13 @@ func TestFactsHandler_GetRandomApproved(t *testing.T) {
 				),
 			},
 			want: &handler.Fact{
-				ID:     exampleID.Hex(),
-				Fact:   "The Blue Whale is the largest animal that has ever lived.",
-				Source: "https://factanimal.com/blue-whale/",
+				ID:        exampleID.Hex(),
+				Fact:      "The Blue Whale is the largest animal that has ever lived.",
+				Source:    "https://factanimal.com/blue-whale/",
+				Tags:      []string{},
+				Citations: []handler.Citation{{Type: handler.CitationTypeURL, URL: "https://factanimal.com/blue-whale/"}},
+				CreatedAt: exampleFactApproved.CreatedAt,
+				UpdatedAt: exampleFactApproved.UpdatedAt,
 			},
 			wantErr: false,
 		},
@@ -202,3 +226,181 @@ func TestFactsHandler_GetRandomApproved(t *testing.T) {
 		})
 	}
 }
+
+func TestFactsHandler_GetNextShuffled(t *testing.T) {
+	facts := map[primitive.ObjectID]*repository.Fact{}
+	for i := 0; i < 5; i++ {
+		fact := exampleFactApproved
+		fact.ID = primitive.NewObjectID()
+		facts[fact.ID] = &fact
+	}
+
+	t.Run("get next shuffled returns every fact once per cycle", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, false))
+		token := shuffle.Token{Seed: 1}
+		seen := map[string]bool{}
+		for i := 0; i < len(facts); i++ {
+			var got *handler.Fact
+			var err error
+			got, token, err = f.GetNextShuffled(token)
+			if err != nil {
+				t.Errorf("GetNextShuffled() unexpected error = %v", err)
+				return
+			}
+			if seen[got.ID] {
+				t.Errorf("GetNextShuffled() returned fact %s twice", got.ID)
+				return
+			}
+			seen[got.ID] = true
+		}
+	})
+
+	t.Run("get next shuffled errors due to no facts", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{}, false))
+		if _, _, err := f.GetNextShuffled(shuffle.Token{Seed: 1}); err == nil {
+			t.Errorf("GetNextShuffled() expected error")
+		}
+	})
+}
+
+func TestFactsHandler_GetRandomApprovedMany(t *testing.T) {
+	facts := map[primitive.ObjectID]*repository.Fact{}
+	for i := 0; i < 20; i++ {
+		fact := exampleFactApproved
+		fact.ID = primitive.NewObjectID()
+		facts[fact.ID] = &fact
+	}
+	seed := "fact-deck"
+	otherSeed := "other-fact-deck"
+
+	t.Run("get random facts returns distinct facts", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, false))
+		got, err := f.GetRandomApprovedMany(10, nil)
+		if err != nil {
+			t.Errorf("GetRandomApprovedMany() unexpected error = %v", err)
+			return
+		}
+		if len(got) != 10 {
+			t.Errorf("GetRandomApprovedMany() got %d facts, want 10", len(got))
+		}
+		seen := map[string]bool{}
+		for _, fact := range got {
+			if seen[fact.ID] {
+				t.Errorf("GetRandomApprovedMany() returned fact %s twice", fact.ID)
+			}
+			seen[fact.ID] = true
+		}
+	})
+
+	t.Run("get random facts returns all facts if count exceeds them", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, false))
+		got, err := f.GetRandomApprovedMany(50, nil)
+		if err != nil {
+			t.Errorf("GetRandomApprovedMany() unexpected error = %v", err)
+			return
+		}
+		if len(got) != len(facts) {
+			t.Errorf("GetRandomApprovedMany() got %d facts, want %d", len(got), len(facts))
+		}
+	})
+
+	t.Run("get random facts with seed is reproducible", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, false))
+		first, err := f.GetRandomApprovedMany(5, &seed)
+		if err != nil {
+			t.Errorf("GetRandomApprovedMany() unexpected error = %v", err)
+			return
+		}
+		second, err := f.GetRandomApprovedMany(5, &seed)
+		if err != nil {
+			t.Errorf("GetRandomApprovedMany() unexpected error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("GetRandomApprovedMany() got = %v for the first and %v for the second call with the same seed", first, second)
+		}
+		other, err := f.GetRandomApprovedMany(5, &otherSeed)
+		if err != nil {
+			t.Errorf("GetRandomApprovedMany() unexpected error = %v", err)
+			return
+		}
+		if reflect.DeepEqual(first, other) {
+			t.Errorf("GetRandomApprovedMany() got the same facts for different seeds")
+		}
+	})
+
+	t.Run("get random facts errors due to repository error", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, true))
+		if _, err := f.GetRandomApprovedMany(5, nil); err == nil {
+			t.Errorf("GetRandomApprovedMany() expected error")
+		}
+	})
+}
+
+func TestFactsHandler_ListApproved(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	facts := map[primitive.ObjectID]*repository.Fact{}
+	var ids []string
+	for i := 0; i < 5; i++ {
+		fact := exampleFactApproved
+		fact.ID = primitive.NewObjectID()
+		fact.CreatedAt = createdAt.Add(time.Duration(i) * time.Hour)
+		fact.Animal = "whale"
+		if i%2 == 1 {
+			fact.Animal = "octopus"
+		}
+		facts[fact.ID] = &fact
+		ids = append(ids, fact.ID.Hex())
+	}
+	unapprovedFact := exampleFact
+	unapprovedFact.ID = primitive.NewObjectID()
+	facts[unapprovedFact.ID] = &unapprovedFact
+
+	listIDs := func(t *testing.T, f *handler.FactsHandler, query handler.ListQuery) []string {
+		var got []string
+		for {
+			page, err := f.ListApproved(query)
+			if err != nil {
+				t.Errorf("ListApproved() unexpected error = %v", err)
+				return nil
+			}
+			for _, fact := range page.Facts {
+				got = append(got, fact.ID)
+			}
+			if page.NextCursor == "" {
+				return got
+			}
+			query.Cursor = page.NextCursor
+		}
+	}
+
+	t.Run("list walks all approved facts page by page", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, false))
+		got := listIDs(t, f, handler.ListQuery{Limit: 2})
+		if !reflect.DeepEqual(got, ids) {
+			t.Errorf("ListApproved() got = %v, want %v", got, ids)
+		}
+	})
+
+	t.Run("list sorts descending and filters", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, false))
+		got := listIDs(t, f, handler.ListQuery{Animal: "whale", SortDescending: true, Limit: 1})
+		want := []string{ids[4], ids[2], ids[0]}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ListApproved() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("list errors on cursor of other sort order", func(t *testing.T) {
+		f := handler.NewFactsHandler(repository.NewMockFactsRepository(facts, false))
+		page, err := f.ListApproved(handler.ListQuery{Limit: 1})
+		if err != nil {
+			t.Errorf("ListApproved() unexpected error = %v", err)
+			return
+		}
+		_, err = f.ListApproved(handler.ListQuery{Limit: 1, SortDescending: true, Cursor: page.NextCursor})
+		if !errors.Is(err, handler.ErrInvalidCursor) {
+			t.Errorf("ListApproved() error = %v, want %v", err, handler.ErrInvalidCursor)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	feedSize = 50
+)
+
+type FeedQuery struct {
+	Animal string
+	Tag    string
+}
+
+type FeedItem struct {
+	Fact       *Fact
+	Animal     string
+	Tags       []string
+	ApprovedAt time.Time
+	UpdatedAt  time.Time
+}
+
+type LatestApprovedFeed struct {
+	Items []*FeedItem
+	// LastModified is the latest approval or update time of the items, or the time an approved fact was last removed
+	// if that is later, as the feed could have lost an item with that. It is zero if nothing was approved or removed.
+	LastModified time.Time
+	// ETag is the hash of the IDs and revisions of the items, it changes if any item is added, changed or removed.
+	ETag string
+}
+
+type FeedsHandler struct {
+	factsRepository repository.FactsRepository
+}
+
+func NewFeedsHandler(factsRepository repository.FactsRepository) *FeedsHandler {
+	return &FeedsHandler{factsRepository}
+}
+
+// GetLatestApproved returns the most recently approved facts, the most recently approved first.
+func (h *FeedsHandler) GetLatestApproved(query FeedQuery) (*LatestApprovedFeed, error) {
+	facts, err := h.factsRepository.ReadRecentlyApproved(repository.RecentlyApprovedQuery{
+		Animal: query.Animal,
+		Tag:    query.Tag,
+		Limit:  feedSize,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get recently approved facts")
+	}
+
+	feed := &LatestApprovedFeed{Items: []*FeedItem{}}
+	hash := sha256.New()
+	for _, fact := range facts {
+		// facts that were approved before the approval time was tracked fall back to their creation time
+		approvedAt := fact.ApprovedAt
+		if approvedAt.IsZero() {
+			approvedAt = fact.CreatedAt
+		}
+		updatedAt := fact.UpdatedAt
+		if updatedAt.Before(approvedAt) {
+			updatedAt = approvedAt
+		}
+
+		feed.Items = append(feed.Items, &FeedItem{
+			Fact:       mapFactToHandler(fact),
+			Animal:     fact.Animal,
+			Tags:       fact.Tags,
+			ApprovedAt: approvedAt,
+			UpdatedAt:  updatedAt,
+		})
+		if updatedAt.After(feed.LastModified) {
+			feed.LastModified = updatedAt
+		}
+		hash.Write([]byte(fact.ID.Hex() + ":" + strconv.Itoa(fact.Revision) + ";"))
+	}
+	feed.ETag = hex.EncodeToString(hash.Sum(nil)[:16])
+
+	lastRemoval, err := h.factsRepository.ReadLastRemoval()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get last removal of approved facts")
+	}
+	if lastRemoval.After(feed.LastModified) {
+		feed.LastModified = lastRemoval
+	}
+
+	return feed, nil
+}
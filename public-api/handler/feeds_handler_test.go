@@ -0,0 +1,129 @@
+package handler_test
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+)
+
+func TestFeedsHandler_GetLatestApproved(t *testing.T) {
+	now := time.Now()
+	newFact := func(animal string, tags []string, approved bool, approvedAt time.Time, updatedAt time.Time) *repository.Fact {
+		return &repository.Fact{
+			ID:         primitive.NewObjectID(),
+			Fact:       "fact about " + animal,
+			Animal:     animal,
+			Tags:       tags,
+			Approved:   approved,
+			ApprovedAt: approvedAt,
+			CreatedAt:  now.Add(-72 * time.Hour),
+			UpdatedAt:  updatedAt,
+		}
+	}
+	whale := newFact("whale", []string{"ocean"}, true, now.Add(-time.Hour), now.Add(-time.Hour))
+	octopus := newFact("octopus", []string{"ocean"}, true, now.Add(-48*time.Hour), now.Add(-30*time.Minute))
+	cat := newFact("cat", nil, true, now.Add(-24*time.Hour), now.Add(-24*time.Hour))
+	unapprovedDog := newFact("dog", []string{"ocean"}, false, time.Time{}, now)
+
+	factsRepository := repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{
+		whale.ID: whale, octopus.ID: octopus, cat.ID: cat, unapprovedDog.ID: unapprovedDog,
+	}, false)
+	feedsHandler := handler.NewFeedsHandler(factsRepository)
+
+	tests := []struct {
+		name             string
+		query            handler.FeedQuery
+		wantFacts        []*repository.Fact
+		wantLastModified time.Time
+	}{
+		{
+			name:             "ordered by approval time",
+			wantFacts:        []*repository.Fact{whale, cat, octopus},
+			wantLastModified: octopus.UpdatedAt,
+		},
+		{
+			name:             "filtered by tag",
+			query:            handler.FeedQuery{Tag: "ocean"},
+			wantFacts:        []*repository.Fact{whale, octopus},
+			wantLastModified: octopus.UpdatedAt,
+		},
+		{
+			name:             "filtered by animal",
+			query:            handler.FeedQuery{Animal: "cat"},
+			wantFacts:        []*repository.Fact{cat},
+			wantLastModified: cat.UpdatedAt,
+		},
+		{
+			name:      "no matching facts",
+			query:     handler.FeedQuery{Animal: "dog"},
+			wantFacts: []*repository.Fact{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := feedsHandler.GetLatestApproved(tt.query)
+			if err != nil {
+				t.Fatalf("GetLatestApproved() unexpected error = %v", err)
+			}
+
+			if len(got.Items) != len(tt.wantFacts) {
+				t.Fatalf("GetLatestApproved() returned %d items, want %d", len(got.Items), len(tt.wantFacts))
+			}
+			for i, item := range got.Items {
+				if item.Fact.ID != tt.wantFacts[i].ID.Hex() {
+					t.Errorf("GetLatestApproved() item %d = %v, want %v", i, item.Fact.Fact, tt.wantFacts[i].Fact)
+				}
+			}
+			if !got.LastModified.Equal(tt.wantLastModified) {
+				t.Errorf("GetLatestApproved() last modified = %v, want %v", got.LastModified, tt.wantLastModified)
+			}
+		})
+	}
+
+	t.Run("etag and last modification change with removed items", func(t *testing.T) {
+		before, err := feedsHandler.GetLatestApproved(handler.FeedQuery{Tag: "ocean"})
+		if err != nil {
+			t.Fatalf("GetLatestApproved() unexpected error = %v", err)
+		}
+
+		err = factsRepository.Update(whale.ID, func(fact *repository.Fact) (*repository.Fact, error) {
+			fact.Approved = false
+			return fact, nil
+		})
+		if err != nil {
+			t.Fatalf("Update() unexpected error = %v", err)
+		}
+		defer func() {
+			_ = factsRepository.Update(whale.ID, func(fact *repository.Fact) (*repository.Fact, error) {
+				fact.Approved = true
+				return fact, nil
+			})
+		}()
+		after, err := feedsHandler.GetLatestApproved(handler.FeedQuery{Tag: "ocean"})
+		if err != nil {
+			t.Fatalf("GetLatestApproved() unexpected error = %v", err)
+		}
+		if before.ETag == "" || after.ETag == before.ETag {
+			t.Errorf("GetLatestApproved() etag = %s, then %s, want another etag", before.ETag, after.ETag)
+		}
+		if !after.LastModified.After(before.LastModified) {
+			t.Errorf("GetLatestApproved() last modified = %v, then %v, want the time of the removal", before.LastModified, after.LastModified)
+		}
+
+		octopus.Revision++
+		if changed, _ := feedsHandler.GetLatestApproved(handler.FeedQuery{Tag: "ocean"}); changed.ETag == before.ETag {
+			t.Errorf("GetLatestApproved() etag = %s, want another etag for a new revision of an item", changed.ETag)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		_, err := handler.NewFeedsHandler(repository.NewMockFactsRepository(nil, true)).GetLatestApproved(handler.FeedQuery{})
+		if err == nil {
+			t.Errorf("GetLatestApproved() expected error")
+		}
+	})
+}
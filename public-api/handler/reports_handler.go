@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/neko-neko/echo-logrus/v2/log"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	// FlagThreshold is the number of open reports at which a fact gets flagged for the review queue.
+	FlagThreshold       = 3
+	maxReportTextLength = 1000
+)
+
+var (
+	ErrInvalidReportReason = errors.New("invalid report reason")
+	ErrInvalidReportText   = errors.New("invalid report text")
+)
+
+type Report struct {
+	Reason string `json:"reason"`
+	Text   string `json:"text"`
+}
+
+type ReportsHandler struct {
+	factsRepository   repository.FactsRepository
+	reportsRepository repository.ReportsRepository
+}
+
+func NewReportsHandler(factsRepository repository.FactsRepository, reportsRepository repository.ReportsRepository) *ReportsHandler {
+	return &ReportsHandler{factsRepository, reportsRepository}
+}
+
+// Create stores a report for an approved fact and flags the fact for review once it reached FlagThreshold open reports.
+// The ID of the stored report is returned even if flagging the fact failed, the failure is logged.
+func (r *ReportsHandler) Create(factID primitive.ObjectID, report *Report) (primitive.ObjectID, error) {
+	if !slices.Contains(repository.ReportReasons, repository.ReportReason(report.Reason)) {
+		return primitive.NilObjectID, ErrInvalidReportReason
+	}
+
+	text := strings.TrimSpace(report.Text)
+	if utf8.RuneCountInString(text) > maxReportTextLength {
+		return primitive.NilObjectID, ErrInvalidReportText
+	}
+
+	_, err := r.factsRepository.ReadOne(factID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return primitive.NilObjectID, ErrNotFound
+	} else if err != nil {
+		return primitive.NilObjectID, errors.Wrapf(err, "could not get fact by ID %v", factID)
+	}
+
+	now := time.Now()
+	reportToCreate := &repository.Report{
+		ID:        primitive.NewObjectID(),
+		FactID:    factID,
+		Reason:    repository.ReportReason(report.Reason),
+		Text:      text,
+		Status:    repository.ReportStatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := r.reportsRepository.Create(reportToCreate); err != nil {
+		return primitive.NilObjectID, errors.Wrap(err, "could not create report")
+	}
+
+	// the report is stored, failing to flag the fact must not fail the request, as a retry would create the report
+	// again. The fact is flagged with the next report instead.
+	if err := r.flagIfReported(factID); err != nil {
+		log.Logger().WithError(err).WithField("report_id", reportToCreate.ID.Hex()).Error("failed to flag reported fact")
+	}
+
+	return reportToCreate.ID, nil
+}
+
+// flagIfReported flags the fact for review if it has at least FlagThreshold open reports.
+func (r *ReportsHandler) flagIfReported(factID primitive.ObjectID) error {
+	openReports, err := r.reportsRepository.CountOpen(factID)
+	if err != nil {
+		return errors.Wrapf(err, "could not count open reports of fact %v", factID)
+	}
+	if openReports < FlagThreshold {
+		return nil
+	}
+
+	err = r.factsRepository.Update(factID, func(fact *repository.Fact) (*repository.Fact, error) {
+		fact.Flagged = true
+		return fact, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not flag fact %v", factID)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"testing"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReportsHandler_Create(t *testing.T) {
+	type fields struct {
+		facts   map[primitive.ObjectID]*repository.Fact
+		reports map[primitive.ObjectID]*repository.Report
+	}
+	type args struct {
+		factID primitive.ObjectID
+		report *handler.Report
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantFlagged bool
+		wantErr     error
+	}{
+		{
+			name: "create report works",
+			fields: fields{
+				facts:   map[primitive.ObjectID]*repository.Fact{exampleID: &exampleFactApproved},
+				reports: map[primitive.ObjectID]*repository.Report{},
+			},
+			args: args{
+				factID: exampleID,
+				report: &handler.Report{Reason: "incorrect", Text: "The fin whale is larger."},
+			},
+			wantFlagged: false,
+		},
+		{
+			name: "create report flags fact on reaching the flag threshold",
+			fields: fields{
+				facts: map[primitive.ObjectID]*repository.Fact{exampleID: &exampleFactApproved},
+				reports: map[primitive.ObjectID]*repository.Report{
+					primitive.NewObjectID(): {FactID: exampleID, Status: repository.ReportStatusOpen},
+					primitive.NewObjectID(): {FactID: exampleID, Status: repository.ReportStatusOpen},
+					primitive.NewObjectID(): {FactID: exampleID, Status: repository.ReportStatusDismissed},
+				},
+			},
+			args: args{
+				factID: exampleID,
+				report: &handler.Report{Reason: "outdated"},
+			},
+			wantFlagged: true,
+		},
+		{
+			name: "create report errors on invalid reason",
+			fields: fields{
+				facts:   map[primitive.ObjectID]*repository.Fact{exampleID: &exampleFactApproved},
+				reports: map[primitive.ObjectID]*repository.Report{},
+			},
+			args: args{
+				factID: exampleID,
+				report: &handler.Report{Reason: "boring"},
+			},
+			wantErr: handler.ErrInvalidReportReason,
+		},
+		{
+			name: "create report errors on fact not found",
+			fields: fields{
+				facts:   map[primitive.ObjectID]*repository.Fact{},
+				reports: map[primitive.ObjectID]*repository.Report{},
+			},
+			args: args{
+				factID: exampleID,
+				report: &handler.Report{Reason: "incorrect"},
+			},
+			wantErr: handler.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factsRepository := repository.NewMockFactsRepository(tt.fields.facts, false)
+			r := handler.NewReportsHandler(factsRepository, repository.NewMockReportsRepository(tt.fields.reports, false))
+			_, err := r.Create(tt.args.factID, tt.args.report)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Create() unexpected error = %v", err)
+				return
+			}
+
+			fact, err := factsRepository.ReadOne(tt.args.factID)
+			if err != nil {
+				t.Errorf("ReadOne() unexpected error = %v", err)
+				return
+			}
+			if fact.Flagged != tt.wantFlagged {
+				t.Errorf("Create() fact flagged = %v, want %v", fact.Flagged, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+// failingCountReportsRepository stores reports, but fails to count them.
+type failingCountReportsRepository struct {
+	repository.ReportsRepository
+}
+
+func (f *failingCountReportsRepository) CountOpen(factID primitive.ObjectID) (int, error) {
+	return 0, errors.New("count failed")
+}
+
+func TestReportsHandler_Create_flaggingFails(t *testing.T) {
+	fact := exampleFactApproved
+	reports := map[primitive.ObjectID]*repository.Report{}
+	reportsRepository := &failingCountReportsRepository{repository.NewMockReportsRepository(reports, false)}
+	r := handler.NewReportsHandler(repository.NewMockFactsRepository(map[primitive.ObjectID]*repository.Fact{exampleID: &fact}, false), reportsRepository)
+
+	id, err := r.Create(exampleID, &handler.Report{Reason: "incorrect"})
+	if err != nil {
+		t.Fatalf("Create() unexpected error = %v, want the report created although flagging failed", err)
+	}
+	if _, ok := reports[id]; !ok || len(reports) != 1 {
+		t.Errorf("Create() = %v with reports %v, want the ID of the only stored report", id, reports)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+)
+
+const (
+	trendingWindow   = 7 * 24 * time.Hour
+	trendingHalfLife = 24 * time.Hour
+)
+
+type TrendingHandler struct {
+	factsRepository      repository.FactsRepository
+	serveStatsRepository repository.ServeStatsRepository
+	now                  func() time.Time
+}
+
+func NewTrendingHandler(factsRepository repository.FactsRepository, serveStatsRepository repository.ServeStatsRepository) *TrendingHandler {
+	return &TrendingHandler{factsRepository, serveStatsRepository, time.Now}
+}
+
+// GetTrending returns up to limit approved facts ordered by their serve counts of the last week, where every serve
+// loses half of its weight per day that passed since.
+func (t *TrendingHandler) GetTrending(limit int) ([]*Fact, error) {
+	now := t.now()
+	serveCounts, err := t.serveStatsRepository.ReadCounts(repository.ServeCountsFilter{
+		Granularity: repository.GranularityHour,
+		From:        now.Add(-trendingWindow),
+		To:          now,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get serve counts")
+	}
+
+	scores := map[primitive.ObjectID]float64{}
+	for _, serveCount := range serveCounts {
+		age := now.Sub(serveCount.PeriodStart.Add(repository.GranularityHour.Duration() / 2))
+		scores[serveCount.FactID] += float64(serveCount.Count) * math.Pow(0.5, age.Hours()/trendingHalfLife.Hours())
+	}
+
+	factIDs := make([]primitive.ObjectID, 0, len(scores))
+	for factID := range scores {
+		factIDs = append(factIDs, factID)
+	}
+	sort.Slice(factIDs, func(i, j int) bool {
+		if scores[factIDs[i]] == scores[factIDs[j]] {
+			return factIDs[i].Hex() < factIDs[j].Hex()
+		}
+		return scores[factIDs[i]] > scores[factIDs[j]]
+	})
+
+	trending := []*Fact{}
+	for _, factID := range factIDs {
+		if len(trending) >= limit {
+			break
+		}
+
+		fact, err := t.factsRepository.ReadOne(factID)
+		if errors.Is(err, repository.ErrNotFound) {
+			// facts that got unapproved or deleted in the meantime are no longer trending
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "could not get trending fact by ID %v", factID)
+		}
+
+		trending = append(trending, mapFactToHandler(fact))
+	}
+
+	return trending, nil
+}
@@ -0,0 +1,100 @@
+package handler_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cafo13/animal-facts/pkg/repository"
+	"github.com/cafo13/animal-facts/public-api/handler"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTrendingHandler_GetTrending(t *testing.T) {
+	otherID := primitive.NewObjectID()
+	otherFactApproved := exampleFactApproved
+	otherFactApproved.ID = otherID
+	otherFactApproved.Fact = "Octopuses have three hearts."
+
+	now := time.Now()
+	serveEvents := func(factID primitive.ObjectID, count int, age time.Duration) []*repository.ServeEvent {
+		var events []*repository.ServeEvent
+		for i := 0; i < count; i++ {
+			events = append(events, &repository.ServeEvent{FactID: factID, Endpoint: "random", Timestamp: now.Add(-age)})
+		}
+		return events
+	}
+
+	type fields struct {
+		facts       map[primitive.ObjectID]*repository.Fact
+		serveEvents []*repository.ServeEvent
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		limit   int
+		want    []*handler.Fact
+		wantErr bool
+	}{
+		{
+			name: "recent serves outweigh older serves",
+			fields: fields{
+				facts: map[primitive.ObjectID]*repository.Fact{exampleID: &exampleFactApproved, otherID: &otherFactApproved},
+				serveEvents: append(
+					serveEvents(exampleID, 10, 4*24*time.Hour),
+					serveEvents(otherID, 3, time.Hour)...,
+				),
+			},
+			limit: 10,
+			want: []*handler.Fact{
+				{ID: otherID.Hex(), Fact: otherFactApproved.Fact, Source: otherFactApproved.Source, Tags: []string{}, Citations: []handler.Citation{{Type: handler.CitationTypeURL, URL: otherFactApproved.Source}}, CreatedAt: otherFactApproved.CreatedAt, UpdatedAt: otherFactApproved.UpdatedAt},
+				{ID: exampleID.Hex(), Fact: exampleFactApproved.Fact, Source: exampleFactApproved.Source, Tags: []string{}, Citations: []handler.Citation{{Type: handler.CitationTypeURL, URL: exampleFactApproved.Source}}, CreatedAt: exampleFactApproved.CreatedAt, UpdatedAt: exampleFactApproved.UpdatedAt},
+			},
+		},
+		{
+			name: "trending facts are limited",
+			fields: fields{
+				facts: map[primitive.ObjectID]*repository.Fact{exampleID: &exampleFactApproved, otherID: &otherFactApproved},
+				serveEvents: append(
+					serveEvents(exampleID, 5, time.Hour),
+					serveEvents(otherID, 3, time.Hour)...,
+				),
+			},
+			limit: 1,
+			want: []*handler.Fact{
+				{ID: exampleID.Hex(), Fact: exampleFactApproved.Fact, Source: exampleFactApproved.Source, Tags: []string{}, Citations: []handler.Citation{{Type: handler.CitationTypeURL, URL: exampleFactApproved.Source}}, CreatedAt: exampleFactApproved.CreatedAt, UpdatedAt: exampleFactApproved.UpdatedAt},
+			},
+		},
+		{
+			name: "serves older than a week and facts that no longer exist are ignored",
+			fields: fields{
+				facts: map[primitive.ObjectID]*repository.Fact{exampleID: &exampleFactApproved},
+				serveEvents: append(
+					serveEvents(exampleID, 100, 8*24*time.Hour),
+					serveEvents(otherID, 3, time.Hour)...,
+				),
+			},
+			limit: 10,
+			want:  []*handler.Fact{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serveStatsRepository := repository.NewMockServeStatsRepository(nil, false)
+			if err := serveStatsRepository.RecordServes(tt.fields.serveEvents); err != nil {
+				t.Errorf("RecordServes() unexpected error = %v", err)
+				return
+			}
+
+			h := handler.NewTrendingHandler(repository.NewMockFactsRepository(tt.fields.facts, false), serveStatsRepository)
+			got, err := h.GetTrending(tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTrending() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTrending() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,439 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+)
+
+const (
+	formatContextKey = "render.format"
+)
+
+type Format struct {
+	Name      string
+	MediaType string
+	// aliases are further media types that are accepted for the format
+	aliases []string
+}
+
+var (
+	FormatJSON     = Format{Name: "json", MediaType: echo.MIMEApplicationJSON}
+	FormatText     = Format{Name: "text", MediaType: echo.MIMETextPlain}
+	FormatXML      = Format{Name: "xml", MediaType: echo.MIMEApplicationXML, aliases: []string{echo.MIMETextXML}}
+	FormatCSV      = Format{Name: "csv", MediaType: "text/csv"}
+	FormatYAML     = Format{Name: "yaml", MediaType: "application/yaml", aliases: []string{"application/x-yaml", "text/yaml"}}
+	FormatMarkdown = Format{Name: "markdown", MediaType: "text/markdown"}
+
+	// Formats are all supported formats, the first one is the default.
+	Formats = []Format{FormatJSON, FormatText, FormatXML, FormatCSV, FormatYAML, FormatMarkdown}
+)
+
+// Negotiate is a middleware that selects the response format from the format query parameter or the Accept header
+// and responds with 406 Not Acceptable if none of the supported formats is acceptable. The supported formats are all
+// Formats unless only some of them are passed, the first one is the default.
+func Negotiate(formats ...Format) echo.MiddlewareFunc {
+	if len(formats) == 0 {
+		formats = Formats
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Add(echo.HeaderVary, "Accept")
+
+			format, ok := negotiate(formats, c.QueryParam("format"), c.Request().Header.Get(echo.HeaderAccept))
+			if !ok {
+				var supported []string
+				for _, format := range formats {
+					supported = append(supported, fmt.Sprintf("%s (format=%s)", format.MediaType, format.Name))
+				}
+				return problem.NotAcceptable("none of the requested formats is supported, supported are " + strings.Join(supported, ", "))
+			}
+
+			c.Set(formatContextKey, format)
+			return next(c)
+		}
+	}
+}
+
+func negotiate(formats []Format, formatParam string, accept string) (Format, bool) {
+	if formatParam != "" {
+		for _, format := range formats {
+			if strings.EqualFold(format.Name, formatParam) {
+				return format, true
+			}
+		}
+		return Format{}, false
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return formats[0], true
+	}
+
+	bestFormat, bestQuality, bestSpecificity := Format{}, 0.0, -1
+	for _, acceptedRange := range strings.Split(accept, ",") {
+		mediaRange, quality := parseMediaRange(acceptedRange)
+		if quality <= 0 {
+			continue
+		}
+
+		for _, format := range formats {
+			specificity := matches(mediaRange, format)
+			if specificity < 0 {
+				continue
+			}
+			if quality > bestQuality || (quality == bestQuality && specificity > bestSpecificity) {
+				bestFormat, bestQuality, bestSpecificity = format, quality, specificity
+			}
+			// the formats are ordered by preference, so wildcards select the first matching one
+			break
+		}
+	}
+
+	return bestFormat, bestQuality > 0
+}
+
+func parseMediaRange(acceptedRange string) (string, float64) {
+	parts := strings.Split(acceptedRange, ";")
+	mediaRange := strings.ToLower(strings.TrimSpace(parts[0]))
+	quality := 1.0
+	for _, parameter := range parts[1:] {
+		name, value, found := strings.Cut(strings.TrimSpace(parameter), "=")
+		if found && strings.TrimSpace(name) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+	}
+
+	return mediaRange, quality
+}
+
+// matches returns how specific the media range matches the format (2 exact, 1 type wildcard, 0 full wildcard),
+// or -1 if it doesn't match.
+func matches(mediaRange string, format Format) int {
+	if mediaRange == "*/*" {
+		return 0
+	}
+
+	for _, mediaType := range append([]string{format.MediaType}, format.aliases...) {
+		if mediaRange == mediaType {
+			return 2
+		}
+		if strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")) {
+			return 1
+		}
+	}
+
+	return -1
+}
+
+// FormatOf returns the negotiated format of the request, JSON if the request wasn't negotiated.
+func FormatOf(c echo.Context) Format {
+	if format, ok := c.Get(formatContextKey).(Format); ok {
+		return format
+	}
+
+	return FormatJSON
+}
+
+// Render writes the value in the negotiated format, value is a struct, a pointer to a struct or a slice of them.
+// JSON responses are exactly the same as with c.JSON.
+func Render(c echo.Context, code int, value interface{}) error {
+	format := FormatOf(c)
+	if format.Name == FormatJSON.Name {
+		return c.JSON(code, value)
+	}
+
+	name, records, isList := toRecords(value)
+
+	var body []byte
+	var err error
+	switch format.Name {
+	case FormatText.Name:
+		body = renderText(records)
+	case FormatXML.Name:
+		body, err = renderXML(name, records, isList)
+	case FormatCSV.Name:
+		body, err = renderCSV(records)
+	case FormatYAML.Name:
+		body, err = renderYAML(records, isList)
+	case FormatMarkdown.Name:
+		body = renderMarkdown(records)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Blob(code, format.MediaType+"; charset=UTF-8", body)
+}
+
+// Problem writes the problem details in the negotiated format, it is the problem.RenderFunc of the public api. Problem
+// details in JSON are left to the problem package, as they have their own media type.
+func Problem(c echo.Context, details *problem.Problem) (bool, error) {
+	if FormatOf(c).Name == FormatJSON.Name {
+		return false, nil
+	}
+
+	return true, Render(c, details.Status, details)
+}
+
+type field struct {
+	name  string
+	value string
+	// isString is false for numbers and booleans, which don't need quotes
+	isString bool
+}
+
+type record []field
+
+func (r record) get(name string) (string, bool) {
+	for _, f := range r {
+		if f.name == name {
+			return f.value, true
+		}
+	}
+
+	return "", false
+}
+
+// toRecords flattens the value into records of fields in the order of the struct fields, named by their json tags.
+func toRecords(value interface{}) (string, []record, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice {
+		return lowerFirst(v.Type().Name()), []record{toRecord(v)}, false
+	}
+
+	elementType := v.Type().Elem()
+	for elementType.Kind() == reflect.Pointer {
+		elementType = elementType.Elem()
+	}
+
+	records := make([]record, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		records = append(records, toRecord(reflect.Indirect(v.Index(i))))
+	}
+
+	return lowerFirst(elementType.Name()), records, true
+}
+
+func toRecord(v reflect.Value) record {
+	var r record
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = structField.Name
+		}
+
+		fieldValue := v.Field(i)
+		if strings.Contains(options, "omitempty") && fieldValue.IsZero() {
+			continue
+		}
+		for fieldValue.Kind() == reflect.Pointer {
+			fieldValue = fieldValue.Elem()
+		}
+
+		isString := fieldValue.Kind() != reflect.Bool && !fieldValue.CanInt() && !fieldValue.CanUint() && !fieldValue.CanFloat()
+		r = append(r, field{name, fmt.Sprint(fieldValue.Interface()), isString})
+	}
+
+	return r
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return "item"
+	}
+
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// renderText writes one line per record, facts as the fact followed by the source in parentheses.
+func renderText(records []record) []byte {
+	var buffer bytes.Buffer
+	for _, r := range records {
+		if fact, isFact := r.get("fact"); isFact {
+			buffer.WriteString(fact)
+			if source, _ := r.get("source"); source != "" {
+				buffer.WriteString(" (" + source + ")")
+			}
+		} else {
+			var values []string
+			for _, f := range r {
+				values = append(values, f.value)
+			}
+			buffer.WriteString(strings.Join(values, " "))
+		}
+		buffer.WriteString("\n")
+	}
+
+	return buffer.Bytes()
+}
+
+func renderXML(name string, records []record, isList bool) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buffer)
+
+	if isList {
+		if err := encoder.EncodeToken(xml.StartElement{Name: xml.Name{Local: name + "s"}}); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range records {
+		if err := encoder.EncodeToken(xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return nil, err
+		}
+		for _, f := range r {
+			if err := encoder.EncodeElement(f.value, xml.StartElement{Name: xml.Name{Local: f.name}}); err != nil {
+				return nil, err
+			}
+		}
+		if err := encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return nil, err
+		}
+	}
+	if isList {
+		if err := encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: name + "s"}}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	buffer.WriteString("\n")
+
+	return buffer.Bytes(), nil
+}
+
+func renderCSV(records []record) ([]byte, error) {
+	var header []string
+	for _, r := range records {
+		for _, f := range r {
+			if !slices.Contains(header, f.name) {
+				header = append(header, f.name)
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	if len(header) == 0 {
+		return buffer.Bytes(), nil
+	}
+
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		row := make([]string, len(header))
+		for i, name := range header {
+			row[i], _ = r.get(name)
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+
+	return buffer.Bytes(), writer.Error()
+}
+
+func renderYAML(records []record, isList bool) ([]byte, error) {
+	toNode := func(r record) *yaml.Node {
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range r {
+			valueNode := &yaml.Node{Kind: yaml.ScalarNode, Value: f.value}
+			if f.isString {
+				valueNode.Tag = "!!str"
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.name}, valueNode)
+		}
+		return node
+	}
+
+	var document *yaml.Node
+	if isList {
+		document = &yaml.Node{Kind: yaml.SequenceNode}
+		for _, r := range records {
+			document.Content = append(document.Content, toNode(r))
+		}
+	} else {
+		document = toNode(records[0])
+	}
+
+	return yaml.Marshal(document)
+}
+
+// renderMarkdown writes facts as block quotes with the source as link, other records as a table.
+func renderMarkdown(records []record) []byte {
+	var buffer bytes.Buffer
+	if len(records) == 0 {
+		return buffer.Bytes()
+	}
+
+	if _, isFact := records[0].get("fact"); isFact {
+		for i, r := range records {
+			if i > 0 {
+				buffer.WriteString("\n")
+			}
+			fact, _ := r.get("fact")
+			buffer.WriteString("> " + escapeMarkdown(fact) + "\n")
+			if source, _ := r.get("source"); source != "" {
+				buffer.WriteString(">\n> — <" + source + ">\n")
+			}
+		}
+		return buffer.Bytes()
+	}
+
+	var names, separators []string
+	for _, f := range records[0] {
+		names = append(names, escapeMarkdown(f.name))
+		separators = append(separators, "---")
+	}
+	buffer.WriteString("| " + strings.Join(names, " | ") + " |\n")
+	buffer.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+	for _, r := range records {
+		var values []string
+		for _, f := range r {
+			values = append(values, escapeMarkdown(f.value))
+		}
+		buffer.WriteString("| " + strings.Join(values, " | ") + " |\n")
+	}
+
+	return buffer.Bytes()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,223 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/cafo13/animal-facts/pkg/problem"
+)
+
+type testFact struct {
+	ID     string `json:"id"`
+	Fact   string `json:"fact"`
+	Source string `json:"source"`
+}
+
+type testCount struct {
+	Count int `json:"count"`
+}
+
+var exampleFact = &testFact{
+	ID:     "6578bf140e487ecc049c7594",
+	Fact:   "The Blue Whale is the largest animal that has ever lived.",
+	Source: "https://factanimal.com/blue-whale/",
+}
+
+func Test_negotiate(t *testing.T) {
+	tests := []struct {
+		name        string
+		formatParam string
+		accept      string
+		want        Format
+		wantOk      bool
+	}{
+		{name: "defaults to json without accept header", want: FormatJSON, wantOk: true},
+		{name: "wildcard selects json", accept: "*/*", want: FormatJSON, wantOk: true},
+		{name: "exact media type", accept: "text/csv", want: FormatCSV, wantOk: true},
+		{name: "media type alias", accept: "text/xml", want: FormatXML, wantOk: true},
+		{name: "type wildcard", accept: "text/*", want: FormatText, wantOk: true},
+		{name: "highest quality wins", accept: "application/json;q=0.5, text/markdown;q=0.9", want: FormatMarkdown, wantOk: true},
+		{name: "more specific wins on same quality", accept: "*/*, application/yaml", want: FormatYAML, wantOk: true},
+		{name: "format parameter overrides accept header", formatParam: "xml", accept: "application/json", want: FormatXML, wantOk: true},
+		{name: "unsupported media type", accept: "application/pdf", wantOk: false},
+		{name: "excluded media type", accept: "application/json;q=0", wantOk: false},
+		{name: "unsupported format parameter", formatParam: "pdf", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := negotiate(Formats, tt.formatParam, tt.accept)
+			if ok != tt.wantOk {
+				t.Errorf("negotiate() ok = %v, want %v", ok, tt.wantOk)
+				return
+			}
+			if ok && got.Name != tt.want.Name {
+				t.Errorf("negotiate() got = %v, want %v", got.Name, tt.want.Name)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name            string
+		accept          string
+		value           interface{}
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "json",
+			accept:          "application/json",
+			value:           exampleFact,
+			wantContentType: "application/json; charset=UTF-8",
+			wantBody:        `{"id":"6578bf140e487ecc049c7594","fact":"The Blue Whale is the largest animal that has ever lived.","source":"https://factanimal.com/blue-whale/"}` + "\n",
+		},
+		{
+			name:            "plain text",
+			accept:          "text/plain",
+			value:           exampleFact,
+			wantContentType: "text/plain; charset=UTF-8",
+			wantBody:        "The Blue Whale is the largest animal that has ever lived. (https://factanimal.com/blue-whale/)\n",
+		},
+		{
+			name:            "xml list",
+			accept:          "application/xml",
+			value:           []*testFact{exampleFact},
+			wantContentType: "application/xml; charset=UTF-8",
+			wantBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<testFacts><testFact><id>6578bf140e487ecc049c7594</id><fact>The Blue Whale is the largest animal that has ever lived.</fact><source>https://factanimal.com/blue-whale/</source></testFact></testFacts>` + "\n",
+		},
+		{
+			name:            "csv",
+			accept:          "text/csv",
+			value:           []*testFact{exampleFact},
+			wantContentType: "text/csv; charset=UTF-8",
+			wantBody:        "id,fact,source\n6578bf140e487ecc049c7594,The Blue Whale is the largest animal that has ever lived.,https://factanimal.com/blue-whale/\n",
+		},
+		{
+			name:            "yaml",
+			accept:          "application/yaml",
+			value:           &testCount{Count: 3},
+			wantContentType: "application/yaml; charset=UTF-8",
+			wantBody:        "count: 3\n",
+		},
+		{
+			name:            "markdown",
+			accept:          "text/markdown",
+			value:           exampleFact,
+			wantContentType: "text/markdown; charset=UTF-8",
+			wantBody:        "> The Blue Whale is the largest animal that has ever lived.\n>\n> — <https://factanimal.com/blue-whale/>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := Negotiate()(func(c echo.Context) error {
+				return Render(c, http.StatusOK, tt.value)
+			})(c)
+			if err != nil {
+				t.Errorf("Render() unexpected error = %v", err)
+				return
+			}
+
+			if got := rec.Header().Get(echo.HeaderContentType); got != tt.wantContentType {
+				t.Errorf("Render() content type = %v, want %v", got, tt.wantContentType)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("Render() body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+
+	t.Run("not acceptable", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAccept, "image/png")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := Negotiate()(func(c echo.Context) error {
+			return Render(c, http.StatusOK, exampleFact)
+		})(c)
+		var problemErr *problem.Error
+		if !errors.As(err, &problemErr) || problemErr.Status != http.StatusNotAcceptable {
+			t.Errorf("Render() error = %v, want %v", err, http.StatusNotAcceptable)
+		}
+	})
+
+	t.Run("not one of the passed formats", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?format=csv", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := Negotiate(FormatJSON)(func(c echo.Context) error {
+			return Render(c, http.StatusOK, exampleFact)
+		})(c)
+		var problemErr *problem.Error
+		if !errors.As(err, &problemErr) || problemErr.Status != http.StatusNotAcceptable {
+			t.Errorf("Render() error = %v, want %v", err, http.StatusNotAcceptable)
+		}
+	})
+}
+
+func TestProblem(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewHTTPErrorHandler(Problem)
+	e.GET("/facts/:id", func(c echo.Context) error {
+		return problem.NotFound("fact with ID '1' not found")
+	}, Negotiate())
+
+	tests := []struct {
+		format          string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			format:          "xml",
+			wantContentType: "application/xml; charset=UTF-8",
+			wantBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<problem><type>urn:animal-facts:problem:not_found</type><title>Not Found</title><status>404</status>` +
+				`<detail>fact with ID &#39;1&#39; not found</detail><instance>/facts/1</instance><code>not_found</code></problem>` + "\n",
+		},
+		{
+			format:          "text",
+			wantContentType: "text/plain; charset=UTF-8",
+			wantBody:        "urn:animal-facts:problem:not_found Not Found 404 fact with ID '1' not found /facts/1 not_found\n",
+		},
+		{
+			format:          "csv",
+			wantContentType: "text/csv; charset=UTF-8",
+			wantBody:        "type,title,status,detail,instance,code\nurn:animal-facts:problem:not_found,Not Found,404,fact with ID '1' not found,/facts/1,not_found\n",
+		},
+		{
+			format:          "json",
+			wantContentType: problem.MediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/facts/1?format="+tt.format, nil))
+
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+			if got := rec.Header().Get(echo.HeaderContentType); got != tt.wantContentType {
+				t.Errorf("content type = %v, want %v", got, tt.wantContentType)
+			}
+			if got := rec.Body.String(); tt.wantBody != "" && got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -2,24 +2,61 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"fmt"
+	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/labstack/echo/v4"
 	"github.com/neko-neko/echo-logrus/v2/log"
 	"github.com/pkg/errors"
 
+	animalfacts "github.com/cafo13/animal-facts"
+	"github.com/cafo13/animal-facts/pkg/analytics"
+	"github.com/cafo13/animal-facts/pkg/apikey"
+	"github.com/cafo13/animal-facts/pkg/events"
+	"github.com/cafo13/animal-facts/pkg/health"
+	"github.com/cafo13/animal-facts/pkg/httpcache"
 	logger "github.com/cafo13/animal-facts/pkg/log"
+	"github.com/cafo13/animal-facts/pkg/metrics"
+	"github.com/cafo13/animal-facts/pkg/ratelimit"
 	"github.com/cafo13/animal-facts/pkg/repository"
 	"github.com/cafo13/animal-facts/pkg/router"
 	"github.com/cafo13/animal-facts/pkg/service"
+	"github.com/cafo13/animal-facts/pkg/shuffle"
+	"github.com/cafo13/animal-facts/pkg/tracing"
 	"github.com/cafo13/animal-facts/public-api/api"
+	"github.com/cafo13/animal-facts/public-api/graphql"
 	"github.com/cafo13/animal-facts/public-api/handler"
+	"github.com/cafo13/animal-facts/public-api/render"
 )
 
 var (
-	mongoDbUri string
+	mongoDbUri      string
+	metricsPort     int
+	tracingExporter = tracing.ExporterNone
+	// shutdownDrainPeriod is the time the router keeps serving requests after the readiness check fails on shutdown
+	shutdownDrainPeriod  = 5 * time.Second
+	shuffleTokenSecret   []byte
+	graphQLIntrospection bool
+	cachePolicies        = api.DefaultCachePolicies
+	// publicBaseUrl is the base URL of the links in responses, e.g. of feeds and embed snippets
+	publicBaseUrl      = "http://localhost:8081"
+	rateLimit          = ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Requests: 60, Period: time.Minute}
+	rateLimitKey       = "ip"
+	rateLimitStoreName = "memory"
+	// apiKeyRateLimitRequests replaces the requests of the rate limit for requests with an API key
+	apiKeyRateLimitRequests = 600
+	// apiV1Deprecation is announced on all responses of the api v1 since the api v2 was released
+	apiV1Deprecation = router.Deprecation{
+		Since: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Link:  "https://github.com/cafo13/animal-facts#api-versions",
+	}
 )
 
 // Run
@@ -42,12 +79,15 @@ func Run() {
 
 	loadEnv()
 
-	factsRouter, err := setupServiceDependencies()
+	healthChecks := health.New()
+	factsRouter, workers, err := setupServiceDependencies(healthChecks)
 	if err != nil {
 		panic(errors.Wrap(err, "failed to setup service dependencies"))
 	}
 
-	svc := service.NewService(factsRouter)
+	svc := service.NewService(factsRouter, workers...)
+	svc.SetDrainPeriod(shutdownDrainPeriod)
+	healthChecks.AddLivenessCheck(health.Check{Name: "workers", Func: svc.CheckWorkers})
 
 	apiPortStr, ok := os.LookupEnv("PUBLIC_API_PORT")
 	if !ok {
@@ -81,26 +121,283 @@ func loadEnv() {
 	if !ok {
 		panic("MONGODB_URI environment variable is not set")
 	}
+
+	metricsPortStr, ok := os.LookupEnv("PUBLIC_API_METRICS_PORT")
+	if !ok {
+		metricsPortStr = "9101"
+		log.Logger().Infof("PUBLIC_API_METRICS_PORT environment variable is not set, using default value %s", metricsPortStr)
+	}
+
+	metricsPort, err = strconv.Atoi(metricsPortStr)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to parse PUBLIC_API_METRICS_PORT environment variable, only integer values are allowed (like 9101)"))
+	}
+
+	shuffleTokenSecretStr, ok := os.LookupEnv("SHUFFLE_TOKEN_SECRET")
+	if ok && shuffleTokenSecretStr != "" {
+		shuffleTokenSecret = []byte(shuffleTokenSecretStr)
+	} else {
+		log.Logger().Warn("SHUFFLE_TOKEN_SECRET environment variable is not set, using a random secret, shuffle tokens will not be valid after a restart or on other replicas")
+		shuffleTokenSecret = make([]byte, 32)
+		if _, err := rand.Read(shuffleTokenSecret); err != nil {
+			panic(errors.Wrap(err, "failed to generate random shuffle token secret"))
+		}
+	}
+
+	publicBaseUrlStr, ok := os.LookupEnv("PUBLIC_API_BASE_URL")
+	if ok && publicBaseUrlStr != "" {
+		baseUrl, err := url.Parse(publicBaseUrlStr)
+		if err != nil || (baseUrl.Scheme != "http" && baseUrl.Scheme != "https") || baseUrl.Host == "" || strings.Trim(baseUrl.Path, "/") != "" {
+			panic("failed to parse PUBLIC_API_BASE_URL environment variable, only http or https URLs without path are allowed (like https://animal-facts.cafo.dev)")
+		}
+		publicBaseUrl = strings.TrimSuffix(publicBaseUrlStr, "/")
+	} else {
+		log.Logger().Warnf("PUBLIC_API_BASE_URL environment variable is not set, links in responses use the default value %s", publicBaseUrl)
+	}
+
+	cachePoliciesStr, ok := os.LookupEnv("CACHE_CONTROL_POLICIES")
+	if ok && cachePoliciesStr != "" {
+		overrides, err := httpcache.ParsePolicies(cachePoliciesStr)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to parse CACHE_CONTROL_POLICIES environment variable"))
+		}
+		for name := range overrides {
+			if _, exists := api.DefaultCachePolicies[name]; !exists {
+				panic(fmt.Sprintf("failed to parse CACHE_CONTROL_POLICIES environment variable, route name '%s' is not valid, valid names are %s", name, strings.Join(api.DefaultCachePolicies.Names(), ", ")))
+			}
+		}
+		cachePolicies = api.DefaultCachePolicies.With(overrides)
+	}
+
+	rateLimitRequestsStr, ok := os.LookupEnv("RATE_LIMIT_REQUESTS")
+	if ok && rateLimitRequestsStr != "" {
+		var err error
+		rateLimit.Requests, err = strconv.Atoi(rateLimitRequestsStr)
+		if err != nil || rateLimit.Requests < 0 {
+			panic("failed to parse RATE_LIMIT_REQUESTS environment variable, only positive integer values or 0 to disable the rate limit are allowed (like 60)")
+		}
+	}
+
+	apiKeyRateLimitRequestsStr, ok := os.LookupEnv("RATE_LIMIT_API_KEY_REQUESTS")
+	if ok && apiKeyRateLimitRequestsStr != "" {
+		var err error
+		apiKeyRateLimitRequests, err = strconv.Atoi(apiKeyRateLimitRequestsStr)
+		if err != nil || apiKeyRateLimitRequests < 0 {
+			panic("failed to parse RATE_LIMIT_API_KEY_REQUESTS environment variable, only positive integer values or 0 to disable the rate limit are allowed (like 600)")
+		}
+	}
+
+	rateLimitPeriodStr, ok := os.LookupEnv("RATE_LIMIT_PERIOD")
+	if ok && rateLimitPeriodStr != "" {
+		var err error
+		rateLimit.Period, err = time.ParseDuration(rateLimitPeriodStr)
+		if err != nil || rateLimit.Period < time.Second {
+			panic("failed to parse RATE_LIMIT_PERIOD environment variable, only durations of at least a second are allowed (like 1m or 1h)")
+		}
+	}
+
+	rateLimitAlgorithmStr, ok := os.LookupEnv("RATE_LIMIT_ALGORITHM")
+	if ok && rateLimitAlgorithmStr != "" {
+		var err error
+		rateLimit.Algorithm, err = ratelimit.ParseAlgorithm(rateLimitAlgorithmStr)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to parse RATE_LIMIT_ALGORITHM environment variable"))
+		}
+	}
+
+	rateLimitKeyStr, ok := os.LookupEnv("RATE_LIMIT_KEY")
+	if ok && rateLimitKeyStr != "" {
+		if rateLimitKeyStr != "ip" && rateLimitKeyStr != "route" {
+			panic("failed to parse RATE_LIMIT_KEY environment variable, only ip or route are allowed")
+		}
+		rateLimitKey = rateLimitKeyStr
+	}
+
+	rateLimitStoreStr, ok := os.LookupEnv("RATE_LIMIT_STORE")
+	if ok && rateLimitStoreStr != "" {
+		if rateLimitStoreStr != "memory" && rateLimitStoreStr != "mongodb" {
+			panic("failed to parse RATE_LIMIT_STORE environment variable, only memory or mongodb are allowed")
+		}
+		rateLimitStoreName = rateLimitStoreStr
+	}
+
+	graphQLIntrospectionStr, ok := os.LookupEnv("GRAPHQL_INTROSPECTION")
+	if ok && graphQLIntrospectionStr != "" {
+		var err error
+		graphQLIntrospection, err = strconv.ParseBool(graphQLIntrospectionStr)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to parse GRAPHQL_INTROSPECTION environment variable, only boolean values are allowed (like true or false)"))
+		}
+	}
+
+	tracingExporterStr, ok := os.LookupEnv("TRACING_EXPORTER")
+	if ok && tracingExporterStr != "" {
+		var err error
+		tracingExporter, err = tracing.ParseExporter(tracingExporterStr)
+		if err != nil {
+			panic(errors.Wrap(err, "failed to parse TRACING_EXPORTER environment variable"))
+		}
+	}
+
+	apiV1SunsetStr, ok := os.LookupEnv("API_V1_SUNSET")
+	if ok && apiV1SunsetStr != "" {
+		var err error
+		apiV1Deprecation.Sunset, err = time.Parse(time.RFC3339, apiV1SunsetStr)
+		if err != nil {
+			panic("failed to parse API_V1_SUNSET environment variable, only RFC 3339 timestamps are allowed (like 2027-06-30T00:00:00Z)")
+		}
+	}
+
+	shutdownDrainPeriodStr, ok := os.LookupEnv("SHUTDOWN_DRAIN_PERIOD")
+	if ok && shutdownDrainPeriodStr != "" {
+		var err error
+		shutdownDrainPeriod, err = time.ParseDuration(shutdownDrainPeriodStr)
+		if err != nil || shutdownDrainPeriod < 0 {
+			panic("failed to parse SHUTDOWN_DRAIN_PERIOD environment variable, only durations are allowed (like 5s)")
+		}
+	}
 }
 
-func setupServiceDependencies() (*router.Router, error) {
-	factsRepository, err := repository.NewMongoDBFactsRepository(mongoDbUri)
+func setupServiceDependencies(healthChecks *health.Health) (*router.Router, []service.Worker, error) {
+	tracingProvider, err := tracing.NewProvider(context.Background(), tracingExporter, "public-api", animalfacts.Version())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup tracing")
+	}
+
+	metricsRegistry := metrics.NewRegistry("public-api", animalfacts.Version())
+	mongoDBConnection, err := repository.NewMongoDBConnection(mongoDbUri, metrics.NewPoolMonitor(metricsRegistry), tracing.NewCommandMonitor())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup mongo db connection")
+	}
+
+	factsRepository := tracing.NewTracingFactsRepository(
+		metrics.NewInstrumentingFactsRepository(repository.NewMongoDBFactsRepositoryFromConnection(mongoDBConnection), metricsRegistry),
+	)
+	reportsRepository := repository.NewMongoDBReportsRepository(mongoDBConnection)
+	serveStatsRepository, err := repository.NewMongoDBServeStatsRepository(mongoDBConnection)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup serve stats repository")
+	}
+	apiKeysRepository, err := repository.NewMongoDBAPIKeysRepository(mongoDBConnection)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to setup mongo db facts repository")
+		return nil, nil, errors.Wrap(err, "failed to setup api keys repository")
 	}
 
+	serveRecorder := analytics.NewServeRecorder(serveStatsRepository)
+
 	factsHandler := handler.NewFactsHandler(factsRepository)
-	factsApi := api.NewFactsApi(factsHandler)
-	factsApi.SetupRoutes()
-	factsRouter := router.NewRouter()
-	for _, route := range factsApi.GetRoutes() {
-		err := factsRouter.RegisterRoute(route)
+	factsApi := api.NewFactsApi(factsHandler, serveRecorder, shuffle.NewCodec(shuffleTokenSecret), cachePolicies)
+
+	reportsHandler := handler.NewReportsHandler(factsRepository, reportsRepository)
+	reportsApi := api.NewReportsApi(reportsHandler)
+
+	trendingHandler := handler.NewTrendingHandler(factsRepository, serveStatsRepository)
+	trendingApi := api.NewTrendingApi(trendingHandler)
+
+	cardsHandler := handler.NewCardsHandler(factsRepository)
+	cardsApi := api.NewCardsApi(cardsHandler, factsHandler, cachePolicies)
+
+	oEmbedApi := api.NewOEmbedApi(factsHandler, cachePolicies)
+
+	feedsHandler := handler.NewFeedsHandler(factsRepository)
+	feedsApi := api.NewFeedsApi(feedsHandler, cachePolicies)
+
+	graphQLServer, err := graphql.NewServer(factsHandler, factsRepository, graphql.Options{
+		MaxDepth:      graphql.DefaultMaxDepth,
+		MaxComplexity: graphql.DefaultMaxComplexity,
+		Introspection: graphQLIntrospection,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to setup graphql server")
+	}
+	graphQLApi := api.NewGraphQLApi(graphQLServer)
+
+	eventBus := events.NewBus()
+	approvedFactsWatcher := handler.NewApprovedFactsWatcher(factsRepository, eventBus)
+	streamsApi := api.NewStreamsApi(eventBus)
+
+	healthChecks.AddReadinessCheck(health.Check{Name: "mongodb", Func: factsRepository.Ping})
+	healthApi := health.NewHealthApi(healthChecks, "/health-public")
+
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if rateLimitStoreName == "mongodb" {
+		rateLimitStore, err = repository.NewMongoDBRateLimitRepository(mongoDBConnection)
 		if err != nil {
-			log.Logger().WithError(err).Errorf("failed to register route %s", route.Path)
-		} else {
-			log.Logger().Infof("registered route %s", route.Path)
+			return nil, nil, errors.Wrap(err, "failed to setup rate limit store")
 		}
 	}
 
-	return factsRouter, nil
+	// errors are rendered in the negotiated format of the route like its responses
+	factsRouter := router.NewRouter("public-api").RenderProblems(render.Problem)
+	factsRouter.Use(metricsRegistry.Middleware())
+	factsRouter.Use(api.BaseUrl(publicBaseUrl))
+	anonymousLimiter := newRateLimiter("anonymous", rateLimitStore, rateLimit)
+	apiKeyLimiter := newRateLimiter("api-key", rateLimitStore, ratelimit.Limit{Algorithm: rateLimit.Algorithm, Requests: apiKeyRateLimitRequests, Period: rateLimit.Period})
+	// requests with invalid keys count for the anonymous limit of their IP address, so that guessing keys is limited
+	// before the keys are read from the database
+	factsRouter.Use(apikey.NewAuthenticator(apiKeysRepository).LimitFailures(anonymousLimiter, ratelimit.KeyByIP).Middleware())
+	// probes of load balancers are not rate limited, they would use up the limit of their IP address
+	factsRouter.Use(healthApi.ExceptProbes(apikey.Select(
+		rateLimitMiddleware(anonymousLimiter, rateLimitKeyFunc()),
+		rateLimitMiddleware(apiKeyLimiter, apikey.KeyByID),
+	)))
+	// routes with a scope need an api key with the scope
+	factsRouter.Authorize(func(scope string) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{apikey.RequireScope(scope)}
+	})
+	err = factsRouter.Mount(api.NewDocsApi(), graphQLApi, healthApi)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to mount routes")
+	}
+	for _, route := range oEmbedApi.DiscoveryRoutes() {
+		if err := factsRouter.RegisterRoute(route); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to register oembed route")
+		}
+	}
+	err = factsRouter.Version("v1").
+		Deprecate(apiV1Deprecation).
+		Mount(factsApi, reportsApi, trendingApi, cardsApi, oEmbedApi, feedsApi, streamsApi)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to mount routes of api v1")
+	}
+	// the api v2 serves the same facts of the same handlers in the envelope of its documents
+	err = factsRouter.Version("v2").Mount(api.NewFactsApiV2(factsApi, trendingHandler))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to mount routes of api v2")
+	}
+
+	metricsServer := metrics.NewServer(metricsPort, metricsRegistry)
+	metricsServer.Handle("/debug/routes", factsRouter.RoutesHandler())
+
+	return factsRouter, []service.Worker{serveRecorder, eventBus, approvedFactsWatcher, metricsServer, tracingProvider, healthChecks}, nil
+}
+
+// newRateLimiter creates the limiter of the clients with the name, a limit of 0 requests disables it and returns nil.
+func newRateLimiter(name string, store ratelimit.Store, limit ratelimit.Limit) *ratelimit.Limiter {
+	if limit.Requests == 0 {
+		log.Logger().Infof("requests of %s clients are not rate limited", name)
+		return nil
+	}
+
+	log.Logger().Infof("limiting requests of %s clients to %d per %s (%s, %s store)", name, limit.Requests, limit.Period, limit.Algorithm, rateLimitStoreName)
+	return ratelimit.NewLimiter("public-api-"+name, store, limit)
+}
+
+// rateLimitMiddleware limits the requests with the limiter, a nil limiter does not limit them.
+func rateLimitMiddleware(limiter *ratelimit.Limiter, keyFunc ratelimit.KeyFunc) echo.MiddlewareFunc {
+	if limiter == nil {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	return limiter.Middleware(keyFunc)
+}
+
+func rateLimitKeyFunc() ratelimit.KeyFunc {
+	if rateLimitKey == "route" {
+		return ratelimit.KeyByRoute
+	}
+
+	return ratelimit.KeyByIP
 }
@@ -0,0 +1,17 @@
+package server
+
+// The general info of the swagger docs of the api v2, the docs of the api v1 are generated from the general info at Run.
+//
+// @title           Animal Facts Public API
+// @version         0.0.4
+// @description     This API provides facts about animals. All responses are JSON documents with data, meta and links,
+// @description     errors are problem details (RFC 7807).
+//
+// @license.name  MIT
+// @license.url   https://github.com/cafo13/animal-facts/blob/main/LICENSE
+//
+// @host      https://animal-facts.cafo.dev
+// @BasePath  /api/v2
+//
+// @externalDocs.description  OpenAPI
+// @externalDocs.url          https://swagger.io/resources/open-api/
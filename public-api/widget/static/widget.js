@@ -0,0 +1,87 @@
+// Animal Facts widget: renders every <blockquote class="animal-fact" data-fact-id="..."> of the page with the fact
+// from the public api. data-fact-id is the ID of a fact or "random" for another random fact on every page view. The
+// content of the blockquote is kept if the fact can't be loaded, so embeds work without this script as well.
+(function () {
+  "use strict";
+
+  var script = document.currentScript;
+  var origin = script ? new URL(script.src).origin : "https://animal-facts.cafo.dev";
+  var apiUrl = origin + "/api/v1";
+
+  function factUrl(id) {
+    return id === "random" ? apiUrl + "/facts" : apiUrl + "/facts/" + encodeURIComponent(id);
+  }
+
+  function isHttpUrl(value) {
+    try {
+      var url = new URL(value);
+      return url.protocol === "https:" || url.protocol === "http:";
+    } catch (e) {
+      return false;
+    }
+  }
+
+  function link(href, text) {
+    var a = document.createElement("a");
+    a.href = href;
+    a.target = "_blank";
+    a.rel = "nofollow noopener";
+    a.textContent = text;
+    return a;
+  }
+
+  // render replaces the content of the element with the fact, only textContent is used so that facts can't inject HTML
+  function render(element, fact) {
+    var text = document.createElement("p");
+    text.style.margin = "0 0 .5em";
+    text.textContent = fact.fact;
+
+    var footer = document.createElement("footer");
+    footer.style.fontSize = ".85em";
+    footer.style.color = "#6b7280";
+    if (fact.source) {
+      footer.appendChild(document.createTextNode("— "));
+      footer.appendChild(isHttpUrl(fact.source) ? link(fact.source, fact.source) : document.createTextNode(fact.source));
+      footer.appendChild(document.createTextNode(" "));
+    }
+    footer.appendChild(document.createTextNode("via "));
+    footer.appendChild(link(apiUrl + "/facts/" + encodeURIComponent(fact.id), "Animal Facts"));
+
+    element.replaceChildren(text, footer);
+    element.setAttribute("data-rendered", "true");
+  }
+
+  function load(element) {
+    var id = element.getAttribute("data-fact-id");
+    if (!id || element.getAttribute("data-rendered") === "true") {
+      return;
+    }
+
+    fetch(factUrl(id), { headers: { Accept: "application/json" } })
+      .then(function (response) {
+        if (!response.ok) {
+          throw new Error("animal facts responded with status " + response.status);
+        }
+        return response.json();
+      })
+      .then(function (fact) {
+        render(element, fact);
+      })
+      .catch(function () {
+        // the fallback content of the blockquote stays
+      });
+  }
+
+  function loadAll() {
+    var elements = document.querySelectorAll("blockquote.animal-fact[data-fact-id]");
+    for (var i = 0; i < elements.length; i++) {
+      load(elements[i]);
+    }
+  }
+
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", loadAll);
+  } else {
+    loadAll();
+  }
+})();
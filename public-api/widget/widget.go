@@ -0,0 +1,77 @@
+package widget
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RandomFactID makes the widget load another random fact on every page view.
+	RandomFactID = "random"
+
+	DefaultWidth = 550
+)
+
+//go:embed static templates
+var files embed.FS
+
+var (
+	script          = mustReadFile("static/widget.js")
+	snippetTemplate = template.Must(template.ParseFS(files, "templates/snippet.html"))
+)
+
+func mustReadFile(name string) []byte {
+	content, err := files.ReadFile(name)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to read embedded file %s", name))
+	}
+
+	return content
+}
+
+// Script returns the JS widget, which renders the snippets of a page with the current fact.
+func Script() []byte {
+	return script
+}
+
+// Snippet is the embeddable HTML of a fact. The fact is part of the HTML, so that it is shown even if the script is
+// blocked, the script replaces it with the current fact.
+type Snippet struct {
+	// FactID is the ID the widget loads, RandomFactID for a random fact.
+	FactID string
+	Fact   string
+	Source string
+	// FactURL is linked in the attribution.
+	FactURL string
+	// ScriptURL is the URL the widget script is served at.
+	ScriptURL string
+	Width     int
+}
+
+// SourceURL returns the source if it is an absolute http(s) URL, other sources are not linked.
+func (s Snippet) SourceURL() string {
+	sourceURL, err := url.Parse(s.Source)
+	if err != nil || (sourceURL.Scheme != "https" && sourceURL.Scheme != "http") || sourceURL.Host == "" {
+		return ""
+	}
+
+	return sourceURL.String()
+}
+
+// Render returns the HTML of the snippet, all values are escaped.
+func (s Snippet) Render() (string, error) {
+	if s.Width <= 0 {
+		s.Width = DefaultWidth
+	}
+
+	var html bytes.Buffer
+	if err := snippetTemplate.Execute(&html, s); err != nil {
+		return "", errors.Wrap(err, "failed to render embed snippet")
+	}
+
+	return html.String(), nil
+}
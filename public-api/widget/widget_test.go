@@ -0,0 +1,74 @@
+package widget
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnippet_Render(t *testing.T) {
+	tests := []struct {
+		name        string
+		snippet     Snippet
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "fact with source url",
+			snippet: Snippet{
+				FactID:    "6578bf140e487ecc049c7594",
+				Fact:      "The Blue Whale is the largest animal that has ever lived.",
+				Source:    "https://factanimal.com/blue-whale/",
+				FactURL:   "https://animal-facts.cafo.dev/api/v1/facts/6578bf140e487ecc049c7594",
+				ScriptURL: "https://animal-facts.cafo.dev/api/v1/embed/widget.js",
+			},
+			contains: []string{
+				`data-fact-id="6578bf140e487ecc049c7594"`,
+				`max-width:550px`,
+				`<p style="margin:0 0 .5em">The Blue Whale is the largest animal that has ever lived.</p>`,
+				`<a href="https://factanimal.com/blue-whale/" rel="nofollow noopener" target="_blank">https://factanimal.com/blue-whale/</a>`,
+				`via <a href="https://animal-facts.cafo.dev/api/v1/facts/6578bf140e487ecc049c7594" target="_blank">Animal Facts</a>`,
+				`<script async src="https://animal-facts.cafo.dev/api/v1/embed/widget.js" charset="utf-8"></script>`,
+			},
+		},
+		{
+			name:     "source that is no url is not linked",
+			snippet:  Snippet{FactID: RandomFactID, Fact: "Cats purr.", Source: "javascript:alert(1)", Width: 300},
+			contains: []string{`data-fact-id="random"`, `max-width:300px`, `&mdash; javascript:alert(1) via`},
+			notContains: []string{
+				`href="javascript`,
+			},
+		},
+		{
+			name:        "html is escaped",
+			snippet:     Snippet{FactID: RandomFactID, Fact: `Cats & dogs <script>alert("x")</script>`},
+			contains:    []string{`Cats &amp; dogs &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;`},
+			notContains: []string{`&mdash;`, `<script>alert`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.snippet.Render()
+			if err != nil {
+				t.Fatalf("Render() unexpected error = %v", err)
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %s, want it to contain %s", got, want)
+				}
+			}
+			for _, notWant := range tt.notContains {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Render() = %s, want it not to contain %s", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestScript(t *testing.T) {
+	script := string(Script())
+	if !strings.Contains(script, `blockquote.animal-fact[data-fact-id]`) || !strings.Contains(script, `textContent`) {
+		t.Errorf("Script() doesn't render the snippets")
+	}
+}
@@ -0,0 +1,14 @@
+package animalfacts
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed version.txt
+var version string
+
+// Version returns the version of the APIs from version.txt, which is updated with every release.
+func Version() string {
+	return strings.TrimSpace(version)
+}